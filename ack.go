@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// PublishAcked publishes val on the event scope like PublishToScope, and returns a channel that
+// receives the number of subscribers whose receiver took the value, once every subscriber it was
+// delivered to has either taken it or stopped receiving, however long that takes. The channel
+// receives exactly one count and is then closed. It is buffered, so the count is not lost if the
+// caller stops waiting for it. If the publish fails, the count is zero.
+//
+// Deliveries abandoned because ctx was canceled are not counted. A subscriber that never receives
+// and never unsubscribes holds back the count indefinitely.
+func PublishAcked[T any](ctx context.Context, scope *EventScope, val T) <-chan int {
+	acks := &ackCounter{result: make(chan int, 1)}
+	if scope == nil {
+		acks.expect(0)
+		return acks.result
+	}
+
+	msg := scope.newMessage(ctx, val)
+	msg.acks = acks
+	if err := scope.publish(ctx, eventTypeOf[T](), msg); err != nil {
+		acks.expect(0)
+	}
+	return acks.result
+}
+
+// ackCounter counts the subscribers that received a message published with PublishAcked. A nil
+// ackCounter counts nothing.
+type ackCounter struct {
+	remaining atomic.Int64
+	received  atomic.Int64
+	result    chan int
+}
+
+// expect sets the number of deliveries made of the message. It must be called before any of them
+// settles, and exactly once.
+func (a *ackCounter) expect(n int) {
+	if a == nil {
+		return
+	}
+	if n == 0 {
+		a.finish()
+		return
+	}
+	a.remaining.Store(int64(n))
+}
+
+// receive counts a delivery taken by its receiver, before it settles.
+func (a *ackCounter) receive() {
+	if a != nil {
+		a.received.Add(1)
+	}
+}
+
+// settle counts a delivery as done with, and reports the count once every delivery is.
+func (a *ackCounter) settle() {
+	if a != nil && a.remaining.Add(-1) == 0 {
+		a.finish()
+	}
+}
+
+func (a *ackCounter) finish() {
+	a.result <- int(a.received.Load())
+	close(a.result)
+}
@@ -0,0 +1,45 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishAcked(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	assert.Equal(t, 0, <-PublishAcked(ctx, testScope, 0))
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	defer unsubFirst()
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubSecond()
+	_, unsubIdle := SubscribeToScope[int](ctx, testScope)
+
+	acked := PublishAcked(ctx, testScope, 1)
+	assert.Equal(t, 1, <-first)
+	assert.Equal(t, 1, <-second)
+	select {
+	case n := <-acked:
+		t.Fatalf("acknowledged %d receivers while one has not received yet", n)
+	default:
+	}
+
+	// A subscriber that stops receiving is not counted.
+	unsubIdle()
+	assert.Equal(t, 2, <-acked)
+	_, ok := <-acked
+	assert.False(t, ok)
+}
+
+func TestPublishAcked_Failed(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, 0, <-PublishAcked[int](ctx, nil, 1))
+
+	testScope := NewEventScope()
+	require.NoError(t, testScope.Close())
+	assert.Equal(t, 0, <-PublishAcked(ctx, testScope, 1))
+}
@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+)
+
+// ErrUnauthorized is returned when the identity attached to a context is not allowed to publish
+// a type on an event scope.
+var ErrUnauthorized = errors.New("pubsub: unauthorized")
+
+// AllowPublish allows the listed identities to publish types whose name matches typeFilter.
+// Type names are formatted like reflect.Type.String, for example "mypkg.UserEvent". The filter
+// uses path.Match syntax, and a filter of "*" matches every type.
+//
+// Access control is opt in: types that are not matched by any rule can be published by anyone,
+// while types matched by at least one rule can only be published by the identities those rules
+// list.
+func (e *EventScope) AllowPublish(typeFilter string, identities ...string) {
+	e.acl.add(&e.acl.publish, typeFilter, identities)
+}
+
+// AllowSubscribe allows the listed identities to subscribe to types whose name matches
+// typeFilter. It follows the same rules as AllowPublish.
+func (e *EventScope) AllowSubscribe(typeFilter string, identities ...string) {
+	e.acl.add(&e.acl.subscribe, typeFilter, identities)
+}
+
+type aclRule struct {
+	filter     string
+	identities map[string]bool
+}
+
+func (r aclRule) matches(typeName string) bool {
+	if r.filter == "*" {
+		return true
+	}
+	ok, _ := path.Match(r.filter, typeName)
+	return ok
+}
+
+type accessList struct {
+	mu        sync.RWMutex
+	publish   []aclRule
+	subscribe []aclRule
+}
+
+func (a *accessList) add(rules *[]aclRule, typeFilter string, identities []string) {
+	rule := aclRule{
+		filter:     typeFilter,
+		identities: make(map[string]bool, len(identities)),
+	}
+	for _, id := range identities {
+		rule.identities[id] = true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	*rules = append(*rules, rule)
+}
+
+// allowPublish reports whether the identity on ctx may publish the named type. The name is
+// computed lazily since most scopes have no rules.
+func (a *accessList) allowPublish(ctx context.Context, name func() string) bool {
+	return a.allowed(ctx, &a.publish, name)
+}
+
+func (a *accessList) allowSubscribe(ctx context.Context, name func() string) bool {
+	return a.allowed(ctx, &a.subscribe, name)
+}
+
+func (a *accessList) allowed(ctx context.Context, list *[]aclRule, name func() string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	rules := *list
+	if len(rules) == 0 {
+		return true
+	}
+
+	typeName := name()
+	identity, hasIdentity := IdentityFromContext(ctx)
+	matched := false
+	for _, rule := range rules {
+		if !rule.matches(typeName) {
+			continue
+		}
+		matched = true
+		if hasIdentity && rule.identities[identity] {
+			return true
+		}
+	}
+	return !matched
+}
@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type aclEvent struct {
+	ID int
+}
+
+func TestACL_Publish(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowPublish("pubsub.aclEvent", "orders")
+
+	testingCh, unsub := SubscribeToScope[aclEvent](ctx, testScope)
+	defer unsub()
+
+	err := PublishToScope(ctx, testScope, aclEvent{ID: 1})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	err = PublishToScope(WithIdentity(ctx, "billing"), testScope, aclEvent{ID: 2})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	err = PublishToScope(WithIdentity(ctx, "orders"), testScope, aclEvent{ID: 3})
+	assert.NoError(t, err)
+
+	val := <-testingCh
+	assert.Equal(t, 3, val.ID)
+
+	// Types without a matching rule are unrestricted.
+	assert.NoError(t, PublishToScope(ctx, testScope, 42))
+}
+
+func TestACL_Wildcard(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowPublish("*", "admin")
+	testScope.AllowPublish("pubsub.acl*", "orders")
+
+	assert.ErrorIs(t, PublishToScope(WithIdentity(ctx, "orders"), testScope, 42), ErrUnauthorized)
+	assert.NoError(t, PublishToScope(WithIdentity(ctx, "orders"), testScope, aclEvent{}))
+	assert.NoError(t, PublishToScope(WithIdentity(ctx, "admin"), testScope, 42))
+	assert.NoError(t, PublishToScope(WithIdentity(ctx, "admin"), testScope, aclEvent{}))
+}
+
+func TestACL_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowSubscribe("pubsub.aclEvent", "auditor")
+
+	deniedCh, unsub := SubscribeToScope[aclEvent](WithIdentity(ctx, "intruder"), testScope)
+	defer unsub()
+	_, ok := <-deniedCh
+	assert.False(t, ok)
+
+	allowedCh, unsub := SubscribeToScope[aclEvent](WithIdentity(ctx, "auditor"), testScope)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, aclEvent{ID: 7})
+	val, ok := <-allowedCh
+	assert.True(t, ok)
+	assert.Equal(t, 7, val.ID)
+}
+
+func TestACL_SubscribeAll(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowSubscribe("pubsub.aclEvent", "auditor")
+
+	deniedCh, unsubDenied := subscribeAll(WithIdentity(ctx, "intruder"), testScope)
+	defer unsubDenied()
+	allowedCh, unsubAllowed := subscribeAll(WithIdentity(ctx, "auditor"), testScope, WithOrdered())
+	defer unsubAllowed()
+
+	PublishToScope(ctx, testScope, aclEvent{ID: 7})
+	PublishToScope(ctx, testScope, 42)
+
+	assert.Equal(t, 42, (<-deniedCh).val)
+	assert.Equal(t, aclEvent{ID: 7}, (<-allowedCh).val)
+	assert.Equal(t, 42, (<-allowedCh).val)
+}
+
+func TestACL_Bridge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	left, right := newPipe()
+	scopeA := NewEventScope()
+	scopeB := NewEventScope()
+	scopeB.AllowPublish("pubsub.bridgeEvent", "trusted")
+
+	bridgeA := NewBridge(scopeA, left)
+	defer BridgeType[bridgeEvent](ctx, bridgeA)()
+	bridgeB := NewBridge(scopeB, right)
+	defer BridgeType[bridgeEvent](ctx, bridgeB)()
+
+	// Messages received by a bridge are published with the identity attached to its context.
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		bridgeB.Run(runCtx)
+		close(done)
+	}()
+
+	chB, unsubB := SubscribeToScope[bridgeEvent](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "untrusted"})
+	select {
+	case val := <-chB:
+		t.Fatalf("received unauthorized event %v", val)
+	case <-time.After(50 * time.Millisecond):
+	}
+	stop()
+	<-done
+
+	go bridgeB.Run(WithIdentity(ctx, "trusted"))
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "trusted"})
+	assert.Equal(t, "trusted", (<-chB).Msg)
+}
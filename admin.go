@@ -0,0 +1,277 @@
+package pubsub
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AdminHandler returns an http.Handler exposing a REST API to inspect and operate the scope at
+// runtime:
+//
+//	GET    /types                            list the types that have been subscribed to
+//	GET    /types/{type}/subscribers         list the IDs of the subscribers of a type
+//	DELETE /types/{type}/subscribers/{id}    unsubscribe a subscriber
+//	POST   /types/{type}/publish             publish the JSON request body as a value of the type
+//	GET    /types/{type}/events              stream the values published to the type
+//	GET    /stats                            return the scope's ScopeStats
+//
+// Types are identified by their name, path escaped, as used by bridges. Values published through
+// the API are decoded with JSONCodec regardless of the scope's codec, and streamed values are
+// encoded as JSON server-sent events, one per value, gzip compressed if the client sends an
+// Accept-Encoding header accepting gzip. Both are subject to the scope's access rules
+// with the identity attached to the request context. The handler is meant for operators and
+// should not be exposed without authentication.
+func (e *EventScope) AdminHandler() http.Handler {
+	return http.HandlerFunc(e.serveAdmin)
+}
+
+func (e *EventScope) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		s, err := url.PathUnescape(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		segments = append(segments, s)
+	}
+
+	switch {
+	case len(segments) == 1 && segments[0] == "stats":
+		if allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, e.Stats())
+		}
+	case len(segments) == 1 && segments[0] == "types":
+		if allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, e.typeNames())
+		}
+	case len(segments) >= 3 && segments[0] == "types":
+		typ, ok := e.types()[segments[1]]
+		if !ok {
+			http.Error(w, "unknown type", http.StatusNotFound)
+			return
+		}
+		e.serveAdminType(w, r, typ, segments[2:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (e *EventScope) serveAdminType(w http.ResponseWriter, r *http.Request, typ reflect.Type, segments []string) {
+	switch {
+	case len(segments) == 1 && segments[0] == "subscribers":
+		if allowMethod(w, r, http.MethodGet) {
+			ids := []string{}
+			for _, sub := range e.subscribersOf(typ) {
+				ids = append(ids, sub.id.String())
+			}
+			sort.Strings(ids)
+			writeJSON(w, ids)
+		}
+	case len(segments) == 2 && segments[0] == "subscribers":
+		if !allowMethod(w, r, http.MethodDelete) {
+			return
+		}
+		id, err := uuid.Parse(segments[1])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !e.unsubscribe(typ, id) {
+			http.Error(w, "unknown subscriber", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 1 && segments[0] == "publish":
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		t := eventTypeFor(typ)
+		val, err := t.decode(JSONCodec{}, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Deliveries are abandoned once their context is canceled, which happens to the request
+		// context as soon as the handler returns.
+		ctx := context.WithoutCancel(r.Context())
+		err = e.publish(ctx, t, e.newMessage(ctx, val))
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrScopeClosed):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case len(segments) == 1 && segments[0] == "events":
+		if allowMethod(w, r, http.MethodGet) {
+			e.streamEvents(w, r, typ)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// streamEvents sends the values published to typ as server-sent events until the request is
+// canceled. The stream is gzip compressed if the client accepts it.
+func (e *EventScope) streamEvents(w http.ResponseWriter, r *http.Request, typ reflect.Type) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	t := eventTypeFor(typ)
+	if !e.acl.allowSubscribe(r.Context(), t.name) {
+		http.Error(w, ErrUnauthorized.Error(), http.StatusForbidden)
+		return
+	}
+
+	ch, unsub := subscribeKey(r.Context(), e, t, func(val any, _ message) any { return val })
+	defer unsub()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Add("Vary", "Accept-Encoding")
+	var out io.Writer = w
+	flush := flusher.Flush
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+		flush = func() {
+			// Every event is flushed out of the compressor so that it reaches the client
+			// right away.
+			gz.Flush()
+			flusher.Flush()
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	flush()
+
+	for val := range ch {
+		data, err := json.Marshal(val)
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flush()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of r accepts gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if strings.TrimSpace(name) != "gzip" {
+				continue
+			}
+			// A weight of 0 refuses the coding.
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+			return q > 0
+		}
+	}
+	return false
+}
+
+// types returns the types that have been subscribed to on the scope, by name.
+func (e *EventScope) types() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	e.knownTopics().Range(func(_, value any) bool {
+		if t := value.(*topic).t; t.typ != nil {
+			types[t.name()] = t.typ
+		}
+		return true
+	})
+	return types
+}
+
+func (e *EventScope) typeNames() []string {
+	names := []string{}
+	for name := range e.types() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// topicsOf returns the topics of typ: the one of its regular subscribers, and the ones of its
+// sticky subscribers and consistent hashing groups.
+func (e *EventScope) topicsOf(typ reflect.Type) []*topic {
+	var topics []*topic
+	e.knownTopics().Range(func(_, value any) bool {
+		if top := value.(*topic); top.t.typ == typ {
+			topics = append(topics, top)
+		}
+		return true
+	})
+	return topics
+}
+
+// subscribersOf returns the subscribers registered for typ, including its sticky subscribers and
+// the members of its consistent hashing groups.
+func (e *EventScope) subscribersOf(typ reflect.Type) []*subscriber {
+	var subs []*subscriber
+	for _, top := range e.topicsOf(typ) {
+		top.subs.Range(func(_, value any) bool {
+			subs = append(subs, value.(*subscriber))
+			return true
+		})
+	}
+	return subs
+}
+
+// unsubscribe ends the subscription of the subscriber of typ with the given id, as if its
+// UnsubFn had been called. It reports whether the subscriber was found.
+func (e *EventScope) unsubscribe(typ reflect.Type, id uuid.UUID) bool {
+	for _, top := range e.topicsOf(typ) {
+		if sub, ok := top.subs.Load(id); ok {
+			top.remove(id)
+			sub.(*subscriber).cancel()
+			return true
+		}
+	}
+	return false
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,156 @@
+package pubsub
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminEvent struct {
+	X int `json:"x"`
+}
+
+func adminRequest(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestAdminHandler(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	h := testScope.AdminHandler()
+
+	events, unsub := SubscribeToScope[adminEvent](ctx, testScope)
+	defer unsub()
+	_, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+
+	rec := adminRequest(t, h, http.MethodGet, "/types", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `["int", "pubsub.adminEvent"]`, rec.Body.String())
+
+	typePath := "/types/" + url.PathEscape("pubsub.adminEvent")
+	rec = adminRequest(t, h, http.MethodPost, typePath+"/publish", `{"x":1}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, adminEvent{X: 1}, <-events)
+
+	rec = adminRequest(t, h, http.MethodGet, typePath+"/subscribers", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var ids []string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ids))
+	require.Len(t, ids, 1)
+
+	rec = adminRequest(t, h, http.MethodGet, "/stats", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var stats ScopeStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 2, stats.Subscribers)
+	assert.Equal(t, int64(1), stats.Published)
+
+	rec = adminRequest(t, h, http.MethodDelete, typePath+"/subscribers/"+ids[0], "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not ended")
+	}
+
+	rec = adminRequest(t, h, http.MethodDelete, typePath+"/subscribers/"+ids[0], "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminHandler_Errors(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowPublish("int", "publisher")
+	h := testScope.AdminHandler()
+
+	_, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	for _, tc := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{http.MethodGet, "/unknown", "", http.StatusNotFound},
+		{http.MethodPost, "/types", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/types/float64/subscribers", "", http.StatusNotFound},
+		{http.MethodGet, "/types/int/publish", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/types/int/publish", `"not an int"`, http.StatusBadRequest},
+		{http.MethodPost, "/types/int/publish", `1`, http.StatusForbidden},
+		{http.MethodDelete, "/types/int/subscribers/not-a-uuid", "", http.StatusBadRequest},
+	} {
+		rec := adminRequest(t, h, tc.method, tc.path, tc.body)
+		assert.Equal(t, tc.code, rec.Code, "%s %s", tc.method, tc.path)
+	}
+}
+
+func TestAdminHandler_Events(t *testing.T) {
+	for _, tc := range []struct {
+		name, acceptEncoding string
+		gzipped              bool
+	}{
+		{name: "Plain"},
+		{name: "Gzip", acceptEncoding: "br, gzip;q=0.5", gzipped: true},
+		{name: "GzipRefused", acceptEncoding: "gzip;q=0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			testScope := NewEventScope()
+			_, unsub := SubscribeToScope[int](ctx, testScope)
+			defer unsub()
+			server := httptest.NewServer(testScope.AdminHandler())
+			defer server.Close()
+			// The client must not ask for gzip on its own.
+			transport := &http.Transport{DisableCompression: true}
+			defer transport.CloseIdleConnections()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/types/int/events", nil)
+			require.NoError(t, err)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+			var body io.Reader = resp.Body
+			if tc.gzipped {
+				assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+				gz, err := gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+				body = gz
+			} else {
+				assert.Empty(t, resp.Header.Get("Content-Encoding"))
+			}
+
+			require.Eventually(t, func() bool { return testScope.Stats().Subscribers == 2 }, time.Second, time.Millisecond)
+			// Each event arrives as soon as it is published, without waiting for the stream to end.
+			lines := bufio.NewReader(body)
+			for i := 1; i <= 2; i++ {
+				PublishToScope(ctx, testScope, i)
+				for _, want := range []string{fmt.Sprintf("data: %d\n", i), "\n"} {
+					line, err := lines.ReadString('\n')
+					require.NoError(t, err)
+					assert.Equal(t, want, line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Aggregate folds every event published on an event scope into a state value, as in event
+// sourcing. Each event is identified by the sequence number the scope assigned to it when it
+// was published, and events are applied in that order.
+type Aggregate[S any] struct {
+	mu    sync.RWMutex
+	state S
+	seq   int64
+	apply func(S, any) S
+
+	unsub UnsubFn
+}
+
+// NewAggregate creates an aggregate starting from initial. Every event published on scope from
+// now on, of any type, is folded into the state with apply. Call Close to stop listening.
+func NewAggregate[S any](scope *EventScope, initial S, apply func(S, any) S) *Aggregate[S] {
+	return NewAggregateFromSnapshot(initial, 0, scope, apply)
+}
+
+// NewAggregateFromSnapshot creates an aggregate resuming from a state and sequence number
+// previously returned by Aggregate.Snapshot. Events with a sequence number less than or equal to
+// seqNum are assumed to be part of the snapshot and are skipped.
+//
+// Events published on scope after the snapshot was taken but before the aggregate was created
+// are replayed from the events the scope retains if it was created with WithReplay, and are
+// missed otherwise. If scope has not yet numbered as many events as seqNum, for example because
+// it was created after the snapshot was taken, its numbering continues after seqNum so that new
+// events are not mistaken for ones that are part of the snapshot.
+func NewAggregateFromSnapshot[S any](snapshot S, seqNum int64, scope *EventScope, apply func(S, any) S) *Aggregate[S] {
+	a := &Aggregate[S]{
+		state: snapshot,
+		seq:   seqNum,
+		apply: apply,
+	}
+
+	ctx := context.Background()
+	var replayed []message
+	restore := func(c *subscribeConfig) {
+		c.registered = func(*topic, *subscriber) {
+			if scope.seq.Load() < seqNum {
+				scope.seq.Store(seqNum)
+			}
+			if scope.replay != nil {
+				replayed = scope.replay.sinceAll(seqNum, func(t eventType) bool {
+					return scope.acl.allowSubscribe(ctx, t.name)
+				})
+			}
+		}
+	}
+
+	ch, unsub := subscribeAll(ctx, scope, WithOrdered(), restore)
+	a.unsub = unsub
+	go func() {
+		for _, msg := range replayed {
+			a.handle(msg)
+		}
+		for msg := range ch {
+			a.handle(msg)
+		}
+	}()
+
+	return a
+}
+
+func (a *Aggregate[S]) handle(msg message) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if msg.seq <= a.seq {
+		return
+	}
+	a.state = a.apply(a.state, msg.val)
+	a.seq = msg.seq
+}
+
+// State returns the current state of the aggregate.
+func (a *Aggregate[S]) State() S {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.state
+}
+
+// Snapshot returns the current state together with the sequence number of the last event that
+// has been applied to it. Every event numbered before it has been applied as well, unless its
+// delivery was abandoned. Both can be passed to NewAggregateFromSnapshot to resume the aggregate
+// later.
+func (a *Aggregate[S]) Snapshot() (S, int64) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.state, a.seq
+}
+
+// Close stops the aggregate from receiving further events.
+func (a *Aggregate[S]) Close() {
+	a.unsub()
+}
+
+// SubscribeAggregated subscribes to T on the event scope and combines the values received into
+// values of type A. Starting from initial, each value is folded into the current aggregate with
+// aggregate, in publish order, as with WithOrdered. Whenever trigger returns true for the result,
+// it is sent on the returned channel and the aggregate starts over from initial. Since initial is
+// reused, aggregate must not modify its first argument in place if A is a slice, map or pointer.
+//
+// Values folded into an aggregate that has not been sent yet are discarded when the subscription
+// ends. The channel is closed when the UnsubFn is called or ctx is canceled.
+func SubscribeAggregated[T, A any](ctx context.Context, scope *EventScope, aggregate func(A, T) A, trigger func(A) bool, initial A) (chan A, UnsubFn) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch, unsub := SubscribeToScope[T](ctx, scope, WithOrdered())
+	stop := func() {
+		cancel()
+		unsub()
+	}
+
+	out := make(chan A)
+	go func() {
+		defer close(out)
+		acc := initial
+		for val := range ch {
+			acc = aggregate(acc, val)
+			if !trigger(acc) {
+				continue
+			}
+			select {
+			case out <- acc:
+			case <-ctx.Done():
+				stop()
+				return
+			}
+			acc = initial
+		}
+	}()
+	return out, stop
+}
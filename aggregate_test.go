@@ -0,0 +1,163 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deposit struct {
+	Amount int
+}
+
+type withdrawal struct {
+	Amount int
+}
+
+func applyBalance(balance int, event any) int {
+	switch ev := event.(type) {
+	case deposit:
+		return balance + ev.Amount
+	case withdrawal:
+		return balance - ev.Amount
+	}
+	return balance
+}
+
+func TestAggregate(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	agg := NewAggregate(testScope, 0, applyBalance)
+	defer agg.Close()
+
+	PublishToScope(ctx, testScope, deposit{Amount: 100})
+	PublishToScope(ctx, testScope, withdrawal{Amount: 30})
+	PublishToScope(ctx, testScope, "ignored")
+
+	assert.Eventually(t, func() bool {
+		_, seq := agg.Snapshot()
+		return seq == 3
+	}, time.Second, time.Millisecond)
+
+	state, _ := agg.Snapshot()
+	assert.Equal(t, 70, state)
+	assert.Equal(t, 70, agg.State())
+}
+
+func TestAggregate_Order(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	agg := NewAggregate(testScope, "", func(s string, event any) string {
+		return s + event.(string)
+	})
+	defer agg.Close()
+
+	for _, s := range []string{"a", "b", "c", "d"} {
+		PublishToScope(ctx, testScope, s)
+	}
+
+	assert.Eventually(t, func() bool {
+		_, seq := agg.Snapshot()
+		return seq == 4
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "abcd", agg.State())
+}
+
+func TestAggregate_FromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithReplay(0))
+
+	agg := NewAggregate(testScope, 0, applyBalance)
+	PublishToScope(ctx, testScope, deposit{Amount: 100})
+	PublishToScope(ctx, testScope, withdrawal{Amount: 30})
+	assert.Eventually(t, func() bool {
+		_, seq := agg.Snapshot()
+		return seq == 2
+	}, time.Second, time.Millisecond)
+	state, seq := agg.Snapshot()
+	agg.Close()
+
+	// The event published while no aggregate was running is replayed from the scope.
+	PublishToScope(ctx, testScope, deposit{Amount: 5})
+
+	restored := NewAggregateFromSnapshot(state, seq, testScope, applyBalance)
+	defer restored.Close()
+	PublishToScope(ctx, testScope, deposit{Amount: 1})
+
+	assert.Eventually(t, func() bool {
+		_, seq := restored.Snapshot()
+		return seq == 4
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 76, restored.State())
+}
+
+func TestAggregate_FromSnapshotNewScope(t *testing.T) {
+	ctx := context.Background()
+
+	// Events 1 and 2 are part of a snapshot taken on another scope. The new scope continues
+	// numbering after them, so none of its events are skipped.
+	testScope := NewEventScope()
+	agg := NewAggregateFromSnapshot(50, 2, testScope, applyBalance)
+	defer agg.Close()
+
+	PublishToScope(ctx, testScope, deposit{Amount: 1000})
+	PublishToScope(ctx, testScope, deposit{Amount: 1000})
+	PublishToScope(ctx, testScope, deposit{Amount: 5})
+
+	assert.Eventually(t, func() bool {
+		_, seq := agg.Snapshot()
+		return seq == 5
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 2055, agg.State())
+}
+
+func TestSubscribeAggregated(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	// Sum values in batches that reach at least 10.
+	sums, unsub := SubscribeAggregated(ctx, testScope, func(sum, v int) int { return sum + v }, func(sum int) bool { return sum >= 10 }, 0)
+	for _, v := range []int{3, 4, 5, 10, 1, 2} {
+		PublishToScope(ctx, testScope, v)
+	}
+
+	var got []int
+	for len(got) < 2 {
+		select {
+		case sum := <-sums:
+			got = append(got, sum)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for aggregates")
+		}
+	}
+	assert.Equal(t, []int{12, 10}, got)
+
+	unsub()
+	_, ok := <-sums
+	assert.False(t, ok)
+}
+
+func TestSubscribeAggregated_Batches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testScope := NewEventScope()
+
+	batches, _ := SubscribeAggregated(ctx, testScope, func(batch []string, s string) []string {
+		return append(batch[:len(batch):len(batch)], s)
+	}, func(batch []string) bool { return len(batch) == 2 }, nil)
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		PublishToScope(ctx, testScope, s)
+	}
+
+	assert.Equal(t, []string{"a", "b"}, <-batches)
+	assert.Equal(t, []string{"c", "d"}, <-batches)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, ok := <-batches
+		return !ok
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,42 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+)
+
+// AuditRecord describes a value published on an audited event scope.
+type AuditRecord struct {
+	// TypeName is the name of the type the value was published as, as used by bridges.
+	TypeName string
+	Value    any
+
+	PublishedAt time.Time
+
+	// PublisherID is the identity attached to the publish context, if any.
+	PublisherID string
+}
+
+// Audit publishes an AuditRecord on auditScope for every value published on the event scope from
+// now on, whatever its type, in publish order. Records are published by a subscription of their
+// own, so auditing does not change how values are delivered to the other subscribers. AuditRecord
+// values are not audited themselves, so auditScope may be the audited scope. If the scope has
+// access rules, only the types that may be subscribed to without an identity are audited. The
+// returned function stops auditing.
+func (e *EventScope) Audit(auditScope *EventScope) (cancel func()) {
+	ch, unsub := subscribeAll(context.Background(), e, WithOrdered())
+	go func() {
+		for msg := range ch {
+			if _, ok := msg.val.(AuditRecord); ok {
+				continue
+			}
+			PublishToScope(context.Background(), auditScope, AuditRecord{
+				TypeName:    msg.typeName,
+				Value:       msg.val,
+				PublishedAt: msg.publishedAt,
+				PublisherID: msg.identity,
+			})
+		}
+	}()
+	return unsub
+}
@@ -0,0 +1,60 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	auditScope := NewEventScope()
+
+	records, unsub := SubscribeToScope[AuditRecord](ctx, auditScope, WithOrdered())
+	defer unsub()
+	stop := testScope.Audit(auditScope)
+
+	before := time.Now()
+	require.NoError(t, PublishToScope(WithIdentity(ctx, "alice"), testScope, "hello"))
+	require.NoError(t, PublishToScope(ctx, testScope, deposit{Amount: 5}))
+
+	first := <-records
+	assert.Equal(t, "string", first.TypeName)
+	assert.Equal(t, "hello", first.Value)
+	assert.Equal(t, "alice", first.PublisherID)
+	assert.False(t, first.PublishedAt.Before(before))
+
+	second := <-records
+	assert.Equal(t, "pubsub.deposit", second.TypeName)
+	assert.Equal(t, deposit{Amount: 5}, second.Value)
+	assert.Empty(t, second.PublisherID)
+
+	stop()
+	require.NoError(t, PublishToScope(ctx, testScope, "after"))
+	select {
+	case r := <-records:
+		t.Fatalf("unexpected record after the audit was stopped: %+v", r)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestAudit_SameScope(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	records, unsub := SubscribeToScope[AuditRecord](ctx, testScope)
+	defer unsub()
+	defer testScope.Audit(testScope)()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.Equal(t, 1, (<-records).Value)
+	select {
+	case r := <-records:
+		t.Fatalf("audit record was audited: %+v", r)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
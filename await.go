@@ -0,0 +1,91 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+)
+
+// Await subscribes to T on the event scope and returns a channel that receives exactly one
+// Result before being closed. The Result holds the first value of T published after Await is
+// called, or ctx.Err() if the context is canceled first. The returned channel is buffered, so
+// the result is not lost if the caller stops waiting for it.
+func Await[T any](ctx context.Context, e *EventScope) <-chan Result[T] {
+	result := make(chan Result[T], 1)
+	ch, unsub := SubscribeToScope[T](ctx, e)
+
+	go func() {
+		defer close(result)
+		defer unsub()
+
+		select {
+		case val, ok := <-ch:
+			if !ok {
+				result <- Result[T]{Err: ctx.Err()}
+				return
+			}
+			result <- Result[T]{Value: val}
+		case <-ctx.Done():
+			result <- Result[T]{Err: ctx.Err()}
+		}
+	}()
+
+	return result
+}
+
+// SubscribeWithTimeout waits for the first value of T published on the event scope after it is
+// called and returns it, unsubscribing as soon as it arrives. If timeout elapses first, it returns
+// context.DeadlineExceeded, and if ctx is done first, ctx.Err(). If the subscription cannot be
+// made, it returns ErrNilScope, ErrScopeClosed or ErrUnauthorized.
+func SubscribeWithTimeout[T any](ctx context.Context, scope *EventScope, timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ch, unsub := SubscribeToScope[T](ctx, scope)
+	defer unsub()
+
+	var zero T
+	select {
+	case val, ok := <-ch:
+		if ok {
+			return val, nil
+		}
+	case <-ctx.Done():
+	}
+	return zero, subscriptionErr(ctx, scope)
+}
+
+// subscriptionErr returns why a subscription to scope made with ctx ended, or could not be made.
+func subscriptionErr(ctx context.Context, scope *EventScope) error {
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case scope == nil:
+		return ErrNilScope
+	case scope.closed.Load():
+		return ErrScopeClosed
+	default:
+		return ErrUnauthorized
+	}
+}
+
+// SubscribeUntil subscribes to T on the event scope like SubscribeToScope, and ends the
+// subscription once the scope's clock reaches deadline, even if ctx is not canceled. The channel
+// is closed at the deadline as if the UnsubFn had been called. Calling the UnsubFn before the
+// deadline, or canceling ctx, ends the subscription and stops the timer set for the deadline.
+func SubscribeUntil[T any](ctx context.Context, scope *EventScope, deadline time.Time) (chan T, UnsubFn) {
+	if scope == nil {
+		return SubscribeToScope[T](ctx, scope)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ch, unsub := SubscribeToScope[T](ctx, scope)
+
+	stopTimer := scope.clock.AfterFunc(deadline.Sub(scope.clock.Now()), func() {
+		cancel()
+		unsub()
+	})
+	context.AfterFunc(ctx, func() { stopTimer() })
+	return ch, func() {
+		stopTimer()
+		cancel()
+		unsub()
+	}
+}
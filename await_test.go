@@ -0,0 +1,137 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwait(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	future := Await[int](ctx, testScope)
+	PublishToScope(ctx, testScope, 42)
+
+	res, ok := <-future
+	assert.True(t, ok)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, 42, res.Value)
+
+	_, ok = <-future
+	assert.False(t, ok)
+}
+
+func TestAwait_CtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testScope := NewEventScope()
+
+	future := Await[int](ctx, testScope)
+	cancel()
+
+	res := <-future
+	assert.ErrorIs(t, res.Err, context.Canceled)
+	assert.Zero(t, res.Value)
+}
+
+func TestAwait_Select(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	intFuture := Await[int](ctx, testScope)
+	strFuture := Await[string](ctx, testScope)
+	PublishToScope(ctx, testScope, "first")
+
+	select {
+	case res := <-strFuture:
+		assert.Equal(t, "first", res.Value)
+	case <-intFuture:
+		t.Fatal("int future resolved without a publish")
+	}
+
+	PublishToScope(ctx, testScope, 1)
+	res := <-intFuture
+	assert.Equal(t, 1, res.Value)
+}
+
+func TestSubscribeWithTimeout(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	// Keep publishing until the value is received, since it is only seen once subscribed.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				PublishToScope(ctx, testScope, 42)
+			}
+		}
+	}()
+
+	val, err := SubscribeWithTimeout[int](ctx, testScope, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, val)
+}
+
+func TestSubscribeWithTimeout_Timeout(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	val, err := SubscribeWithTimeout[int](ctx, testScope, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, val)
+	assert.Zero(t, testScope.Stats().Subscribers)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = SubscribeWithTimeout[int](canceled, testScope, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, testScope.Close())
+	_, err = SubscribeWithTimeout[int](ctx, testScope, time.Hour)
+	assert.ErrorIs(t, err, ErrScopeClosed)
+}
+
+func TestSubscribeUntil(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	testingCh, unsub := SubscribeUntil[int](ctx, testScope, deadline)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 1)
+	assert.Equal(t, 1, <-testingCh)
+
+	select {
+	case _, ok := <-testingCh:
+		assert.False(t, ok)
+		assert.False(t, time.Now().Before(deadline))
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not end at the deadline")
+	}
+	assert.Zero(t, testScope.Stats().Subscribers)
+}
+
+func TestSubscribeUntil_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeUntil[int](ctx, testScope, time.Now().Add(time.Hour))
+	unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+
+	// A deadline that has already passed ends the subscription right away.
+	testingCh, unsub = SubscribeUntil[int](ctx, testScope, time.Now().Add(-time.Second))
+	defer unsub()
+	_, ok = <-testingCh
+	assert.False(t, ok)
+}
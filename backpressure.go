@@ -0,0 +1,47 @@
+package pubsub
+
+import "github.com/google/uuid"
+
+// DefaultHighWaterMark is the number of pending messages a subscriber may have before the
+// backpressure callback is called, unless another threshold is set with WithHighWaterMark.
+const DefaultHighWaterMark = 64
+
+// WithBackpressureCallback sets a function that is called whenever the number of messages
+// pending for a subscriber exceeds the scope's high water mark. A message is pending from the
+// moment it is published until the subscriber receives it from its channel. fn is called from
+// the publishing goroutine, once each time the backlog crosses the threshold, so publishers can
+// shed load before they are slowed down by the subscriber.
+func WithBackpressureCallback(fn func(subscriberID uuid.UUID, pending int)) EventScopeOption {
+	return func(e *EventScope) {
+		e.onBackpressure = fn
+	}
+}
+
+// WithHighWaterMark sets the number of pending messages a subscriber may have before the
+// backpressure callback is called. The default is DefaultHighWaterMark.
+func WithHighWaterMark(n int) EventScopeOption {
+	return func(e *EventScope) {
+		e.highWaterMark = n
+	}
+}
+
+// PendingCount returns the number of messages of type T published on the scope that subscribers
+// have not received yet, summed across every subscriber of T. The count is a snapshot, which
+// includes the message each subscriber may have in flight.
+func PendingCount[T any](e *EventScope) int {
+	v, ok := e.knownTopics().Load(eventTypeOf[T]().key)
+	if !ok {
+		return 0
+	}
+	return v.(*topic).pending()
+}
+
+// pending returns the number of messages pending across the topic's subscribers.
+func (t *topic) pending() int {
+	n := 0
+	t.subs.Range(func(_, value any) bool {
+		n += int(value.(*subscriber).pending.Load())
+		return true
+	})
+	return n
+}
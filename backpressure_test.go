@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackpressureCallback(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var calls []int
+	testScope := NewEventScope(
+		WithHighWaterMark(3),
+		WithBackpressureCallback(func(_ uuid.UUID, pending int) {
+			mu.Lock()
+			calls = append(calls, pending)
+			mu.Unlock()
+		}),
+	)
+
+	ch, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	mu.Lock()
+	assert.Empty(t, calls)
+	mu.Unlock()
+
+	PublishToScope(ctx, testScope, 3)
+	PublishToScope(ctx, testScope, 4)
+	mu.Lock()
+	assert.Equal(t, []int{4}, calls)
+	mu.Unlock()
+
+	// Once the backlog is drained, crossing the threshold again is reported again.
+	for i := 0; i < 5; i++ {
+		<-ch
+	}
+	assert.Eventually(t, func() bool { return PendingCount[int](testScope) == 0 }, time.Second, time.Millisecond)
+	for i := 0; i < 4; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	mu.Lock()
+	assert.Equal(t, []int{4, 4}, calls)
+	mu.Unlock()
+}
+
+func TestPendingCount(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	assert.Zero(t, PendingCount[int](testScope))
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	defer unsubFirst()
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope)
+	defer unsubSecond()
+
+	for i := 0; i < 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	PublishToScope(ctx, testScope, "other")
+	assert.Equal(t, 10, PendingCount[int](testScope))
+
+	for i := 0; i < 5; i++ {
+		<-first
+	}
+	<-second
+	assert.Eventually(t, func() bool { return PendingCount[int](testScope) == 4 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+)
+
+// SubscribeBatch subscribes to T on the event scope and delivers the values in batches, in
+// publish order, as with WithOrdered. A batch is sent once it holds maxSize values, or once
+// flushInterval has passed since its first value was received, whichever comes first, so no batch
+// is ever empty. The interval is measured with the scope's clock. maxSize and flushInterval must
+// be positive.
+//
+// If the scope is closed, the values collected so far are sent as a last batch before the channel
+// is closed. They are discarded if the UnsubFn is called or ctx is canceled.
+func SubscribeBatch[T any](ctx context.Context, scope *EventScope, maxSize int, flushInterval time.Duration) (chan []T, UnsubFn) {
+	if maxSize <= 0 {
+		panic("pubsub: batch size must be positive")
+	}
+	if flushInterval <= 0 {
+		panic("pubsub: batch flush interval must be positive")
+	}
+
+	var clock Clock = systemClock{}
+	if scope != nil {
+		clock = scope.clock
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ch, unsub := SubscribeToScope[T](ctx, scope, WithOrdered())
+	stop := func() {
+		cancel()
+		unsub()
+	}
+
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+
+		var batch []T
+		var flush <-chan time.Time
+		send := func() bool {
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				stop()
+				return false
+			}
+			batch, flush = nil, nil
+			return true
+		}
+
+		for {
+			select {
+			case val, ok := <-ch:
+				if !ok {
+					if len(batch) > 0 {
+						send()
+					}
+					return
+				}
+				if len(batch) == 0 {
+					flush = clock.After(flushInterval)
+				}
+				batch = append(batch, val)
+				if len(batch) == maxSize && !send() {
+					return
+				}
+			case <-flush:
+				if !send() {
+					return
+				}
+			}
+		}
+	}()
+	return out, stop
+}
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeBatch(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	batches, unsub := SubscribeBatch[int](ctx, testScope, 3, 20*time.Millisecond)
+	defer unsub()
+
+	for i := 0; i < 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	assert.Equal(t, []int{0, 1, 2}, <-batches)
+
+	// The rest is flushed once the interval has passed, and nothing is sent while idle.
+	start := time.Now()
+	assert.Equal(t, []int{3, 4}, <-batches)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	select {
+	case batch := <-batches:
+		t.Fatalf("received batch %v without publishing", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Closing the scope flushes what has been collected.
+	PublishToScope(ctx, testScope, 5)
+	require.Eventually(t, func() bool { return testScope.Len() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, testScope.Close())
+	assert.Equal(t, []int{5}, <-batches)
+	_, ok := <-batches
+	assert.False(t, ok)
+}
+
+func TestSubscribeBatch_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	batches, unsub := SubscribeBatch[int](ctx, testScope, 2, time.Hour)
+
+	PublishToScope(ctx, testScope, 1)
+	unsub()
+	_, ok := <-batches
+	assert.False(t, ok)
+}
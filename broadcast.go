@@ -0,0 +1,60 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// Broadcast publishes val to the subscribers of every type val can be received as: each type
+// subscribed to on the scope that the dynamic type of val is assignable to, such as the type of
+// val itself and the interface types it implements. This is useful for signals every subscriber
+// should see whatever it subscribed to, such as a shutdown notice.
+//
+// val is published once per matching type, in order of the types' names, as if PublishToScope
+// had been called with each of them, so subscribers to every type receive it once per type as
+// well. Types without a subscriber are skipped, and broadcasting a nil value is a no-op. The
+// errors of the individual publishes are joined together; ErrScopeClosed is returned if the
+// scope has been closed.
+func (e *EventScope) Broadcast(ctx context.Context, val any) error {
+	if e == nil {
+		return ErrNilScope
+	}
+	if e.closed.Load() {
+		return ErrScopeClosed
+	}
+	valType := reflect.TypeOf(val)
+	if valType == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, t := range e.broadcastTypes(valType) {
+		if err := e.publish(ctx, eventTypeFor(t), e.newMessage(ctx, val)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// broadcastTypes returns the types subscribed to on the scope that values of type valType can be
+// published as, sorted by name.
+func (e *EventScope) broadcastTypes(valType reflect.Type) []reflect.Type {
+	seen := make(map[reflect.Type]bool)
+	var types []reflect.Type
+	e.knownTopics().Range(func(_, v any) bool {
+		// Subscribers to every type, and the groups of a type, are reached through the type.
+		t := v.(*topic).t.typ
+		if t == nil || seen[t] {
+			return true
+		}
+		seen[t] = true
+		if valType.AssignableTo(t) {
+			types = append(types, t)
+		}
+		return true
+	})
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+	return types
+}
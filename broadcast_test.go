@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shutdownSignal struct{ Reason string }
+
+func (s shutdownSignal) String() string { return s.Reason }
+
+type stringer interface{ String() string }
+
+func TestBroadcast(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	signals, unsubSignals := SubscribeToScope[shutdownSignal](ctx, testScope)
+	defer unsubSignals()
+	stringers, unsubStringers := SubscribeToScope[stringer](ctx, testScope)
+	defer unsubStringers()
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+
+	require.NoError(t, testScope.Broadcast(ctx, shutdownSignal{Reason: "bye"}))
+	assert.Equal(t, shutdownSignal{Reason: "bye"}, <-signals)
+	assert.Equal(t, "bye", (<-stringers).String())
+	assert.Equal(t, int64(2), testScope.Stats().Published)
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.Equal(t, 1, <-ints)
+}
+
+func TestBroadcast_Nil(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	assert.NoError(t, testScope.Broadcast(ctx, nil))
+	assert.ErrorIs(t, (*EventScope)(nil).Broadcast(ctx, 1), ErrNilScope)
+
+	require.NoError(t, testScope.Close())
+	assert.ErrorIs(t, testScope.Broadcast(ctx, 1), ErrScopeClosed)
+}
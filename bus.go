@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// EventBus routes each type of event to an event scope of its own, so that publishers do not need
+// to know which scope a type lives on. Types without a route go to the bus's default scope.
+// Go methods cannot have type parameters, so routes are registered with Route and events
+// published with PublishToBus.
+type EventBus struct {
+	opts []EventScopeOption
+	def  *EventScope
+
+	mu     sync.RWMutex
+	scopes map[string]*EventScope
+	routes map[reflect.Type]*EventScope
+}
+
+// NewEventBus creates an event bus whose default scope and named scopes are created with opts.
+func NewEventBus(opts ...EventScopeOption) *EventBus {
+	return &EventBus{
+		opts:   opts,
+		def:    NewEventScope(opts...),
+		scopes: make(map[string]*EventScope),
+		routes: make(map[reflect.Type]*EventScope),
+	}
+}
+
+// Default returns the scope events of types without a route are published on.
+func (b *EventBus) Default() *EventScope {
+	return b.def
+}
+
+// Scope returns the scope the bus manages under name, creating it if it does not exist yet.
+func (b *EventBus) Scope(name string) *EventScope {
+	b.mu.RLock()
+	scope, ok := b.scopes[name]
+	b.mu.RUnlock()
+	if ok {
+		return scope
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if scope, ok := b.scopes[name]; ok {
+		return scope
+	}
+	scope = NewEventScope(b.opts...)
+	b.scopes[name] = scope
+	return scope
+}
+
+// Route sends every event of type T published on the bus to scope, replacing any earlier route
+// for T. scope does not have to be one of the bus's named scopes.
+func Route[T any](bus *EventBus, scope *EventScope) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.routes[typeOf[T]()] = scope
+}
+
+// ScopeOf returns the scope events of type T are published on, the one it was routed to or the
+// bus's default scope. Subscribers of T subscribe to it.
+func ScopeOf[T any](bus *EventBus) *EventScope {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	if scope, ok := bus.routes[typeOf[T]()]; ok {
+		return scope
+	}
+	return bus.def
+}
+
+// PublishToBus publishes val with PublishToScope on the scope returned by ScopeOf.
+func PublishToBus[T any](ctx context.Context, bus *EventBus, val T) error {
+	return PublishToScope(ctx, ScopeOf[T](bus), val)
+}
+
+// Close closes the default scope and every named scope of the bus, and joins the errors they
+// return. Scopes that were routed to without being named are left to their owners.
+func (b *EventBus) Close() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	errs := []error{b.def.Close()}
+	for _, scope := range b.scopes {
+		errs = append(errs, scope.Close())
+	}
+	return errors.Join(errs...)
+}
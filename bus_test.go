@@ -0,0 +1,38 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	ctx := context.Background()
+	bus := NewEventBus()
+	orders := bus.Scope("orders")
+	assert.Same(t, orders, bus.Scope("orders"))
+	assert.NotSame(t, orders, bus.Scope("users"))
+
+	Route[int](bus, orders)
+	assert.Same(t, orders, ScopeOf[int](bus))
+	assert.Same(t, bus.Default(), ScopeOf[string](bus))
+
+	ints, unsubInts := SubscribeToScope[int](ctx, orders)
+	defer unsubInts()
+	strs, unsubStrs := SubscribeToScope[string](ctx, bus.Default())
+	defer unsubStrs()
+	misrouted, unsubMisrouted := SubscribeToScope[int](ctx, bus.Default())
+	defer unsubMisrouted()
+
+	require.NoError(t, PublishToBus(ctx, bus, 1))
+	require.NoError(t, PublishToBus(ctx, bus, "default"))
+	assert.Equal(t, 1, <-ints)
+	assert.Equal(t, "default", <-strs)
+
+	require.NoError(t, bus.Close())
+	_, ok := <-misrouted
+	assert.False(t, ok)
+	assert.ErrorIs(t, PublishToBus(ctx, bus, 2), ErrScopeClosed)
+}
@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// NewChannelScope creates an event scope created with opts that delivers its messages through a
+// single channel shared by the whole scope, buffered to capacity, instead of starting a goroutine
+// for every message and subscriber. A fixed set of workers, one per CPU, reads the channel and
+// hands each message to its subscribers, which bounds the number of goroutines of high volume
+// scopes and keeps deliveries on goroutines that stay warm.
+//
+// A publish blocks until its deliveries fit in the channel, or its context is canceled, in which
+// case the deliveries are abandoned right away. Every worker is held by a subscriber that does not
+// receive its messages, so subscribers that publish on the scope from the goroutine they receive
+// on can stall it. The workers stop when the scope is closed.
+func NewChannelScope(capacity int, opts ...EventScopeOption) *EventScope {
+	if capacity < 0 {
+		panic("pubsub: channel scope capacity must not be negative")
+	}
+	e := NewEventScope(opts...)
+	e.channel = newDeliveryChannel(capacity, runtime.GOMAXPROCS(0))
+	return e
+}
+
+// deliveryChannel is the shared channel of a scope created with NewChannelScope, and the workers
+// reading it.
+type deliveryChannel struct {
+	tasks chan func()
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	// mu is held for reading while tasks are sent, so that tasks can be closed once closed
+	// is set under it.
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newDeliveryChannel(capacity, workers int) *deliveryChannel {
+	c := &deliveryChannel{
+		tasks: make(chan func(), capacity),
+		stop:  make(chan struct{}),
+	}
+	c.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer c.wg.Done()
+			for task := range c.tasks {
+				task()
+			}
+		}()
+	}
+	return c
+}
+
+// submit queues task for the workers. If ctx is canceled first, task is run right away, since
+// its deliveries give up on a canceled context. It reports false if the channel has been closed.
+func (c *deliveryChannel) submit(ctx context.Context, task func()) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return false
+	}
+
+	select {
+	case c.tasks <- task:
+	case <-ctx.Done():
+		task()
+	case <-c.stop:
+		return false
+	}
+	return true
+}
+
+// close stops the workers once the queued tasks have run. Tasks submitted concurrently run on
+// goroutines of their own.
+func (c *deliveryChannel) close() {
+	close(c.stop)
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	close(c.tasks)
+	c.wg.Wait()
+}
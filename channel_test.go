@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelScope(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewChannelScope(4)
+	defer testScope.Close()
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubFirst()
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubSecond()
+
+	const count = 100
+	go func() {
+		for i := 0; i < count; i++ {
+			PublishToScope(ctx, testScope, i)
+		}
+	}()
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, <-first)
+		assert.Equal(t, i, <-second)
+	}
+}
+
+func TestChannelScope_Bounded(t *testing.T) {
+	ctx := context.Background()
+	const capacity = 4
+	testScope := NewChannelScope(capacity)
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	// The subscriber does not receive yet, so publishing blocks once every worker is busy
+	// and the channel is full, rather than starting a goroutine for every message.
+	before := runtime.NumGoroutine()
+	total := capacity + runtime.GOMAXPROCS(0) + 10
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < total; i++ {
+			PublishToScope(ctx, testScope, i)
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	assert.Less(t, testScope.Stats().Published, int64(total))
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+1)
+
+	// A publish whose context is canceled gives up instead of waiting for room.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NoError(t, PublishToScope(canceled, testScope, -1))
+
+	received := 0
+	for received < total {
+		if <-testingCh >= 0 {
+			received++
+		}
+	}
+	<-published
+	assert.NoError(t, testScope.Close())
+	assert.ErrorIs(t, PublishToScope(ctx, testScope, 0), ErrScopeClosed)
+}
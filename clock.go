@@ -0,0 +1,65 @@
+package pubsub
+
+import "time"
+
+// Clock tells the time for the parts of an event scope that depend on it, such as the idle TTL of
+// a PartitionedScope and the publish time of audit records. The default is the system clock; the
+// pubsubtest package provides a clock that tests can advance by hand.
+type Clock interface {
+	Now() time.Time
+
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc calls f once d has elapsed, like time.AfterFunc. Calling stop cancels the call,
+	// and reports whether it did so before f was called, like time.Timer.Stop.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+
+	// NewTicker returns a ticker that receives the time every d.
+	NewTicker(d time.Duration) *Ticker
+}
+
+// Ticker delivers ticks of a Clock on C, like time.Ticker.
+type Ticker struct {
+	C <-chan time.Time
+
+	stop func()
+}
+
+// NewTicker creates a ticker that delivers the ticks sent on c and calls stop when it is stopped.
+// It is meant for Clock implementations.
+func NewTicker(c <-chan time.Time, stop func()) *Ticker {
+	return &Ticker{C: c, stop: stop}
+}
+
+// Stop turns off the ticker. No more ticks are sent on C after Stop returns.
+func (t *Ticker) Stop() {
+	t.stop()
+}
+
+// WithClock sets the clock the scope and the operators built on it tell the time with.
+func WithClock(c Clock) EventScopeOption {
+	return func(e *EventScope) {
+		e.clock = c
+	}
+}
+
+// systemClock is the Clock of the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+func (systemClock) NewTicker(d time.Duration) *Ticker {
+	t := time.NewTicker(d)
+	return NewTicker(t.C, t.Stop)
+}
@@ -0,0 +1,97 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrScopeClosed is returned when an event scope is used after Close has been called.
+var ErrScopeClosed = errors.New("pubsub: event scope closed")
+
+// Close closes the channel returned by Done, unsubscribes every subscriber of the event scope,
+// closing their channels, stops the workers of a scope created with NewChannelScope, and then
+// closes the plugins registered with UsePlugin in reverse order of registration. The errors
+// returned by the plugins are joined together. Last, if functions were registered with OnClose,
+// Close waits for the goroutines counted by ActiveGoroutines to exit and calls the functions.
+// Calling Close more than once has no effect.
+//
+// Subscriptions made after Close receive an already closed channel, and publishing and UsePlugin
+// return ErrScopeClosed. A publish that runs concurrently with Close either fails or is numbered
+// before the scope is closed, in which case it is delivered like any other publish that was in
+// flight when the subscribers were unsubscribed.
+func (e *EventScope) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		// Subscribers register under seqMu, so none can be added once the flag is set.
+		e.seqMu.Lock()
+		e.closed.Store(true)
+		e.seqMu.Unlock()
+		close(e.done)
+
+		e.knownTopics().Range(func(_, t any) bool {
+			top := t.(*topic)
+			top.subs.Range(func(id, sub any) bool {
+				top.remove(id.(uuid.UUID))
+				sub.(*subscriber).cancel()
+				return true
+			})
+			return true
+		})
+		if e.channel != nil {
+			e.channel.close()
+		}
+
+		e.pluginsMu.Lock()
+		plugins := e.plugins
+		e.plugins = nil
+		e.pluginsMu.Unlock()
+
+		var errs []error
+		for i := len(plugins) - 1; i >= 0; i-- {
+			if closeErr := plugins[i].Close(); closeErr != nil {
+				errs = append(errs, closeErr)
+			}
+		}
+		err = errors.Join(errs...)
+
+		e.onCloseMu.Lock()
+		fns := e.onClose
+		e.onClose = nil
+		e.onCloseDone = true
+		e.onCloseMu.Unlock()
+
+		if len(fns) > 0 {
+			e.WaitForQuiescence(context.Background())
+		}
+		for i := len(fns) - 1; i >= 0; i-- {
+			fns[i]()
+		}
+	})
+	return err
+}
+
+// OnClose registers fn to be called when the event scope is closed, once every subscriber has
+// been unsubscribed, its forwarding goroutine has exited and the scope's plugins have been
+// closed. It lets the code that creates a scope register its cleanup at once, rather than every
+// caller having to pair Close with it. The functions are called in reverse order of registration,
+// like deferred calls. If the scope has already been closed, fn is called right away.
+func (e *EventScope) OnClose(fn func()) {
+	e.onCloseMu.Lock()
+	if e.onCloseDone {
+		e.onCloseMu.Unlock()
+		fn()
+		return
+	}
+	e.onClose = append(e.onClose, fn)
+	e.onCloseMu.Unlock()
+}
+
+// Done returns a channel that is closed when the event scope is closed, like the Done channel of
+// a context.Context, so that goroutines holding the scope can stop once it is closed without
+// polling it. The channel is closed as soon as Close is called, before the subscribers are
+// unsubscribed, and is the same for every call.
+func (e *EventScope) Done() <-chan struct{} {
+	return e.done
+}
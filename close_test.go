@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnClose(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	var calls []string
+	testScope.OnClose(func() { calls = append(calls, "first") })
+	testScope.OnClose(func() {
+		assert.Zero(t, testScope.ActiveGoroutines())
+		assert.Zero(t, testScope.Stats().Subscribers)
+		calls = append(calls, "second")
+	})
+
+	testingCh, _ := SubscribeToScope[int](ctx, testScope)
+	require.NoError(t, testScope.Close())
+	require.NoError(t, testScope.Close())
+
+	assert.Equal(t, []string{"second", "first"}, calls)
+	_, ok := <-testingCh
+	assert.False(t, ok)
+}
+
+func TestOnClose_AfterClose(t *testing.T) {
+	testScope := NewEventScope()
+	require.NoError(t, testScope.Close())
+
+	called := false
+	testScope.OnClose(func() { called = true })
+	assert.True(t, called)
+}
+
+func TestDone(t *testing.T) {
+	testScope := NewEventScope()
+	done := testScope.Done()
+	assert.Equal(t, done, testScope.Done())
+
+	select {
+	case <-done:
+		t.Fatal("done before the scope was closed")
+	default:
+	}
+
+	require.NoError(t, testScope.Close())
+	<-done
+	require.NoError(t, testScope.Close())
+
+	lazy := NewLazyEventScope()
+	require.NoError(t, lazy.Close())
+	<-lazy.Done()
+}
@@ -0,0 +1,191 @@
+// Command pubsubctl inspects and operates a running pubsub.EventScope through the HTTP API
+// served by EventScope.AdminHandler.
+//
+// Usage:
+//
+//	pubsubctl [-addr url] list-types
+//	pubsubctl [-addr url] list-subscribers --type=T
+//	pubsubctl [-addr url] unsubscribe --type=T --id=ID
+//	pubsubctl [-addr url] publish --type=T --body=JSON
+//	pubsubctl [-addr url] tail --type=T
+//	pubsubctl [-addr url] stats
+//
+// Type names are formatted like reflect.Type.String, for example "mypkg.UserEvent".
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "pubsubctl:", err)
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage: pubsubctl [-addr url] list-types|list-subscribers|unsubscribe|publish|tail|stats [flags]")
+
+// run executes the command described by args, writing its output to out.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	global := flag.NewFlagSet("pubsubctl", flag.ContinueOnError)
+	addr := global.String("addr", "http://localhost:8080", "base URL of the admin API")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	if global.NArg() == 0 {
+		return errUsage
+	}
+
+	c := &client{base: strings.TrimSuffix(*addr, "/"), http: http.DefaultClient}
+	cmd, args := global.Arg(0), global.Args()[1:]
+
+	flags := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	typ := flags.String("type", "", "name of the event type")
+	id := flags.String("id", "", "ID of the subscriber")
+	body := flags.String("body", "", "JSON value to publish")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	needType := func() error {
+		if *typ == "" {
+			return fmt.Errorf("%s: --type is required", cmd)
+		}
+		return nil
+	}
+
+	switch cmd {
+	case "list-types":
+		var types []string
+		if err := c.getJSON(ctx, "/types", &types); err != nil {
+			return err
+		}
+		for _, t := range types {
+			fmt.Fprintln(out, t)
+		}
+		return nil
+	case "list-subscribers":
+		if err := needType(); err != nil {
+			return err
+		}
+		var ids []string
+		if err := c.getJSON(ctx, typePath(*typ, "subscribers"), &ids); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			fmt.Fprintln(out, id)
+		}
+		return nil
+	case "unsubscribe":
+		if err := needType(); err != nil {
+			return err
+		}
+		if *id == "" {
+			return fmt.Errorf("%s: --id is required", cmd)
+		}
+		return c.do(ctx, http.MethodDelete, typePath(*typ, "subscribers", *id), nil)
+	case "publish":
+		if err := needType(); err != nil {
+			return err
+		}
+		return c.do(ctx, http.MethodPost, typePath(*typ, "publish"), strings.NewReader(*body))
+	case "tail":
+		if err := needType(); err != nil {
+			return err
+		}
+		return c.tail(ctx, typePath(*typ, "events"), out)
+	case "stats":
+		var stats json.RawMessage
+		if err := c.getJSON(ctx, "/stats", &stats); err != nil {
+			return err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, stats, "", "  "); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, indented.String())
+		return nil
+	default:
+		return errUsage
+	}
+}
+
+// typePath returns the API path of a type's resource, escaping the type name.
+func typePath(typ string, elems ...string) string {
+	return "/types/" + url.PathEscape(typ) + "/" + strings.Join(elems, "/")
+}
+
+type client struct {
+	base string
+	http *http.Client
+}
+
+func (c *client) request(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func (c *client) do(ctx context.Context, method, path string, body io.Reader) error {
+	resp, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *client) getJSON(ctx context.Context, path string, v any) error {
+	resp, err := c.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// tail prints the data of every server-sent event received from path, one per line, until ctx
+// is canceled or the server ends the stream.
+func (c *client) tail(ctx context.Context, path string, out io.Writer) error {
+	resp, err := c.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			fmt.Fprintln(out, data)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
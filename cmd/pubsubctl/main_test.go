@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type event struct {
+	X int `json:"x"`
+}
+
+// syncBuffer is a bytes.Buffer that can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func runCommand(t *testing.T, args ...string) string {
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), args, &out))
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	scope := pubsub.NewEventScope()
+	server := httptest.NewServer(scope.AdminHandler())
+	defer server.Close()
+
+	events, unsub := pubsub.SubscribeToScope[event](ctx, scope)
+	defer unsub()
+
+	assert.Equal(t, "main.event\n", runCommand(t, "-addr", server.URL, "list-types"))
+
+	ids := strings.Fields(runCommand(t, "-addr", server.URL, "list-subscribers", "--type=main.event"))
+	assert.Len(t, ids, 1)
+
+	runCommand(t, "-addr", server.URL, "publish", "--type=main.event", `--body={"x":1}`)
+	assert.Equal(t, event{X: 1}, <-events)
+
+	assert.Contains(t, runCommand(t, "-addr", server.URL, "stats"), `"published_total": 1`)
+
+	runCommand(t, "-addr", server.URL, "unsubscribe", "--type=main.event", "--id="+ids[0])
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestRun_Tail(t *testing.T) {
+	ctx := context.Background()
+	scope := pubsub.NewEventScope()
+	server := httptest.NewServer(scope.AdminHandler())
+	defer server.Close()
+
+	// The type needs a subscriber to be known to the API.
+	_, unsub := pubsub.SubscribeToScope[event](ctx, scope, pubsub.WithOrdered())
+	defer unsub()
+
+	tailCtx, cancel := context.WithCancel(ctx)
+	var out syncBuffer
+	done := make(chan error)
+	go func() { done <- run(tailCtx, []string{"-addr", server.URL, "tail", "--type=main.event"}, &out) }()
+
+	// Wait for the tail to subscribe before publishing.
+	require.Eventually(t, func() bool {
+		return scope.Stats().Subscribers == 2
+	}, time.Second, time.Millisecond)
+	pubsub.PublishToScope(ctx, scope, event{X: 1})
+	pubsub.PublishToScope(ctx, scope, event{X: 2})
+
+	assert.Eventually(t, func() bool {
+		s := out.String()
+		return strings.Contains(s, `{"x":1}`) && strings.Contains(s, `{"x":2}`)
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestRun_Errors(t *testing.T) {
+	scope := pubsub.NewEventScope()
+	server := httptest.NewServer(scope.AdminHandler())
+	defer server.Close()
+
+	ctx := context.Background()
+	var out bytes.Buffer
+	assert.ErrorIs(t, run(ctx, nil, &out), errUsage)
+	assert.ErrorIs(t, run(ctx, []string{"unknown"}, &out), errUsage)
+	assert.ErrorContains(t, run(ctx, []string{"-addr", server.URL, "publish"}, &out), "--type is required")
+	assert.ErrorContains(t, run(ctx, []string{"-addr", server.URL, "list-subscribers", "--type=main.event"}, &out), "404")
+}
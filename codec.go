@@ -0,0 +1,70 @@
+package pubsub
+
+import "encoding/json"
+
+// Codec serializes published values for features that need to move messages out of memory,
+// such as spilling to disk or sending them across a network.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is a Codec backed by encoding/json. Only exported struct fields survive a round trip.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// marshal serializes val with the scope's codec and compresses the result if the scope has
+// compression enabled. name is the name of the type val is published as. It is covered by the
+// signature, so a signed payload cannot be passed off as a value of another type.
+func (e *EventScope) marshal(name string, val any) ([]byte, error) {
+	data, err := e.codec.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	if e.compression.algo != nil {
+		data, err = e.compression.compress(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(e.signingKeys) > 0 {
+		data = sign(e.signingKeys[0], name, data)
+	}
+	return data, nil
+}
+
+// unmarshal reverses marshal, decoding the value with the provided decode function. The data
+// is rejected if it was not marshaled under the same type name.
+func (e *EventScope) unmarshal(name string, data []byte, decode func(Codec, []byte) (any, error)) (any, error) {
+	if len(e.signingKeys) > 0 {
+		var err error
+		data, err = verify(e.signingKeys, name, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if e.compression.algo != nil {
+		var err error
+		data, err = e.compression.decompress(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decode(e.codec, data)
+}
+
+// decodeAs unmarshals data into a new value of type T.
+func decodeAs[T any](c Codec, data []byte) (any, error) {
+	var val T
+	if err := c.Unmarshal(data, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrNoHandler is returned by Dispatch when no handler is registered for the command type.
+	ErrNoHandler = errors.New("pubsub: no handler registered for command")
+
+	// ErrHandlerExists is returned by Register when the command type already has a handler.
+	ErrHandlerExists = errors.New("pubsub: handler already registered for command")
+)
+
+// CommandBus routes commands to handlers by type. Unlike events, each command is handled by
+// exactly one handler, which returns a result to the caller.
+type CommandBus struct {
+	scope    *EventScope
+	handlers sync.Map // reflect.Type -> handler
+}
+
+// NewCommandBus creates a command bus that publishes every dispatched command on scope so that
+// subscribers can observe commands without handling them.
+func NewCommandBus(scope *EventScope) *CommandBus {
+	return &CommandBus{
+		scope: scope,
+	}
+}
+
+// Register sets the handler for commands of type C. A command type can only have one handler;
+// registering a second one returns ErrHandlerExists.
+func Register[C, R any](bus *CommandBus, handler func(context.Context, C) (R, error)) error {
+	// Handlers are keyed by the command's type rather than its zero value, since the zero values
+	// of all interface types are nil.
+	if _, loaded := bus.handlers.LoadOrStore(typeOf[C](), handler); loaded {
+		return fmt.Errorf("%w: %s", ErrHandlerExists, typeName[C]())
+	}
+	return nil
+}
+
+// Dispatch synchronously executes cmd with its registered handler and returns the handler's
+// result. ErrNoHandler is returned if no handler has been registered for C, or if the handler
+// registered for C does not return an R.
+func Dispatch[C, R any](ctx context.Context, bus *CommandBus, cmd C) (R, error) {
+	var noResult R
+
+	h, ok := bus.handlers.Load(typeOf[C]())
+	if !ok {
+		return noResult, fmt.Errorf("%w: %s", ErrNoHandler, typeName[C]())
+	}
+	handler, ok := h.(func(context.Context, C) (R, error))
+	if !ok {
+		return noResult, fmt.Errorf("%w: %s returning %s", ErrNoHandler, typeName[C](), typeName[R]())
+	}
+
+	if err := PublishToScope(ctx, bus.scope, cmd); err != nil {
+		return noResult, err
+	}
+	return handler(ctx, cmd)
+}
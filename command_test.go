@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createUser struct {
+	Name string
+}
+
+type deleteUser struct {
+	ID int
+}
+
+func TestCommandBus(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	bus := NewCommandBus(testScope)
+
+	observed, unsub := SubscribeToScope[createUser](ctx, testScope)
+	defer unsub()
+
+	err := Register(bus, func(_ context.Context, cmd createUser) (int, error) {
+		return len(cmd.Name), nil
+	})
+	require.NoError(t, err)
+
+	id, err := Dispatch[createUser, int](ctx, bus, createUser{Name: "gopher"})
+	require.NoError(t, err)
+	assert.Equal(t, 6, id)
+	assert.Equal(t, createUser{Name: "gopher"}, <-observed)
+}
+
+func TestCommandBus_Errors(t *testing.T) {
+	ctx := context.Background()
+	bus := NewCommandBus(NewEventScope())
+
+	_, err := Dispatch[deleteUser, bool](ctx, bus, deleteUser{ID: 1})
+	assert.ErrorIs(t, err, ErrNoHandler)
+
+	handler := func(context.Context, deleteUser) (bool, error) { return true, nil }
+	require.NoError(t, Register(bus, handler))
+	assert.ErrorIs(t, Register(bus, handler), ErrHandlerExists)
+
+	_, err = Dispatch[deleteUser, string](ctx, bus, deleteUser{ID: 1})
+	assert.ErrorIs(t, err, ErrNoHandler)
+
+	ok, err := Dispatch[deleteUser, bool](ctx, bus, deleteUser{ID: 1})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+type userCommand interface {
+	user() string
+}
+
+type orderCommand interface {
+	order() int
+}
+
+type renameUser struct {
+	Name string
+}
+
+func (r renameUser) user() string { return r.Name }
+
+func TestCommandBus_InterfaceCommands(t *testing.T) {
+	ctx := context.Background()
+	bus := NewCommandBus(NewEventScope())
+
+	// The zero value of every interface type is nil, which must not make the command types
+	// share a handler.
+	require.NoError(t, Register(bus, func(_ context.Context, cmd userCommand) (string, error) {
+		return cmd.user(), nil
+	}))
+	require.NoError(t, Register(bus, func(_ context.Context, cmd orderCommand) (int, error) {
+		return cmd.order(), nil
+	}))
+
+	name, err := Dispatch[userCommand, string](ctx, bus, renameUser{Name: "gopher"})
+	require.NoError(t, err)
+	assert.Equal(t, "gopher", name)
+}
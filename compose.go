@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ComposeScopes creates an event scope that receives every value published on any of the
+// parent scopes, as its own subscribers to every type would also see them, in addition to the
+// values published on it directly. Values published on the composed scope are only delivered to
+// its own subscribers: they are not published on the parents. Values of a parent keep the type
+// and identity they were published with, and arrive in the order they were published on that
+// parent.
+//
+// The composed scope is closed once every parent has been closed, and stops receiving the values
+// of the parents when it is closed itself. It has the default options of NewEventScope.
+func ComposeScopes(parents ...*EventScope) *EventScope {
+	composed := NewEventScope()
+	ctx, cancel := context.WithCancel(context.Background())
+	composed.OnClose(cancel)
+
+	// open counts the parents that have not been closed, from the start so that a parent closing
+	// early does not close the composed scope before the others are subscribed to.
+	var open atomic.Int64
+	for _, parent := range parents {
+		if parent != nil {
+			open.Add(1)
+		}
+	}
+	for _, parent := range parents {
+		if parent == nil {
+			continue
+		}
+		// The relay does not make the parent interested in the types it carries.
+		ch, _ := subscribeAll(ctx, parent, WithOrdered(), withPassive())
+		go func() {
+			for msg := range ch {
+				composed.relay(ctx, msg)
+			}
+			if open.Add(-1) == 0 && ctx.Err() == nil {
+				composed.Close()
+			}
+		}()
+	}
+	return composed
+}
+
+// relay publishes a message received from a parent scope on the composed scope e.
+func (e *EventScope) relay(ctx context.Context, msg message) {
+	relayed := e.newMessage(ctx, msg.val)
+	relayed.identity, relayed.correlationID = msg.identity, msg.correlationID
+	if err := e.publish(ctx, msg.eventType, relayed); err != nil && !errors.Is(err, ErrScopeClosed) {
+		e.reportError(err)
+	}
+}
@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeScopes(t *testing.T) {
+	ctx := context.Background()
+	first := NewEventScope()
+	second := NewEventScope()
+	composed := ComposeScopes(first, second)
+	defer composed.Close()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, composed)
+	defer unsub()
+	errCh, unsubErrs := SubscribeToErrors(ctx, composed)
+	defer unsubErrs()
+	firstCh, unsubFirst := SubscribeToScope[int](ctx, first)
+	defer unsubFirst()
+
+	require.NoError(t, PublishToScope(ctx, first, 1))
+	assert.Equal(t, 1, <-testingCh)
+	assert.Equal(t, 1, <-firstCh)
+	require.NoError(t, PublishToScope(ctx, second, 2))
+	assert.Equal(t, 2, <-testingCh)
+
+	// Direct publishes reach the composed scope's subscribers only.
+	require.NoError(t, PublishToScope(ctx, composed, 3))
+	assert.Equal(t, 3, <-testingCh)
+	select {
+	case val := <-firstCh:
+		t.Fatalf("composed publish reached a parent: %d", val)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Values keep the type they were published as on the parent.
+	require.NoError(t, PublishError(ctx, second, errNotFound))
+	assert.ErrorIs(t, <-errCh, errNotFound)
+}
+
+func TestComposeScopes_Close(t *testing.T) {
+	first := NewEventScope()
+	second := NewEventScope()
+	composed := ComposeScopes(first, second)
+
+	require.NoError(t, first.Close())
+	assert.Never(t, composed.closed.Load, 10*time.Millisecond, time.Millisecond)
+	require.NoError(t, second.Close())
+	assert.Eventually(t, composed.closed.Load, time.Second, time.Millisecond)
+}
+
+func TestComposeScopes_CloseComposed(t *testing.T) {
+	parent := NewEventScope()
+	defer parent.Close()
+	composed := ComposeScopes(parent)
+
+	require.NoError(t, composed.Close())
+	assert.Eventually(t, func() bool { return parent.Stats().Subscribers == 0 }, time.Second, time.Millisecond)
+}
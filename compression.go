@@ -0,0 +1,206 @@
+package pubsub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressionMinSize is the payload size, in bytes, above which compression is applied
+// when no other threshold has been configured.
+const DefaultCompressionMinSize = 1024
+
+// CompressionAlgo compresses serialized message payloads.
+type CompressionAlgo interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// WithCompression compresses serialized messages with algo before they are stored or
+// transmitted. Only payloads larger than the configured minimum size are compressed; see
+// WithCompressionMinSize.
+func WithCompression(algo CompressionAlgo) EventScopeOption {
+	return func(e *EventScope) {
+		e.compression.algo = algo
+	}
+}
+
+// WithCompressionMinSize sets the payload size, in bytes, above which compression is applied.
+// It only records the threshold and has no effect unless WithCompression is also used.
+func WithCompressionMinSize(minSize int) EventScopeOption {
+	return func(e *EventScope) {
+		e.compression.minSize = minSize
+	}
+}
+
+// WithCompressionThreshold makes an ordered subscription, see WithOrdered, compress the values it
+// holds back until the values published before them have been forwarded, which keeps large values
+// from piling up in memory behind a slow delivery. Values are serialized with the scope's Codec,
+// compressed with algo if they are larger than minBytes, and decoded again before they are sent
+// on the channel, so they must survive a round trip through the Codec. Values that fail to
+// serialize are held as they are, and held values that fail to decode are reported to the scope's
+// error handler and skipped. Subscriptions to every type hold their values as they are.
+func WithCompressionThreshold(minBytes int, algo CompressionAlgo) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.compression = &compression{algo: algo, minSize: minBytes}
+	}
+}
+
+// heldValue is the value of a message held back by a subscription created with
+// WithCompressionThreshold, serialized and compressed.
+type heldValue struct {
+	data []byte
+}
+
+// hold compresses the value of msg, which the subscriber is about to hold back, if the
+// subscription was created with WithCompressionThreshold and the value is large enough.
+func (s *subscriber) hold(msg message) message {
+	if s.compression == nil || s.all || msg.val == nil {
+		return msg
+	}
+	data, err := s.e.codec.Marshal(msg.val)
+	if err != nil || len(data) <= s.compression.minSize {
+		return msg
+	}
+	compressed, err := s.compression.algo.Compress(data)
+	if err != nil || len(compressed) >= len(data) {
+		return msg
+	}
+	msg.val = heldValue{data: compressed}
+	return msg
+}
+
+// release reverses hold, decoding the value of msg as a T.
+func release[T any](s *subscriber, msg message) (message, error) {
+	held, ok := msg.val.(heldValue)
+	if !ok {
+		return msg, nil
+	}
+	data, err := s.compression.algo.Decompress(held.data)
+	if err != nil {
+		return msg, err
+	}
+	msg.val, err = decodeAs[T](s.e.codec, data)
+	return msg, err
+}
+
+// Payload flags written ahead of every payload when compression is enabled.
+const (
+	payloadRaw byte = iota
+	payloadCompressed
+)
+
+var errMalformedPayload = errors.New("pubsub: malformed payload")
+
+// compression holds the scope's compression settings. Compression is disabled while algo is nil.
+type compression struct {
+	algo    CompressionAlgo
+	minSize int
+}
+
+func (c *compression) compress(data []byte) ([]byte, error) {
+	if c.algo == nil || len(data) <= c.minSize {
+		return append([]byte{payloadRaw}, data...), nil
+	}
+
+	compressed, err := c.algo.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{payloadCompressed}, compressed...), nil
+}
+
+func (c *compression) decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errMalformedPayload
+	}
+
+	switch data[0] {
+	case payloadRaw:
+		return data[1:], nil
+	case payloadCompressed:
+		if c.algo == nil {
+			return nil, errMalformedPayload
+		}
+		return c.algo.Decompress(data[1:])
+	default:
+		return nil, errMalformedPayload
+	}
+}
+
+// Gzip is a CompressionAlgo using compress/gzip at the given level. The zero value uses
+// gzip.DefaultCompression.
+type Gzip struct {
+	Level int
+}
+
+func (g Gzip) Compress(data []byte) ([]byte, error) {
+	level := g.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (Gzip) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// Snappy is a CompressionAlgo using the snappy block format.
+type Snappy struct{}
+
+func (Snappy) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+func (Snappy) Decompress(data []byte) ([]byte, error) {
+	return snappy.Decode(nil, data)
+}
+
+// Zstd is a CompressionAlgo using zstandard with the default encoder settings.
+type Zstd struct{}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func initZstd() {
+	// Neither constructor can fail without options.
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+}
+
+func (Zstd) Compress(data []byte) ([]byte, error) {
+	zstdOnce.Do(initZstd)
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func (Zstd) Decompress(data []byte) ([]byte, error) {
+	zstdOnce.Do(initZstd)
+	return zstdDecoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,143 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionAlgos(t *testing.T) {
+	payload := []byte(strings.Repeat("compressible payload ", 100))
+
+	algos := map[string]CompressionAlgo{
+		"gzip":   Gzip{},
+		"snappy": Snappy{},
+		"zstd":   Zstd{},
+	}
+	for name, algo := range algos {
+		t.Run(name, func(t *testing.T) {
+			compressed, err := algo.Compress(payload)
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(payload))
+
+			decompressed, err := algo.Decompress(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+}
+
+func TestCompression_MinSize(t *testing.T) {
+	testScope := NewEventScope(WithCompression(Gzip{}), WithCompressionMinSize(100))
+
+	small, err := testScope.marshal("string", "short")
+	require.NoError(t, err)
+	assert.Equal(t, payloadRaw, small[0])
+
+	large, err := testScope.marshal("string", strings.Repeat("a", 200))
+	require.NoError(t, err)
+	assert.Equal(t, payloadCompressed, large[0])
+
+	for _, data := range [][]byte{small, large} {
+		_, err := testScope.unmarshal("string", data, decodeAs[string])
+		assert.NoError(t, err)
+	}
+}
+
+func TestCompression_Spill(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(
+		WithBoundedBuffer(1, t.TempDir()),
+		WithCompression(Zstd{}),
+		WithCompressionMinSize(16),
+	)
+
+	testingCh, unsub := SubscribeToScope[spillEvent](ctx, testScope)
+	defer unsub()
+
+	payload := strings.Repeat("x", 256)
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, spillEvent{N: i, Payload: payload})
+	}
+
+	for i := 0; i < 3; i++ {
+		val := <-testingCh
+		assert.Equal(t, payload, val.Payload)
+	}
+}
+
+func TestCompression_Malformed(t *testing.T) {
+	testScope := NewEventScope(WithCompression(Snappy{}))
+
+	_, err := testScope.unmarshal("string", nil, decodeAs[string])
+	assert.Error(t, err)
+
+	_, err = testScope.unmarshal("string", append([]byte{payloadCompressed}, bytes.Repeat([]byte{0xff}, 8)...), decodeAs[string])
+	assert.Error(t, err)
+}
+
+func TestCompression_MinSizeOnly(t *testing.T) {
+	plain := NewEventScope()
+	thresholdOnly := NewEventScope(WithCompressionMinSize(1))
+
+	want, err := plain.marshal("string", "value")
+	require.NoError(t, err)
+	got, err := thresholdOnly.marshal("string", "value")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestCompression_OptionOrder(t *testing.T) {
+	testScope := NewEventScope(WithCompressionMinSize(10), WithCompression(Gzip{}))
+
+	data, err := testScope.marshal("string", strings.Repeat("a", 20))
+	require.NoError(t, err)
+	assert.Equal(t, payloadCompressed, data[0])
+}
+
+type largeEvent struct {
+	ID   int
+	Body string
+}
+
+func TestCompressionThreshold_Hold(t *testing.T) {
+	sub := &subscriber{
+		e:           NewEventScope(),
+		compression: &compression{algo: Zstd{}, minSize: 64},
+	}
+
+	large := message{val: largeEvent{ID: 1, Body: strings.Repeat("payload ", 100)}}
+	held := sub.hold(large)
+	require.IsType(t, heldValue{}, held.val)
+	assert.Less(t, len(held.val.(heldValue).data), 100)
+	released, err := release[largeEvent](sub, held)
+	require.NoError(t, err)
+	assert.Equal(t, large.val, released.val)
+
+	// Small values are held as they are.
+	small := message{val: largeEvent{ID: 2}}
+	assert.Equal(t, small, sub.hold(small))
+
+	_, err = release[largeEvent](sub, message{val: heldValue{data: []byte("garbage")}})
+	assert.Error(t, err)
+}
+
+func TestCompressionThreshold(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	ch, unsub := SubscribeToScope[largeEvent](ctx, testScope, WithOrdered(), WithCompressionThreshold(64, Snappy{}))
+	defer unsub()
+
+	body := strings.Repeat("payload ", 100)
+	const count = 100
+	for i := 0; i < count; i++ {
+		PublishToScope(ctx, testScope, largeEvent{ID: i, Body: body})
+	}
+	for i := 0; i < count; i++ {
+		assert.Equal(t, largeEvent{ID: i, Body: body}, <-ch)
+	}
+}
@@ -0,0 +1,39 @@
+package pubsub
+
+import "context"
+
+// WithConcurrencyLimit bounds the number of goroutines the scope runs at once to deliver its
+// messages to maxInFlight, so that a burst of publishes to slow subscribers cannot start an
+// unbounded number of them. Each delivery holds a slot of the limit until its goroutine exits.
+// When every slot is taken, publishing blocks until one is released, or until the publish
+// context is canceled, in which case the remaining deliveries of the message are abandoned. The
+// limit does not apply to deliveries made by a SharedPool or the workers of NewChannelScope,
+// which bound their goroutines themselves. maxInFlight must be positive.
+func WithConcurrencyLimit(maxInFlight int) EventScopeOption {
+	if maxInFlight <= 0 {
+		panic("pubsub: concurrency limit must be positive")
+	}
+	return func(e *EventScope) {
+		e.inFlight = make(chan struct{}, maxInFlight)
+	}
+}
+
+// goLimited runs fn on a new goroutine once a slot of the scope's concurrency limit is free. If
+// ctx is canceled first, fn is run on the calling goroutine instead, where it gives up on its
+// delivery right away.
+func (e *EventScope) goLimited(ctx context.Context, fn func()) {
+	if e.inFlight == nil {
+		go fn()
+		return
+	}
+	select {
+	case e.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		fn()
+		return
+	}
+	go func() {
+		defer func() { <-e.inFlight }()
+		fn()
+	}()
+}
@@ -0,0 +1,62 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithConcurrencyLimit(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithConcurrencyLimit(1))
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	// The forwarder of the subscription holds the first value, and the one delivery goroutine
+	// allowed the second, so the third publish waits for a slot.
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		PublishToScope(ctx, testScope, 3)
+	}()
+	assert.Never(t, func() bool {
+		select {
+		case <-published:
+			return true
+		default:
+			return false
+		}
+	}, 20*time.Millisecond, time.Millisecond)
+
+	received := []int{<-ints}
+	<-published
+	received = append(received, <-ints, <-ints)
+	assert.ElementsMatch(t, []int{1, 2, 3}, received)
+}
+
+func TestWithConcurrencyLimit_Canceled(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithConcurrencyLimit(1))
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, PublishToScope(timeoutCtx, testScope, 3))
+	assert.Equal(t, int64(1), testScope.Stats().Dropped)
+
+	assert.ElementsMatch(t, []int{1, 2}, []int{<-ints, <-ints})
+}
+
+func TestWithConcurrencyLimit_Invalid(t *testing.T) {
+	assert.Panics(t, func() { WithConcurrencyLimit(0) })
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+import "context"
+
+// Condition is the typed, context aware equivalent of a sync.Cond built on an event scope:
+// waiters block until the next value of T is broadcast on the scope.
+type Condition[T any] struct {
+	scope *EventScope
+}
+
+// NewCondition creates a Condition broadcasting values of T on the event scope. Values of T
+// published on the scope by other means wake the waiters as well.
+func NewCondition[T any](scope *EventScope) *Condition[T] {
+	return &Condition[T]{scope: scope}
+}
+
+// Broadcast wakes every waiter with val. It publishes val with PublishToScope and returns its
+// error.
+func (c *Condition[T]) Broadcast(ctx context.Context, val T) error {
+	return PublishToScope(ctx, c.scope, val)
+}
+
+// Wait blocks until the next value is broadcast after Wait is called and returns it. It returns
+// ctx.Err() if ctx is done first, and ErrNilScope, ErrScopeClosed or ErrUnauthorized if the scope
+// cannot be waited on.
+func (c *Condition[T]) Wait(ctx context.Context) (T, error) {
+	return c.WaitFor(ctx, func(T) bool { return true })
+}
+
+// WaitFor is like Wait, but blocks until a value that pred returns true for is broadcast.
+func (c *Condition[T]) WaitFor(ctx context.Context, pred func(T) bool) (T, error) {
+	ch, unsub := SubscribeToScope[T](ctx, c.scope)
+	defer unsub()
+
+	for {
+		select {
+		case val, ok := <-ch:
+			if !ok {
+				var zero T
+				return zero, subscriptionErr(ctx, c.scope)
+			}
+			if pred(val) {
+				return val, nil
+			}
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
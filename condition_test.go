@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondition(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	cond := NewCondition[int](testScope)
+
+	const waiters = 3
+	woken := make(chan int, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			val, err := cond.Wait(ctx)
+			assert.NoError(t, err)
+			woken <- val
+		}()
+	}
+	even := make(chan int, 1)
+	go func() {
+		val, err := cond.WaitFor(ctx, func(i int) bool { return i%2 == 0 })
+		assert.NoError(t, err)
+		even <- val
+	}()
+	assert.Eventually(t, func() bool { return testScope.Stats().Subscribers == waiters+1 }, time.Second, time.Millisecond)
+
+	// Every waiter is woken by the same broadcast.
+	assert.NoError(t, cond.Broadcast(ctx, 1))
+	for i := 0; i < waiters; i++ {
+		assert.Equal(t, 1, <-woken)
+	}
+	assert.Empty(t, even)
+
+	assert.NoError(t, cond.Broadcast(ctx, 2))
+	assert.Equal(t, 2, <-even)
+}
+
+func TestCondition_Errors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	testScope := NewEventScope()
+	_, err := NewCondition[int](testScope).Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	testScope.Close()
+	_, err = NewCondition[int](testScope).Wait(context.Background())
+	assert.ErrorIs(t, err, ErrScopeClosed)
+	_, err = NewCondition[int](nil).Wait(context.Background())
+	assert.ErrorIs(t, err, ErrNilScope)
+}
@@ -0,0 +1,189 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ScopeConfig is the configuration of an event scope as written by MarshalConfig and read by
+// UnmarshalConfig, so that a scope can be described in a JSON config file.
+//
+// Settings made with functions or holding secrets, such as rewriters, plugins, hooks and signing
+// keys, cannot be restored from a config. The types with rewriters and the plugins are still
+// listed by name, to document the scope, and are ignored by UnmarshalConfig.
+type ScopeConfig struct {
+	// ID is the ID given by EnableVectorClock, if any.
+	ID               string `json:"id,omitempty"`
+	LamportClock     bool   `json:"lamportClock,omitempty"`
+	GlobalSequencing bool   `json:"globalSequencing,omitempty"`
+
+	CopyOnWrite    bool `json:"copyOnWrite,omitempty"`
+	SerialDelivery bool `json:"serialDelivery,omitempty"`
+	HighWaterMark  int  `json:"highWaterMark,omitempty"`
+
+	// Replay is the limit given to WithReplay, or nil if the scope does not retain messages.
+	Replay  *int `json:"replay,omitempty"`
+	History int  `json:"history,omitempty"`
+
+	// Codec is "json" for JSONCodec. Compression is "gzip", "snappy" or "zstd", and GzipLevel
+	// the level of Gzip.
+	Codec              string `json:"codec,omitempty"`
+	Compression        string `json:"compression,omitempty"`
+	GzipLevel          int    `json:"gzipLevel,omitempty"`
+	CompressionMinSize int    `json:"compressionMinSize,omitempty"`
+
+	AllowPublish   []AccessRule `json:"allowPublish,omitempty"`
+	AllowSubscribe []AccessRule `json:"allowSubscribe,omitempty"`
+
+	Rewriters []string `json:"rewriters,omitempty"`
+	Plugins   []string `json:"plugins,omitempty"`
+}
+
+// AccessRule is a rule added with AllowPublish or AllowSubscribe.
+type AccessRule struct {
+	Types      string   `json:"types"`
+	Identities []string `json:"identities"`
+}
+
+// MarshalConfig returns the configuration of the event scope as a ScopeConfig in JSON. It fails if
+// the scope uses a codec or compression algorithm other than the ones provided by this package,
+// which a config cannot name.
+func (e *EventScope) MarshalConfig() ([]byte, error) {
+	cfg := ScopeConfig{
+		ID:                 e.ID(),
+		LamportClock:       e.lamportEnabled.Load(),
+		GlobalSequencing:   e.sequencingEnabled.Load(),
+		CopyOnWrite:        e.copyOnWrite,
+		SerialDelivery:     e.serial != nil,
+		HighWaterMark:      e.highWaterMark,
+		CompressionMinSize: e.compression.minSize,
+	}
+	if e.replay != nil {
+		limit := e.replay.limit
+		cfg.Replay = &limit
+	}
+	if e.history != nil {
+		cfg.History = e.history.size
+	}
+
+	switch e.codec.(type) {
+	case JSONCodec:
+		cfg.Codec = "json"
+	default:
+		return nil, fmt.Errorf("pubsub: codec %T cannot be named in a config", e.codec)
+	}
+	switch algo := e.compression.algo.(type) {
+	case nil:
+	case Gzip:
+		cfg.Compression, cfg.GzipLevel = "gzip", algo.Level
+	case Snappy:
+		cfg.Compression = "snappy"
+	case Zstd:
+		cfg.Compression = "zstd"
+	default:
+		return nil, fmt.Errorf("pubsub: compression %T cannot be named in a config", algo)
+	}
+
+	e.acl.mu.RLock()
+	cfg.AllowPublish = accessRules(e.acl.publish)
+	cfg.AllowSubscribe = accessRules(e.acl.subscribe)
+	e.acl.mu.RUnlock()
+
+	for typ := range e.rewriters {
+		cfg.Rewriters = append(cfg.Rewriters, typ.String())
+	}
+	sort.Strings(cfg.Rewriters)
+	e.pluginsMu.Lock()
+	for _, p := range e.plugins {
+		cfg.Plugins = append(cfg.Plugins, fmt.Sprintf("%T", p))
+	}
+	e.pluginsMu.Unlock()
+
+	return json.Marshal(cfg)
+}
+
+func accessRules(rules []aclRule) []AccessRule {
+	var out []AccessRule
+	for _, rule := range rules {
+		identities := make([]string, 0, len(rule.identities))
+		for id := range rule.identities {
+			identities = append(identities, id)
+		}
+		sort.Strings(identities)
+		out = append(out, AccessRule{Types: rule.filter, Identities: identities})
+	}
+	return out
+}
+
+// UnmarshalConfig applies a ScopeConfig read from JSON to the event scope. Settings that are left
+// out or zero keep their current value, and access rules are added to the scope's rules. Most
+// settings can only be made when a scope is created, so UnmarshalConfig must be called right
+// after NewEventScope, before the scope is used. Nothing is applied if the config is invalid.
+func (e *EventScope) UnmarshalConfig(data []byte) error {
+	var cfg ScopeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+
+	var opts []EventScopeOption
+	switch cfg.Codec {
+	case "":
+	case "json":
+		opts = append(opts, WithCodec(JSONCodec{}))
+	default:
+		return fmt.Errorf("pubsub: unknown codec %q", cfg.Codec)
+	}
+	switch cfg.Compression {
+	case "":
+	case "gzip":
+		opts = append(opts, WithCompression(Gzip{Level: cfg.GzipLevel}))
+	case "snappy":
+		opts = append(opts, WithCompression(Snappy{}))
+	case "zstd":
+		opts = append(opts, WithCompression(Zstd{}))
+	default:
+		return fmt.Errorf("pubsub: unknown compression %q", cfg.Compression)
+	}
+	if cfg.CompressionMinSize != 0 {
+		opts = append(opts, WithCompressionMinSize(cfg.CompressionMinSize))
+	}
+	if cfg.History < 0 {
+		return fmt.Errorf("pubsub: negative history size %d", cfg.History)
+	}
+	if cfg.History > 0 {
+		opts = append(opts, WithHistory(cfg.History))
+	}
+	if cfg.Replay != nil {
+		opts = append(opts, WithReplay(*cfg.Replay))
+	}
+	if cfg.HighWaterMark != 0 {
+		opts = append(opts, WithHighWaterMark(cfg.HighWaterMark))
+	}
+	if cfg.CopyOnWrite {
+		opts = append(opts, WithCopyOnWrite())
+	}
+	if cfg.SerialDelivery {
+		opts = append(opts, WithSerialDelivery())
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	if cfg.ID != "" {
+		e.EnableVectorClock(cfg.ID)
+	}
+	if cfg.LamportClock {
+		e.EnableLamportClock()
+	}
+	if cfg.GlobalSequencing {
+		e.EnableGlobalSequencing()
+	}
+	for _, rule := range cfg.AllowPublish {
+		e.AllowPublish(rule.Types, rule.Identities...)
+	}
+	for _, rule := range cfg.AllowSubscribe {
+		e.AllowSubscribe(rule.Types, rule.Identities...)
+	}
+	return nil
+}
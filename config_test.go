@@ -0,0 +1,72 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalConfig(t *testing.T) {
+	src := NewEventScope(
+		WithReplay(10),
+		WithHistory(3),
+		WithCompression(Gzip{Level: 5}),
+		WithCompressionMinSize(64),
+		WithHighWaterMark(50),
+		WithSerialDelivery(),
+		WithPublishRewriter(func(s string) string { return s }),
+	)
+	src.EnableVectorClock("node-1")
+	src.EnableLamportClock()
+	src.AllowPublish("pubsub.*", "writer", "admin")
+	src.AllowSubscribe("*", "reader")
+	var closed []string
+	require.NoError(t, src.UsePlugin(&recordingPlugin{closed: &closed}))
+
+	data, err := src.MarshalConfig()
+	require.NoError(t, err)
+	var cfg ScopeConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, []AccessRule{{Types: "pubsub.*", Identities: []string{"admin", "writer"}}}, cfg.AllowPublish)
+	assert.Equal(t, []string{"string"}, cfg.Rewriters)
+	assert.Equal(t, []string{"*pubsub.recordingPlugin"}, cfg.Plugins)
+
+	dst := NewEventScope()
+	require.NoError(t, dst.UnmarshalConfig(data))
+	assert.Equal(t, "node-1", dst.ID())
+	assert.True(t, dst.lamportEnabled.Load())
+	assert.Equal(t, Gzip{Level: 5}, dst.compression.algo)
+	assert.Equal(t, 64, dst.compression.minSize)
+	assert.Equal(t, 50, dst.highWaterMark)
+	assert.NotNil(t, dst.serial)
+	assert.Equal(t, 3, dst.history.size)
+	assert.Equal(t, 10, dst.replay.limit)
+
+	ctx := context.Background()
+	assert.ErrorIs(t, PublishToScope(WithIdentity(ctx, "reader"), dst, Gzip{}), ErrUnauthorized)
+	assert.NoError(t, PublishToScope(WithIdentity(ctx, "admin"), dst, Gzip{}))
+
+	// Marshaling the restored scope gives the same config, apart from what cannot be restored.
+	restored, err := dst.MarshalConfig()
+	require.NoError(t, err)
+	cfg.Rewriters, cfg.Plugins = nil, nil
+	want, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(restored))
+}
+
+type customCodec struct{ JSONCodec }
+
+func TestMarshalConfig_Errors(t *testing.T) {
+	_, err := NewEventScope(WithCodec(customCodec{})).MarshalConfig()
+	assert.Error(t, err)
+
+	testScope := NewEventScope()
+	assert.Error(t, testScope.UnmarshalConfig([]byte(`{"compression": "lz4", "history": 5}`)))
+	assert.Nil(t, testScope.history)
+	assert.Error(t, testScope.UnmarshalConfig([]byte(`{"history": -1}`)))
+	assert.Error(t, testScope.UnmarshalConfig([]byte(`not json`)))
+}
@@ -0,0 +1,113 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// DeadLetter is a value of type T that could not be handled, together with the reason.
+type DeadLetter[T any] struct {
+	Value T
+	Err   error
+
+	// Attempts is the number of times handling Value was attempted.
+	Attempts int
+}
+
+// DeadLetterHandler returns an error handler, to be set with WithErrorHandler, that sends the
+// values of type T that a SubscribeWithRetry handler kept failing on to dlq, so that they can be
+// inspected and later republished with ReplayDLQ. The send blocks the subscription the value came
+// from until dlq has room, so dlq should be buffered and drained. Other errors are logged.
+func DeadLetterHandler[T any](dlq chan<- DeadLetter[T]) func(err error) {
+	return func(err error) {
+		var handlerErr *HandlerError
+		if errors.As(err, &handlerErr) {
+			if val, ok := handlerErr.Value.(T); ok {
+				dlq <- DeadLetter[T]{Value: val, Err: handlerErr.Err, Attempts: handlerErr.Attempts}
+				return
+			}
+		}
+		log.Print(err)
+	}
+}
+
+// DLQOption configures a ReplayDLQ call.
+type DLQOption func(*dlqConfig)
+
+type dlqConfig struct {
+	delay time.Duration
+	until time.Time
+}
+
+// WithDLQDelay makes ReplayDLQ wait d between two republished values, so that a recovering
+// subscriber is not flooded.
+func WithDLQDelay(d time.Duration) DLQOption {
+	return func(c *dlqConfig) {
+		c.delay = d
+	}
+}
+
+// WithDLQUntil makes ReplayDLQ stop republishing once the time reaches until. Values still in the
+// queue at that time are left there.
+func WithDLQUntil(until time.Time) DLQOption {
+	return func(c *dlqConfig) {
+		c.until = until
+	}
+}
+
+// ReplayDLQ reads dead letters from dlq and republishes their values on the event scope, in the
+// order they are read, until dlq is closed, the deadline set with WithDLQUntil passes or ctx is
+// canceled. Delays and the deadline are measured with the scope's clock. It returns nil once dlq
+// is closed or the deadline has passed, ctx.Err() if ctx is canceled first, and the error of the
+// first publish that fails, whose letter is lost.
+func ReplayDLQ[T any](ctx context.Context, scope *EventScope, dlq chan DeadLetter[T], opts ...DLQOption) error {
+	if scope == nil {
+		return ErrNilScope
+	}
+	var cfg dlqConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var deadline <-chan time.Time
+	if !cfg.until.IsZero() {
+		deadline = scope.clock.After(cfg.until.Sub(scope.clock.Now()))
+	}
+
+	for n := 0; ; n++ {
+		if n > 0 && cfg.delay > 0 {
+			select {
+			case <-scope.clock.After(cfg.delay):
+			case <-deadline:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		// A deadline that has passed wins over letters that are ready.
+		select {
+		case <-deadline:
+			return nil
+		default:
+		}
+
+		var letter DeadLetter[T]
+		var ok bool
+		select {
+		case letter, ok = <-dlq:
+		case <-deadline:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		if err := PublishToScope(ctx, scope, letter.Value); err != nil {
+			return err
+		}
+	}
+}
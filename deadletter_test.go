@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayDLQ(t *testing.T) {
+	ctx := context.Background()
+	dlq := make(chan DeadLetter[int], 10)
+	testScope := NewEventScope(WithErrorHandler(DeadLetterHandler(dlq)))
+
+	// The handler fails until the outage is over.
+	var outage atomic.Bool
+	outage.Store(true)
+	handled := make(chan int, 10)
+	unsub := SubscribeWithRetry(ctx, testScope, func(v int) error {
+		if outage.Load() {
+			return errTransient
+		}
+		handled <- v
+		return nil
+	}, 1, nil)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, 2)
+	letters := []DeadLetter[int]{<-dlq, <-dlq}
+	for i, letter := range letters {
+		assert.Equal(t, DeadLetter[int]{Value: i + 1, Err: errTransient, Attempts: 2}, letter)
+		dlq <- letter
+	}
+
+	outage.Store(false)
+	close(dlq)
+	start := time.Now()
+	require.NoError(t, ReplayDLQ(ctx, testScope, dlq, WithDLQDelay(10*time.Millisecond)))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, 1, <-handled)
+	assert.Equal(t, 2, <-handled)
+}
+
+func TestReplayDLQ_Stops(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	dlq := make(chan DeadLetter[int], 1)
+	dlq <- DeadLetter[int]{Value: 1}
+
+	// Letters are left in the queue once the deadline has passed.
+	require.NoError(t, ReplayDLQ(ctx, testScope, dlq, WithDLQUntil(time.Now().Add(-time.Second))))
+	assert.Len(t, dlq, 1)
+
+	// The deadline also ends the wait for letters.
+	<-dlq
+	require.NoError(t, ReplayDLQ(ctx, testScope, dlq, WithDLQUntil(time.Now().Add(10*time.Millisecond))))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, ReplayDLQ(canceled, testScope, dlq), context.Canceled)
+}
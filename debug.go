@@ -0,0 +1,111 @@
+package pubsub
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// debugPrefix is the path PProfHandler serves under.
+const debugPrefix = "/debug/pubsub/"
+
+// debugSubscriber describes a subscriber in the output of /debug/pubsub/subscribers.
+type debugSubscriber struct {
+	ID       string `json:"id"`
+	Priority int    `json:"priority"`
+	Ordered  bool   `json:"ordered"`
+	Passive  bool   `json:"passive"`
+
+	// Pending is the number of messages handed to the subscriber it has not received yet.
+	Pending int64 `json:"pending"`
+}
+
+// PProfHandler returns an http.Handler exposing debugging endpoints for the scope under
+// /debug/pubsub/, in the manner of net/http/pprof:
+//
+//	/debug/pubsub/types                   list the types that have been subscribed to
+//	/debug/pubsub/subscribers?type={type} describe the subscribers of a type
+//	/debug/pubsub/tail?type={type}        stream the values published to the type
+//	/debug/pubsub/stats                   return the scope's ScopeStats
+//
+// Types are identified by their name, and values are streamed as with the events endpoint of
+// AdminHandler, subject to the scope's access rules. Unlike AdminHandler, the endpoints only
+// read the scope. The handler expects the full request path, so it should be registered for the
+// /debug/pubsub/ pattern, see RegisterDebugHandlers.
+func (e *EventScope) PProfHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(debugPrefix, e.serveDebugIndex)
+	mux.HandleFunc(debugPrefix+"types", func(w http.ResponseWriter, r *http.Request) {
+		if allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, e.typeNames())
+		}
+	})
+	mux.HandleFunc(debugPrefix+"subscribers", func(w http.ResponseWriter, r *http.Request) {
+		typ, ok := e.debugType(w, r)
+		if !ok {
+			return
+		}
+		subs := []debugSubscriber{}
+		for _, sub := range e.subscribersOf(typ) {
+			subs = append(subs, debugSubscriber{
+				ID:       sub.id.String(),
+				Priority: sub.priority,
+				Ordered:  sub.ordered,
+				Passive:  sub.passive,
+				Pending:  sub.pending.Load(),
+			})
+		}
+		sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+		writeJSON(w, subs)
+	})
+	mux.HandleFunc(debugPrefix+"tail", func(w http.ResponseWriter, r *http.Request) {
+		if typ, ok := e.debugType(w, r); ok {
+			e.streamEvents(w, r, typ)
+		}
+	})
+	mux.HandleFunc(debugPrefix+"stats", func(w http.ResponseWriter, r *http.Request) {
+		if allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, e.Stats())
+		}
+	})
+	return mux
+}
+
+// RegisterDebugHandlers registers the handler returned by PProfHandler with
+// http.DefaultServeMux. As with net/http/pprof, only one scope can be registered: registering a
+// second one panics.
+func (e *EventScope) RegisterDebugHandlers() {
+	http.Handle(debugPrefix, e.PProfHandler())
+}
+
+// serveDebugIndex lists the debugging endpoints.
+func (e *EventScope) serveDebugIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != debugPrefix {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, endpoint := range []string{"types", "subscribers?type=", "tail?type=", "stats"} {
+		fmt.Fprintln(w, debugPrefix+endpoint)
+	}
+}
+
+// debugType returns the type named by the type query parameter of a GET request, or writes the
+// error response and returns false.
+func (e *EventScope) debugType(w http.ResponseWriter, r *http.Request) (reflect.Type, bool) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return nil, false
+	}
+	name := r.URL.Query().Get("type")
+	if name == "" {
+		http.Error(w, "missing type", http.StatusBadRequest)
+		return nil, false
+	}
+	typ, ok := e.types()[name]
+	if !ok {
+		http.Error(w, "unknown type", http.StatusNotFound)
+		return nil, false
+	}
+	return typ, true
+}
@@ -0,0 +1,84 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPProfHandler(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	h := testScope.PProfHandler()
+
+	_, unsub := SubscribeToScope[int](ctx, testScope, WithPriority(2))
+	defer unsub()
+	PublishToScope(ctx, testScope, "unsubscribed")
+
+	rec := adminRequest(t, h, http.MethodGet, "/debug/pubsub/types", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `["int"]`, rec.Body.String())
+
+	rec = adminRequest(t, h, http.MethodGet, "/debug/pubsub/subscribers?type=int", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var subs []debugSubscriber
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &subs))
+	require.Len(t, subs, 1)
+	assert.Equal(t, 2, subs[0].Priority)
+
+	rec = adminRequest(t, h, http.MethodGet, "/debug/pubsub/stats", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var stats ScopeStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, int64(1), stats.Published)
+
+	rec = adminRequest(t, h, http.MethodGet, "/debug/pubsub/", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/debug/pubsub/tail?type=")
+}
+
+func TestPProfHandler_Errors(t *testing.T) {
+	h := NewEventScope().PProfHandler()
+
+	for path, code := range map[string]int{
+		"/debug/pubsub/subscribers":          http.StatusBadRequest,
+		"/debug/pubsub/subscribers?type=int": http.StatusNotFound,
+		"/debug/pubsub/tail?type=int":        http.StatusNotFound,
+		"/debug/pubsub/unknown":              http.StatusNotFound,
+	} {
+		assert.Equal(t, code, adminRequest(t, h, http.MethodGet, path, "").Code, path)
+	}
+	assert.Equal(t, http.StatusMethodNotAllowed, adminRequest(t, h, http.MethodPost, "/debug/pubsub/stats", "").Code)
+}
+
+func TestPProfHandler_Tail(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	testScope := NewEventScope()
+	_, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	server := httptest.NewServer(testScope.PProfHandler())
+	defer server.Close()
+	transport := &http.Transport{}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/debug/pubsub/tail?type=int", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool { return testScope.Stats().Subscribers == 2 }, time.Second, time.Millisecond)
+	PublishToScope(ctx, testScope, 1)
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "data: 1\n", line)
+}
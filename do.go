@@ -0,0 +1,44 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// doHooks holds the functions registered with Do. list is replaced, never modified, under mu.
+type doHooks struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]func(typeName string, val any)]
+}
+
+// Do registers fn to be called with the name of the type and the value of every publish on the
+// event scope, once it has been numbered and before it is delivered to the subscribers. It is a
+// lighter alternative to a Middleware for functions that only observe the values published, such
+// as counters and loggers: fn cannot change a value or stop it from being delivered. Functions
+// are called in the order they were registered, for the values published after Do returns.
+//
+// fn is called from the publishing goroutine and must not block, since the publish waits for it;
+// a function that may block should hand the value to a goroutine of its own. On a scope created
+// with WithSerialDelivery, the subscribers may already be receiving the value while fn runs.
+func (e *EventScope) Do(fn func(typeName string, val any)) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+
+	var list []func(string, any)
+	if old := e.hooks.list.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, fn)
+	e.hooks.list.Store(&list)
+}
+
+// runHooks calls the functions registered with Do with a value of type t.
+func (e *EventScope) runHooks(t eventType, val any) {
+	list := e.hooks.list.Load()
+	if list == nil {
+		return
+	}
+	for _, fn := range *list {
+		fn(t.name(), val)
+	}
+}
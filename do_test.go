@@ -0,0 +1,42 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	var names []string
+	var vals []any
+	testScope.Do(func(typeName string, _ any) { names = append(names, typeName) })
+	testScope.Do(func(_ string, val any) { vals = append(vals, val) })
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	require.NoError(t, PublishToScope(ctx, testScope, "unsubscribed"))
+
+	// The functions have run by the time the publish returns.
+	assert.Equal(t, []string{"int", "string"}, names)
+	assert.Equal(t, []any{1, "unsubscribed"}, vals)
+	assert.Equal(t, 1, <-testingCh)
+}
+
+func TestDo_Dropped(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.Use(PublishMiddlewareFunc(func(context.Context, string, any, PublishFn) {}))
+
+	called := false
+	testScope.Do(func(string, any) { called = true })
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.False(t, called)
+}
@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// backlog counts the messages pending on a scope, or its running forwarders, and wakes up the
+// callers of WaitUntilDrained and WaitForQuiescence whenever the count drops to zero.
+type backlog struct {
+	n atomic.Int64
+
+	mu    sync.Mutex
+	empty chan struct{} // nil while nobody is waiting
+}
+
+func (b *backlog) add(delta int64) {
+	if b.n.Add(delta) != 0 {
+		return
+	}
+	b.mu.Lock()
+	if b.empty != nil {
+		close(b.empty)
+		b.empty = nil
+	}
+	b.mu.Unlock()
+}
+
+// wait returns a channel that is closed the next time the count drops to zero.
+func (b *backlog) wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.empty == nil {
+		b.empty = make(chan struct{})
+	}
+	return b.empty
+}
+
+// Len returns the number of messages published on the scope that have not been received yet,
+// summed across every subscriber and type. Messages written to disk by WithBoundedBuffer count
+// once until they are handed to their subscribers.
+func (e *EventScope) Len() int {
+	return int(e.backlog.n.Load())
+}
+
+// WaitUntilDrained blocks until every message published on the scope has been received by its
+// subscribers, dropped because a subscriber stopped receiving, or abandoned because its publish
+// context was canceled, which makes Len return zero. It returns ctx.Err() if ctx is canceled
+// first. Messages published while it waits have to be drained as well.
+func (e *EventScope) WaitUntilDrained(ctx context.Context) error {
+	for {
+		// The channel is taken before checking the count, so a drop to zero in between is not
+		// missed.
+		empty := e.backlog.wait()
+		if e.Len() == 0 {
+			return nil
+		}
+		select {
+		case <-empty:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ActiveGoroutines returns the number of goroutines the scope runs to forward messages to its
+// subscribers. Each subscription has one until it is unsubscribed, its context is canceled or the
+// scope is closed, so a count that keeps growing points to subscriptions that are never ended.
+func (e *EventScope) ActiveGoroutines() int {
+	return int(e.forwarders.n.Load())
+}
+
+// WaitForQuiescence blocks until every goroutine counted by ActiveGoroutines has exited. It
+// returns ctx.Err() if ctx is canceled first. Subscriptions made while it waits have to end as
+// well.
+func (e *EventScope) WaitForQuiescence(ctx context.Context) error {
+	for {
+		idle := e.forwarders.wait()
+		if e.ActiveGoroutines() == 0 {
+			return nil
+		}
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
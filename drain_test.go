@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitUntilDrained(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	require.NoError(t, testScope.WaitUntilDrained(ctx))
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	strs, unsubStrs := SubscribeToScope[string](ctx, testScope, WithOrdered())
+	defer unsubStrs()
+
+	for i := 0; i < 10; i++ {
+		PublishToScope(ctx, testScope, i)
+		PublishToScope(ctx, testScope, "value")
+	}
+	assert.Equal(t, 20, testScope.Len())
+
+	var received atomic.Int64
+	consume := func() {
+		for ints != nil || strs != nil {
+			select {
+			case _, ok := <-ints:
+				if !ok {
+					ints = nil
+					continue
+				}
+			case _, ok := <-strs:
+				if !ok {
+					strs = nil
+					continue
+				}
+			}
+			received.Add(1)
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, testScope.WaitUntilDrained(waitCtx), context.DeadlineExceeded)
+
+	go consume()
+	require.NoError(t, testScope.WaitUntilDrained(ctx))
+	assert.Zero(t, testScope.Len())
+	assert.Eventually(t, func() bool { return received.Load() == 20 }, time.Second, time.Millisecond)
+}
+
+func TestWaitUntilDrained_Unsubscribed(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	_, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	for i := 0; i < 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	unsub()
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, testScope.WaitUntilDrained(waitCtx))
+}
+
+func TestWaitForQuiescence(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	require.NoError(t, testScope.WaitForQuiescence(ctx))
+
+	_, unsubInts := SubscribeToScope[int](ctx, testScope)
+	subCtx, cancelSub := context.WithCancel(ctx)
+	_, unsubStrs := SubscribeToScope[string](subCtx, testScope)
+	defer unsubStrs()
+	assert.Equal(t, 2, testScope.ActiveGoroutines())
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, testScope.WaitForQuiescence(waitCtx), context.DeadlineExceeded)
+
+	unsubInts()
+	cancelSub()
+	require.NoError(t, testScope.WaitForQuiescence(ctx))
+	assert.Zero(t, testScope.ActiveGoroutines())
+
+	// A scope that is closed stops every forwarder.
+	SubscribeToScope[int](ctx, testScope)
+	require.NoError(t, testScope.Close())
+	// Subscribing to a closed scope does not start one.
+	SubscribeToScope[int](ctx, testScope)
+	require.NoError(t, testScope.WaitForQuiescence(ctx))
+}
@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DuplicatePolicy decides what SubscribeToScope does when a subscriber subscribes to a type it is
+// already subscribed to, see WithDuplicateSubscribePolicy.
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow creates a second subscription, which receives every value as well.
+	DuplicateAllow DuplicatePolicy = iota
+	// DuplicateReturnExisting returns the channel of the existing subscription. It is ended once
+	// every UnsubFn returned for it has been called.
+	DuplicateReturnExisting
+	// DuplicatePanic panics.
+	DuplicatePanic
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateAllow:
+		return "allow"
+	case DuplicateReturnExisting:
+		return "return existing"
+	case DuplicatePanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("DuplicatePolicy(%d)", int(p))
+	}
+}
+
+// WithDuplicateSubscribePolicy sets what SubscribeToScope does when a subscriber subscribes to a
+// type twice, which usually means that only one of the channels is drained. Subscribers are told
+// apart by the key attached to their context with WithSubscriberKey; subscriptions made without a
+// key are never duplicates. The default is DuplicateAllow.
+func WithDuplicateSubscribePolicy(policy DuplicatePolicy) EventScopeOption {
+	return func(e *EventScope) {
+		e.duplicatePolicy = policy
+	}
+}
+
+type subscriberKey struct{}
+
+// WithSubscriberKey returns a copy of ctx identifying the subscriber that subscribes with it, for
+// the policy set with WithDuplicateSubscribePolicy.
+func WithSubscriberKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, subscriberKey{}, key)
+}
+
+// SubscriberKeyFromContext returns the key attached to ctx with WithSubscriberKey.
+func SubscriberKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(subscriberKey{}).(string)
+	return key, ok
+}
+
+// duplicateKey identifies the subscription of a subscriber to a type.
+type duplicateKey struct {
+	subscriber string
+	topic      any
+}
+
+// sharedSubscription is a subscription returned to every caller that subscribed to its type with
+// the same subscriber key, under DuplicateReturnExisting.
+type sharedSubscription struct {
+	ch    any
+	refs  int
+	unsub UnsubFn
+	stop  func() bool
+}
+
+type duplicateSubscriptions struct {
+	mu    sync.Mutex
+	byKey map[duplicateKey]*sharedSubscription
+}
+
+// subscribeOnce subscribes to T for the subscriber identified by key, applying the scope's
+// duplicate policy if the subscriber is already subscribed to T.
+func subscribeOnce[T any](ctx context.Context, e *EventScope, key string, opts ...SubscribeOption) (chan T, UnsubFn) {
+	d := &e.duplicates
+	k := duplicateKey{subscriber: key, topic: eventTypeOf[T]().key}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	shared, ok := d.byKey[k]
+	if ok {
+		if e.duplicatePolicy == DuplicatePanic {
+			panic(fmt.Sprintf("pubsub: %s subscribed to %s twice", key, typeName[T]()))
+		}
+		shared.refs++
+		return shared.ch.(chan T), d.release(k, shared)
+	}
+
+	ch, unsub := subscribe(ctx, e, func(val T, _ message) T { return val }, opts...)
+	shared = &sharedSubscription{ch: ch, refs: 1, unsub: unsub}
+	// A subscription that ends because its context is canceled is not returned anymore.
+	shared.stop = context.AfterFunc(ctx, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.byKey[k] == shared {
+			delete(d.byKey, k)
+		}
+	})
+	if d.byKey == nil {
+		d.byKey = make(map[duplicateKey]*sharedSubscription)
+	}
+	d.byKey[k] = shared
+	return ch, d.release(k, shared)
+}
+
+// release returns the UnsubFn of one caller of shared, which ends it once every caller has
+// unsubscribed.
+func (d *duplicateSubscriptions) release(k duplicateKey, shared *sharedSubscription) UnsubFn {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			shared.refs--
+			last := shared.refs == 0
+			if last && d.byKey[k] == shared {
+				delete(d.byKey, k)
+			}
+			d.mu.Unlock()
+
+			if last {
+				shared.stop()
+				shared.unsub()
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateSubscribePolicy_ReturnExisting(t *testing.T) {
+	ctx := WithSubscriberKey(context.Background(), "worker")
+	testScope := NewEventScope(WithDuplicateSubscribePolicy(DuplicateReturnExisting))
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, testScope.Stats().Subscribers)
+
+	// Other subscribers and types are not duplicates.
+	other, unsubOther := SubscribeToScope[int](WithSubscriberKey(context.Background(), "other"), testScope)
+	defer unsubOther()
+	strs, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	defer unsubStrs()
+	unkeyed, unsubUnkeyed := SubscribeToScope[int](context.Background(), testScope)
+	defer unsubUnkeyed()
+	assert.NotEqual(t, first, other)
+	assert.NotEqual(t, first, unkeyed)
+	assert.NotNil(t, strs)
+	assert.Equal(t, 4, testScope.Stats().Subscribers)
+
+	// The subscription ends once both callers have unsubscribed.
+	unsubFirst()
+	unsubFirst()
+	assert.Equal(t, 4, testScope.Stats().Subscribers)
+	unsubSecond()
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.Equal(t, 3, testScope.Stats().Subscribers)
+
+	third, unsubThird := SubscribeToScope[int](ctx, testScope)
+	defer unsubThird()
+	assert.NotEqual(t, first, third)
+}
+
+func TestDuplicateSubscribePolicy_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithSubscriberKey(context.Background(), "worker"))
+	testScope := NewEventScope(WithDuplicateSubscribePolicy(DuplicatePanic))
+
+	first, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	assert.Panics(t, func() { SubscribeToScope[int](ctx, testScope) })
+
+	// Once the first subscription has ended with its context, subscribing again is allowed.
+	cancel()
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.Eventually(t, func() (ok bool) {
+		defer func() {
+			if recover() != nil {
+				ok = false
+			}
+		}()
+		_, unsub := SubscribeToScope[int](WithSubscriberKey(context.Background(), "worker"), testScope)
+		unsub()
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func TestDuplicateSubscribePolicy_Allow(t *testing.T) {
+	ctx := WithSubscriberKey(context.Background(), "worker")
+	testScope := NewEventScope()
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	defer unsubFirst()
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope)
+	defer unsubSecond()
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, "return existing", DuplicateReturnExisting.String())
+}
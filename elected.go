@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+	"reflect"
+)
+
+// electedGroupTopicKey is the key the members of an elected group for typ are registered under.
+type electedGroupTopicKey struct {
+	typ   reflect.Type
+	group string
+}
+
+// ElectedGroup is a group of subscribers to T of which exactly one, the leader, receives values
+// at any time, for active-passive failover. It is created with NewElectedGroup.
+type ElectedGroup[T any] struct {
+	ctx   context.Context
+	scope *EventScope
+	ring  *hashRing
+	key   electedGroupTopicKey
+}
+
+// NewElectedGroup returns the elected group groupID for T on the event scope. Groups with the same
+// ID share their members, and are independent of the consistent hashing groups and sticky
+// subscribers of T. Every member of the group leaves it once ctx is canceled.
+func NewElectedGroup[T any](ctx context.Context, scope *EventScope, groupID string) *ElectedGroup[T] {
+	g := &ElectedGroup[T]{ctx: ctx, scope: scope}
+	if scope == nil {
+		return g
+	}
+	g.key = electedGroupTopicKey{typ: eventTypeOf[T]().typ, group: groupID}
+	g.ring = scope.ringsOf(g.key.typ).electedGroup(groupID)
+	return g
+}
+
+// Subscribe adds a member to the group. The earliest member still subscribed is the leader: its
+// channel receives every value of T published on the scope, in publish order, while the channels
+// of the other members, its followers, receive nothing. When the leader unsubscribes, the member
+// that subscribed after it becomes the leader and receives the values published from then on.
+// Values that were on their way to the previous leader are not handed over.
+func (g *ElectedGroup[T]) Subscribe() (chan T, UnsubFn) {
+	if g.scope == nil {
+		return SubscribeToScope[T](g.ctx, g.scope)
+	}
+	return subscribeRing(g.ctx, g.scope, g.key, g.ring, "", func(T) uint64 { return 0 })
+}
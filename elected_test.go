@@ -0,0 +1,62 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElectedGroup(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	group := NewElectedGroup[int](ctx, testScope, "workers")
+
+	leader, unsubLeader := group.Subscribe()
+	follower, unsubFollower := group.Subscribe()
+	defer unsubFollower()
+	// Another group and regular subscribers are not affected by the election.
+	other, unsubOther := NewElectedGroup[int](ctx, testScope, "auditors").Subscribe()
+	defer unsubOther()
+	regular, unsubRegular := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubRegular()
+
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, i, <-leader)
+		assert.Equal(t, i, <-other)
+		assert.Equal(t, i, <-regular)
+	}
+	select {
+	case val := <-follower:
+		t.Fatalf("follower received %d", val)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The follower takes over once the leader leaves.
+	unsubLeader()
+	PublishToScope(ctx, testScope, 3)
+	assert.Equal(t, 3, <-follower)
+	assert.Equal(t, 3, <-other)
+	assert.Equal(t, 3, <-regular)
+}
+
+func TestElectedGroup_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testScope := NewEventScope()
+	group := NewElectedGroup[int](ctx, testScope, "workers")
+
+	leader, _ := group.Subscribe()
+	follower, _ := group.Subscribe()
+	cancel()
+
+	_, ok := <-leader
+	assert.False(t, ok)
+	_, ok = <-follower
+	assert.False(t, ok)
+	assert.NoError(t, testScope.WaitForQuiescence(context.Background()))
+	assert.Zero(t, testScope.Stats().Subscribers)
+}
@@ -0,0 +1,49 @@
+package pubsub
+
+import "context"
+
+// Envelope wraps a published value together with the metadata the event scope attached to it
+// at publish time.
+type Envelope[T any] struct {
+	Value T
+
+	// Seq is the sequence number the scope assigned to this publish. Sequence numbers are
+	// shared by all types published on the scope and increase in publish order.
+	Seq int64
+
+	// Lamport is the scope's Lamport timestamp for this publish. It is zero unless the scope
+	// has the Lamport clock enabled.
+	Lamport int64
+
+	// Clock is the scope's vector clock at the time of this publish. It is nil unless the scope
+	// has the vector clock enabled.
+	Clock VectorClock
+
+	// Identity is the identity of the publisher, as attached to the publish context with
+	// WithIdentity. It is empty if the publisher did not provide one.
+	Identity string
+
+	// CorrelationID is the correlation ID attached to the publish context with
+	// WithCorrelationID, which ties a reply to the request it answers. It is empty if the
+	// publisher did not provide one.
+	CorrelationID string
+}
+
+func newEnvelope[T any](val T, msg message) Envelope[T] {
+	return Envelope[T]{
+		Value:         val,
+		Seq:           msg.seq,
+		Lamport:       msg.lamport,
+		Clock:         msg.vclock,
+		Identity:      msg.identity,
+		CorrelationID: msg.correlationID,
+	}
+}
+
+// SubscribeEnvelopes creates a channel to listen for events of type T published on the provided
+// event scope. Unlike SubscribeToScope, each value is delivered wrapped in an Envelope carrying
+// its publish metadata. When listeners are finished processing these events, the UnsubFn should
+// be called.
+func SubscribeEnvelopes[T any](ctx context.Context, e *EventScope) (chan Envelope[T], UnsubFn) {
+	return subscribe(ctx, e, newEnvelope[T])
+}
@@ -0,0 +1,29 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeEnvelopes(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	ints, unsub := SubscribeEnvelopes[int](ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	env := <-ints
+	assert.Equal(t, 1, env.Value)
+	assert.Equal(t, int64(1), env.Seq)
+
+	// Sequence numbers are shared by every type.
+	require.NoError(t, PublishToScope(ctx, testScope, "two"))
+	require.NoError(t, PublishToScope(ctx, testScope, 3))
+	env = <-ints
+	assert.Equal(t, 3, env.Value)
+	assert.Equal(t, int64(3), env.Seq)
+}
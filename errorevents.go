@@ -0,0 +1,28 @@
+package pubsub
+
+import "context"
+
+// PublishError publishes err on the event scope as an error, so that it reaches the subscribers
+// to errors whatever its concrete type, as PublishToScope[error] does. Publishing a nil error is
+// a no-op.
+func PublishError(ctx context.Context, scope *EventScope, err error) error {
+	if err == nil {
+		return nil
+	}
+	return PublishToScope[error](ctx, scope, err)
+}
+
+// SubscribeToErrors subscribes to the errors published on the event scope with PublishError or
+// PublishToScope[error]. Wrapped errors are received as they were published, so they can be
+// inspected with errors.Is and errors.As.
+func SubscribeToErrors(ctx context.Context, scope *EventScope) (chan error, UnsubFn) {
+	return SubscribeToScope[error](ctx, scope)
+}
+
+// HandleErrors calls fn with every error published on the event scope, like SubscribeCallback,
+// for the common case of a single subscriber to errors. These are the errors published as events;
+// the errors of the scope itself, such as those of failed deliveries, are handled by the function
+// set with WithErrorHandler.
+func HandleErrors(ctx context.Context, scope *EventScope, fn func(error)) UnsubFn {
+	return SubscribeCallback(ctx, scope, fn)
+}
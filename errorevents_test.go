@@ -0,0 +1,48 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+type lookupError struct{ Key string }
+
+func (e lookupError) Error() string { return "lookup " + e.Key }
+
+func TestPublishError(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	errCh, unsub := SubscribeToErrors(ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, PublishError(ctx, testScope, nil))
+	require.NoError(t, PublishError(ctx, testScope, fmt.Errorf("loading user: %w", errNotFound)))
+	err := <-errCh
+	assert.ErrorIs(t, err, errNotFound)
+	assert.EqualError(t, err, "loading user: not found")
+
+	require.NoError(t, PublishError(ctx, testScope, lookupError{Key: "user"}))
+	var lookupErr lookupError
+	require.ErrorAs(t, <-errCh, &lookupErr)
+	assert.Equal(t, "user", lookupErr.Key)
+}
+
+func TestHandleErrors(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	handled := make(chan error, 1)
+	unsub := HandleErrors(ctx, testScope, func(err error) { handled <- err })
+	defer unsub()
+
+	PublishError(ctx, testScope, errNotFound)
+	assert.ErrorIs(t, <-handled, errNotFound)
+}
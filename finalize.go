@@ -0,0 +1,105 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PendingSubscriber is a subscriber that still had messages to receive when Finalize closed its
+// scope.
+type PendingSubscriber struct {
+	ID uuid.UUID
+	// TypeName is the name of the type subscribed to, or "*" for a subscription to every type.
+	TypeName string
+	// Pending is the number of messages the subscriber had not received.
+	Pending int
+}
+
+// FinalizeError is returned by Finalize when the deadline passed before every message published
+// on the scope had been received. Its Subscribers are sorted by type name, then ID.
+type FinalizeError struct {
+	Subscribers []PendingSubscriber
+}
+
+func (e *FinalizeError) Error() string {
+	subs := make([]string, len(e.Subscribers))
+	for i, sub := range e.Subscribers {
+		subs[i] = fmt.Sprintf("%s (%s): %d", sub.ID, sub.TypeName, sub.Pending)
+	}
+	return fmt.Sprintf("pubsub: scope finalized with undelivered messages: %s", strings.Join(subs, ", "))
+}
+
+// Unwrap returns context.DeadlineExceeded, so that errors.Is reports the deadline as the cause.
+func (e *FinalizeError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Finalize shuts the event scope down gracefully, then forcefully: publishing returns
+// ErrScopeClosed from the time it is called, it waits up to deadline, measured with the scope's
+// clock, for the messages already published to be received as with WaitUntilDrained, and then
+// closes the scope with Close whether they were or not. If the deadline passed first, the
+// returned error holds a *FinalizeError listing the subscribers that still had messages to
+// receive, which errors.As finds, joined with the error returned by Close.
+func (e *EventScope) Finalize(deadline time.Duration) error {
+	// Publishes are numbered under seqMu, so none is numbered once the flag is set.
+	e.seqMu.Lock()
+	e.finalizing.Store(true)
+	e.seqMu.Unlock()
+
+	var err error
+	if !e.drainWithin(deadline) {
+		if subs := e.pendingSubscribers(); len(subs) > 0 {
+			err = &FinalizeError{Subscribers: subs}
+		}
+	}
+	return errors.Join(err, e.Close())
+}
+
+// drainWithin waits up to deadline for Len to drop to zero, and reports whether it did.
+func (e *EventScope) drainWithin(deadline time.Duration) bool {
+	expired := e.clock.After(deadline)
+	for {
+		empty := e.backlog.wait()
+		if e.Len() == 0 {
+			return true
+		}
+		select {
+		case <-empty:
+		case <-expired:
+			return e.Len() == 0
+		}
+	}
+}
+
+// pendingSubscribers returns the subscribers of the scope that have messages they have not
+// received.
+func (e *EventScope) pendingSubscribers() []PendingSubscriber {
+	var subs []PendingSubscriber
+	e.knownTopics().Range(func(_, value any) bool {
+		top := value.(*topic)
+		name := wildcardName
+		if top.t.typ != nil {
+			name = top.t.name()
+		}
+		top.subs.Range(func(id, sub any) bool {
+			if n := sub.(*subscriber).pending.Load(); n > 0 {
+				subs = append(subs, PendingSubscriber{ID: id.(uuid.UUID), TypeName: name, Pending: int(n)})
+			}
+			return true
+		})
+		return true
+	})
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].TypeName != subs[j].TypeName {
+			return subs[i].TypeName < subs[j].TypeName
+		}
+		return subs[i].ID.String() < subs[j].ID.String()
+	})
+	return subs
+}
@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalize(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubInts()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, PublishToScope(ctx, testScope, i))
+	}
+	received := make(chan []int)
+	go func() {
+		var vals []int
+		for val := range ints {
+			vals = append(vals, val)
+		}
+		received <- vals
+	}()
+
+	require.NoError(t, testScope.Finalize(time.Second))
+	assert.Equal(t, []int{0, 1, 2}, <-received)
+	assert.ErrorIs(t, PublishToScope(ctx, testScope, 3), ErrScopeClosed)
+}
+
+func TestFinalize_Deadline(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	strs, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	defer unsubStrs()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	require.NoError(t, PublishToScope(ctx, testScope, "received"))
+	assert.Equal(t, "received", <-strs)
+
+	err := testScope.Finalize(10 * time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var finalizeErr *FinalizeError
+	require.True(t, errors.As(err, &finalizeErr))
+	require.Len(t, finalizeErr.Subscribers, 1)
+	assert.Equal(t, "int", finalizeErr.Subscribers[0].TypeName)
+	assert.Equal(t, 2, finalizeErr.Subscribers[0].Pending)
+
+	// Every channel is closed regardless.
+	for range ints {
+	}
+	_, ok := <-strs
+	assert.False(t, ok)
+}
@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventLog records the values published on every event scope of the process, see
+// EnableGlobalLog. Record is called synchronously by every publish, from the publishing
+// goroutine, so it must be safe for concurrent use and should return quickly.
+type EventLog interface {
+	// Record is called with the ID of the scope, as given by EnableVectorClock and empty if it
+	// has none, the name of the type the value was published as, as used by bridges, and the
+	// time of the scope's clock the value was published at.
+	Record(scopeID, typeName string, val any, ts time.Time)
+}
+
+// globalLog holds the EventLog set with EnableGlobalLog, so that it can be swapped atomically.
+type globalLog struct {
+	log EventLog
+}
+
+var global atomic.Pointer[globalLog]
+
+// EnableGlobalLog makes every publish on every event scope of the process that is not rejected
+// record its value in log, replacing the log of an earlier call. It is meant for audit trails
+// that span scopes and for tests that check what was published.
+func EnableGlobalLog(log EventLog) {
+	global.Store(&globalLog{log: log})
+}
+
+// DisableGlobalLog stops recording publishes in the log set with EnableGlobalLog.
+func DisableGlobalLog() {
+	global.Store(nil)
+}
+
+// recordGlobal records a value of type t published on the scope in the global log, if any.
+func (e *EventScope) recordGlobal(t eventType, val any) {
+	if g := global.Load(); g != nil {
+		g.log.Record(e.ID(), t.name(), val, e.clock.Now())
+	}
+}
+
+// LogEntry is a publish recorded by an InMemoryEventLog.
+type LogEntry struct {
+	ScopeID  string
+	TypeName string
+	Value    any
+	Time     time.Time
+}
+
+// InMemoryEventLog is an EventLog that keeps every entry in memory, for tests.
+type InMemoryEventLog struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// NewInMemoryEventLog creates an empty InMemoryEventLog.
+func NewInMemoryEventLog() *InMemoryEventLog {
+	return &InMemoryEventLog{}
+}
+
+func (l *InMemoryEventLog) Record(scopeID, typeName string, val any, ts time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, LogEntry{ScopeID: scopeID, TypeName: typeName, Value: val, Time: ts})
+}
+
+// Events returns a copy of the entries recorded so far, oldest first.
+func (l *InMemoryEventLog) Events() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogEntry(nil), l.entries...)
+}
+
+// Clear removes every entry.
+func (l *InMemoryEventLog) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalLog(t *testing.T) {
+	ctx := context.Background()
+	log := NewInMemoryEventLog()
+	EnableGlobalLog(log)
+	defer DisableGlobalLog()
+
+	first := NewEventScope()
+	first.EnableVectorClock("first")
+	second := NewEventScope()
+	second.EnableVectorClock("second")
+	second.AllowPublish("string", "publisher")
+
+	before := time.Now()
+	require.NoError(t, PublishToScope(ctx, first, 1))
+	require.NoError(t, PublishToScope(ctx, second, 2.5))
+	// Rejected publishes are not recorded.
+	assert.ErrorIs(t, PublishToScope(ctx, second, "rejected"), ErrUnauthorized)
+
+	// Goroutines left over by other tests may publish on scopes without an ID.
+	var events []LogEntry
+	for _, entry := range log.Events() {
+		if entry.ScopeID != "" {
+			events = append(events, entry)
+		}
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, LogEntry{ScopeID: "first", TypeName: "int", Value: 1, Time: events[0].Time}, events[0])
+	assert.Equal(t, LogEntry{ScopeID: "second", TypeName: "float64", Value: 2.5, Time: events[1].Time}, events[1])
+	assert.False(t, events[0].Time.Before(before))
+
+	log.Clear()
+	assert.Empty(t, log.Events())
+
+	DisableGlobalLog()
+	require.NoError(t, PublishToScope(ctx, first, 3))
+	for _, entry := range log.Events() {
+		assert.NotEqual(t, "first", entry.ScopeID)
+	}
+}
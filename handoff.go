@@ -0,0 +1,154 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownSubscription is returned by ResumeSubscription for an ID that was not restored with
+// RestoreSubscriberState, or that has already been resumed.
+var ErrUnknownSubscription = errors.New("pubsub: unknown subscription")
+
+// subscriberState is the state of a subscription handed from one process to another.
+type subscriberState struct {
+	ID        uuid.UUID `json:"id"`
+	TypeName  string    `json:"type"`
+	Watermark int64     `json:"watermark"`
+}
+
+// handoffState is the blob written by SerializeSubscriberState.
+type handoffState struct {
+	// Seq is the sequence number of the last message published on the scope.
+	Seq         int64             `json:"seq"`
+	Subscribers []subscriberState `json:"subscribers"`
+}
+
+// handoffRegistry holds the subscriptions of a scope whose state can be handed off: the live
+// Subscriptions, and the restored ones that have not been resumed yet.
+type handoffRegistry struct {
+	mu       sync.Mutex
+	live     map[uuid.UUID]trackedSubscription
+	restored map[uuid.UUID]subscriberState
+}
+
+type trackedSubscription struct {
+	typeName  string
+	watermark func() int64
+}
+
+// trackSubscription registers sub with the scope for SerializeSubscriberState until it is
+// unsubscribed.
+func trackSubscription[T any](e *EventScope, sub *Subscription[T]) {
+	if e == nil {
+		return
+	}
+	h := &e.handoff
+	h.mu.Lock()
+	if h.live == nil {
+		h.live = make(map[uuid.UUID]trackedSubscription)
+	}
+	h.live[sub.id] = trackedSubscription{typeName: typeName[T](), watermark: sub.delivered.Load}
+	h.mu.Unlock()
+
+	unsub := sub.unsub
+	sub.unsub = func() {
+		untrackSubscription(e, sub.id)
+		unsub()
+	}
+}
+
+// untrackSubscription stops including the subscription with the given ID in the scope's state.
+func untrackSubscription(e *EventScope, id uuid.UUID) {
+	h := &e.handoff
+	h.mu.Lock()
+	delete(h.live, id)
+	h.mu.Unlock()
+}
+
+// SerializeSubscriberState returns the state of the event scope's Subscriptions, made with
+// NewSubscription and NewSubscriptionFromWatermark, so that another process can resume them with
+// RestoreSubscriberState and ResumeSubscription during a rolling restart: their IDs, the names of
+// their types and their watermarks, along with the scope's sequence number. Subscriptions that
+// were restored but not resumed yet are included as well.
+//
+// The new process resumes each subscription after the last value it received; a value that was
+// being sent on C as the state was serialized may be received again. The values published in
+// between have to be replayed from a message store shared by both processes, see
+// WithStore. The state should be serialized once the scope has stopped publishing, so that the
+// new process numbers its messages after the old one's.
+func (e *EventScope) SerializeSubscriberState() ([]byte, error) {
+	state := handoffState{Seq: e.seq.Load()}
+
+	e.handoff.mu.Lock()
+	for id, sub := range e.handoff.live {
+		state.Subscribers = append(state.Subscribers, subscriberState{
+			ID:        id,
+			TypeName:  sub.typeName,
+			Watermark: sub.watermark(),
+		})
+	}
+	for _, sub := range e.handoff.restored {
+		state.Subscribers = append(state.Subscribers, sub)
+	}
+	e.handoff.mu.Unlock()
+
+	return json.Marshal(state)
+}
+
+// RestoreSubscriberState reads the state written by SerializeSubscriberState in another process,
+// so that its subscriptions can be resumed with ResumeSubscription. The scope's numbering
+// continues after the other scope's, like it does after a journal with UseJournal. Go cannot
+// create a subscription to a type it only knows by name, so subscriptions are recreated when they
+// are resumed.
+func (e *EventScope) RestoreSubscriberState(data []byte) error {
+	var state handoffState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	e.seqMu.Lock()
+	if e.seq.Load() < state.Seq {
+		e.seq.Store(state.Seq)
+	}
+	e.seqMu.Unlock()
+
+	e.handoff.mu.Lock()
+	defer e.handoff.mu.Unlock()
+	if e.handoff.restored == nil {
+		e.handoff.restored = make(map[uuid.UUID]subscriberState)
+	}
+	for _, sub := range state.Subscribers {
+		e.handoff.restored[sub.ID] = sub
+	}
+	return nil
+}
+
+// ResumeSubscription recreates the subscription with the given ID from the state restored with
+// RestoreSubscriberState. It starts after the last value the subscription received in the other
+// process, as with NewSubscriptionFromWatermark, and keeps its ID. It returns
+// ErrUnknownSubscription if no such subscription was restored, and an error if the subscription
+// was to a type other than T.
+func ResumeSubscription[T any](ctx context.Context, e *EventScope, id uuid.UUID) (*Subscription[T], error) {
+	if e == nil {
+		return nil, ErrNilScope
+	}
+
+	e.handoff.mu.Lock()
+	state, ok := e.handoff.restored[id]
+	if ok && state.TypeName != typeName[T]() {
+		e.handoff.mu.Unlock()
+		return nil, fmt.Errorf("pubsub: subscription %s is to %s, not %s", id, state.TypeName, typeName[T]())
+	}
+	delete(e.handoff.restored, id)
+	e.handoff.mu.Unlock()
+	if !ok {
+		return nil, ErrUnknownSubscription
+	}
+
+	return subscribeFromWatermark[T](ctx, e, state.Watermark, id), nil
+}
@@ -0,0 +1,78 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriberStateHandoff(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	old := NewEventScope(WithStore(store))
+
+	ints := NewSubscription[int](ctx, old)
+	strs := NewSubscription[string](ctx, old)
+	gone := NewSubscription[int](ctx, old)
+	gone.Unsubscribe()
+
+	PublishToScope(ctx, old, 1)
+	assert.Equal(t, 1, <-ints.C)
+	PublishToScope(ctx, old, "a")
+	assert.Equal(t, "a", <-strs.C)
+
+	// Values published before the handoff that the subscriptions have not received yet are
+	// replayed from the store by the new scope.
+	PublishToScope(ctx, old, 2)
+	PublishToScope(ctx, old, "b")
+
+	state, err := old.SerializeSubscriberState()
+	require.NoError(t, err)
+	assert.NoError(t, old.Close())
+	// Unsubscribed subscriptions are not handed off.
+	assert.Contains(t, string(state), ints.ID().String())
+	assert.NotContains(t, string(state), gone.ID().String())
+
+	scope := NewEventScope(WithStore(store))
+	defer scope.Close()
+	require.NoError(t, scope.RestoreSubscriberState(state))
+
+	_, err = ResumeSubscription[string](ctx, scope, ints.ID())
+	assert.Error(t, err)
+	_, err = ResumeSubscription[int](ctx, scope, uuid.New())
+	assert.ErrorIs(t, err, ErrUnknownSubscription)
+
+	resumed, err := ResumeSubscription[int](ctx, scope, ints.ID())
+	require.NoError(t, err)
+	defer resumed.Unsubscribe()
+	assert.Equal(t, ints.ID(), resumed.ID())
+	assert.Equal(t, 2, <-resumed.C)
+	_, err = ResumeSubscription[int](ctx, scope, ints.ID())
+	assert.ErrorIs(t, err, ErrUnknownSubscription)
+
+	// The new scope numbers its messages after the old one's.
+	PublishToScope(ctx, scope, 3)
+	assert.Equal(t, 3, <-resumed.C)
+	assert.Equal(t, int64(5), resumed.Watermark())
+
+	// Subscriptions that have not been resumed yet are handed off again.
+	state, err = scope.SerializeSubscriberState()
+	require.NoError(t, err)
+	assert.Contains(t, string(state), strs.ID().String())
+
+	next := NewEventScope(WithStore(store))
+	defer next.Close()
+	require.NoError(t, next.RestoreSubscriberState(state))
+	resumedStrs, err := ResumeSubscription[string](ctx, next, strs.ID())
+	require.NoError(t, err)
+	defer resumedStrs.Unsubscribe()
+	assert.Equal(t, "b", <-resumedStrs.C)
+}
+
+func TestRestoreSubscriberState_Invalid(t *testing.T) {
+	scope := NewEventScope()
+	assert.Error(t, scope.RestoreSubscriberState([]byte("{")))
+}
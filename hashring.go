@@ -0,0 +1,308 @@
+package pubsub
+
+import (
+	"context"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ringReplicas is the number of points each member has on a hash ring. More points spread the
+// hashes more evenly between members.
+const ringReplicas = 64
+
+// hashRing routes messages between the members of a group with consistent hashing, so that a
+// hash keeps being routed to the same member while it is subscribed, and a member joining or
+// leaving only moves the hashes next to its own points.
+type hashRing struct {
+	// elected rings route every message to their earliest member, the leader of an
+	// ElectedGroup, instead of hashing.
+	elected bool
+
+	mu      sync.Mutex
+	members []*ringMember // in order of registration
+	points  []ringPoint   // sorted by hash
+}
+
+type ringMember struct {
+	sub *subscriber
+
+	// hash hashes the value of a message onto the ring.
+	hash func(any) uint64
+
+	// seq numbers the messages routed to the member, which receives them in that order.
+	seq int64
+}
+
+type ringPoint struct {
+	hash   uint64
+	member *ringMember
+}
+
+// ringHash hashes s onto a ring. FNV alone maps strings that only differ in their last bytes
+// close to each other, so its result is mixed with the MurmurHash3 finalizer.
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// add places m on the ring, at points derived from name.
+func (r *hashRing) add(m *ringMember, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.members = append(r.members, m)
+	if r.elected {
+		return
+	}
+	for i := 0; i < ringReplicas; i++ {
+		r.points = append(r.points, ringPoint{hash: ringHash(name + "#" + strconv.Itoa(i)), member: m})
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i].hash < r.points[j].hash })
+}
+
+func (r *hashRing) remove(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, m := range r.members {
+		if m.sub.id == id {
+			r.members = append(r.members[:i:i], r.members[i+1:]...)
+			break
+		}
+	}
+	points := r.points[:0:0]
+	for _, p := range r.points {
+		if p.member.sub.id != id {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+}
+
+// hashOf hashes val with the hash function of the earliest member still subscribed. The boolean
+// is false if there is none.
+func (r *hashRing) hashOf(val any) (uint64, bool) {
+	r.mu.Lock()
+	if len(r.members) == 0 {
+		r.mu.Unlock()
+		return 0, false
+	}
+	hash := r.members[0].hash
+	r.mu.Unlock()
+	return hash(val), true
+}
+
+// route picks the member for h and numbers the message for it. It returns nil if the ring has no
+// members.
+func (r *hashRing) route(h uint64) (*subscriber, int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.elected && len(r.members) > 0 {
+		m := r.members[0]
+		m.seq++
+		return m.sub, m.seq
+	}
+	if len(r.points) == 0 {
+		return nil, 0
+	}
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	m := r.points[i].member
+	m.seq++
+	return m.sub, m.seq
+}
+
+// ringSet holds the hash rings messages of a type are routed through: the ring of its sticky
+// subscribers, one ring per consistent hashing group and one per elected group.
+type ringSet struct {
+	mu      sync.RWMutex
+	sticky  *hashRing
+	groups  map[string]*hashRing // by group ID
+	elected map[string]*hashRing // by group ID
+}
+
+// ringsOf returns the ring set of typ, creating it if it does not exist yet.
+func (e *EventScope) ringsOf(typ reflect.Type) *ringSet {
+	v, _ := e.rings.LoadOrStore(typ, &ringSet{})
+	return v.(*ringSet)
+}
+
+// stickyRing returns the ring of the sticky subscribers in the set, creating it if needed.
+func (s *ringSet) stickyRing() *hashRing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sticky == nil {
+		s.sticky = &hashRing{}
+	}
+	return s.sticky
+}
+
+// group returns the ring of the group with the given ID, creating it if needed.
+func (s *ringSet) group(id string) *hashRing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.groups == nil {
+		s.groups = make(map[string]*hashRing)
+	}
+	r, ok := s.groups[id]
+	if !ok {
+		r = &hashRing{}
+		s.groups[id] = r
+	}
+	return r
+}
+
+// electedGroup returns the ring of the elected group with the given ID, creating it if needed.
+func (s *ringSet) electedGroup(id string) *hashRing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.elected == nil {
+		s.elected = make(map[string]*hashRing)
+	}
+	r, ok := s.elected[id]
+	if !ok {
+		r = &hashRing{elected: true}
+		s.elected[id] = r
+	}
+	return r
+}
+
+// ringHashing is the position of a message on one of the rings of its type.
+type ringHashing struct {
+	ring *hashRing
+	hash uint64
+}
+
+// ringRoute is a ring member a message has been routed to, with the number of the message among
+// the messages routed to that member.
+type ringRoute struct {
+	sub *subscriber
+	seq int64
+}
+
+// hashRings hashes msg onto every ring of its type, before it is numbered. Hash functions are
+// user code, so they are not called with seqMu held.
+func (e *EventScope) hashRings(t eventType, msg *message) {
+	v, ok := e.rings.Load(t.typ)
+	if !ok {
+		return
+	}
+	s := v.(*ringSet)
+	s.mu.RLock()
+	rings := make([]*hashRing, 0, len(s.groups)+len(s.elected)+1)
+	if s.sticky != nil {
+		rings = append(rings, s.sticky)
+	}
+	for _, r := range s.groups {
+		rings = append(rings, r)
+	}
+	for _, r := range s.elected {
+		rings = append(rings, r)
+	}
+	s.mu.RUnlock()
+
+	for _, r := range rings {
+		var h uint64
+		ok := true
+		if r == s.sticky && msg.keyed {
+			h = ringHash(msg.stickyKey)
+		} else {
+			h, ok = r.hashOf(msg.val)
+		}
+		if ok {
+			msg.hashes = append(msg.hashes, ringHashing{ring: r, hash: h})
+		}
+	}
+}
+
+// route decides which member of each ring msg was hashed onto receives it. It must be called with
+// seqMu held, so that messages are numbered in the order they are published.
+func (e *EventScope) route(msg *message) {
+	for _, h := range msg.hashes {
+		if sub, seq := h.ring.route(h.hash); sub != nil {
+			msg.routes = append(msg.routes, ringRoute{sub: sub, seq: seq})
+		}
+	}
+	msg.hashes = nil
+}
+
+// hashGroupTopicKey is the key the members of a consistent hashing group for typ are registered
+// under. Like sticky subscribers, they are kept apart from the regular subscribers of the type.
+type hashGroupTopicKey struct {
+	typ   reflect.Type
+	group string
+}
+
+// subscribeRing subscribes to T under key as a member of ring, placed at points derived from name,
+// or from the ID of the subscriber if name is empty. The member leaves the ring when it
+// unsubscribes, when ctx is canceled and when the scope is closed.
+func subscribeRing[T any](ctx context.Context, e *EventScope, key any, ring *hashRing, name string, hash func(T) uint64) (chan T, UnsubFn) {
+	t := eventTypeOf[T]()
+	t.key = key
+
+	member := &ringMember{hash: func(val any) uint64 {
+		// val is nil for the zero value of an interface type.
+		typed, _ := val.(T)
+		return hash(typed)
+	}}
+	registered := func(c *subscribeConfig) {
+		c.registered = func(_ *topic, s *subscriber) {
+			member.sub = s
+			if name == "" {
+				ring.add(member, s.id.String())
+			} else {
+				ring.add(member, name)
+			}
+		}
+	}
+
+	ch, unsub := subscribeKey(ctx, e, t, func(val T, _ message) T { return val }, WithOrdered(), registered)
+	sub := member.sub
+	if sub == nil {
+		return ch, unsub
+	}
+
+	go func() {
+		<-sub.done
+		ring.remove(sub.id)
+	}()
+	return ch, func() {
+		ring.remove(sub.id)
+		unsub()
+	}
+}
+
+// SubscribeConsistentHash subscribes to T on the event scope as the member nodeID of the group
+// groupID. Each value of T is delivered to a single member of the group, chosen by consistent
+// hashing on the hash hashFn returns for it, so that values with the same hash reach the same
+// member, in publish order, for as long as it stays subscribed. When a member joins or leaves
+// the group, only the hashes next to its points on the ring are moved to another member.
+//
+// The points of a member are derived from its nodeID, so a member that resubscribes with the
+// same nodeID, for example after a restart, is assigned the same hashes again. The nodeIDs of a
+// group should be unique, and its members are expected to use the same hashFn. Groups are
+// independent of each other and of the sticky subscribers of T, and regular subscribers of T
+// still receive every value.
+func SubscribeConsistentHash[T any](ctx context.Context, scope *EventScope, groupID string, nodeID string, hashFn func(T) uint64) (chan T, UnsubFn) {
+	if scope == nil {
+		return SubscribeToScope[T](ctx, scope)
+	}
+	typ := eventTypeOf[T]().typ
+	ring := scope.ringsOf(typ).group(groupID)
+	return subscribeRing(ctx, scope, hashGroupTopicKey{typ: typ, group: groupID}, ring, "node:"+nodeID, hashFn)
+}
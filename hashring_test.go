@@ -0,0 +1,138 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// groupMembers subscribes the given nodes to the group and records which node received each
+// value.
+type groupMembers struct {
+	mu       sync.Mutex
+	received map[uint64]string
+	unsubs   map[string]UnsubFn
+	wg       sync.WaitGroup
+}
+
+func (g *groupMembers) join(ctx context.Context, e *EventScope, group, node string) {
+	ch, unsub := SubscribeConsistentHash(ctx, e, group, node, func(v uint64) uint64 { return v })
+	g.unsubs[node] = unsub
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		for v := range ch {
+			g.mu.Lock()
+			g.received[v] = node
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// assignments publishes every hash in 0..n and returns the node each was delivered to.
+func (g *groupMembers) assignments(t *testing.T, ctx context.Context, e *EventScope, n int) map[uint64]string {
+	g.mu.Lock()
+	g.received = make(map[uint64]string)
+	g.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, PublishToScope(ctx, e, ringHash(fmt.Sprint(i))))
+	}
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return len(g.received) == n
+	}, time.Second, time.Millisecond)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.received
+}
+
+func TestSubscribeConsistentHash(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	const n = 500
+
+	g := &groupMembers{unsubs: make(map[string]UnsubFn)}
+	for _, node := range []string{"a", "b", "c"} {
+		g.join(ctx, testScope, "workers", node)
+	}
+	before := g.assignments(t, ctx, testScope, n)
+	used := make(map[string]bool)
+	for _, node := range before {
+		used[node] = true
+	}
+	assert.Len(t, used, 3)
+
+	// A new member only takes hashes over, it does not move them between the others.
+	g.join(ctx, testScope, "workers", "d")
+	after := g.assignments(t, ctx, testScope, n)
+	moved := 0
+	for h, node := range after {
+		if node != before[h] {
+			assert.Equal(t, "d", node)
+			moved++
+		}
+	}
+	assert.Greater(t, moved, 0)
+	assert.Less(t, moved, n/2)
+
+	// Once it leaves, its hashes go back to where they were.
+	g.unsubs["d"]()
+	assert.Equal(t, before, g.assignments(t, ctx, testScope, n))
+
+	for _, unsub := range g.unsubs {
+		unsub()
+	}
+	g.wg.Wait()
+}
+
+func TestSubscribeConsistentHash_Groups(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	first, unsubFirst := SubscribeConsistentHash(ctx, testScope, "first", "node", func(v int) uint64 { return uint64(v) })
+	defer unsubFirst()
+	second, unsubSecond := SubscribeConsistentHash(ctx, testScope, "second", "node", func(v int) uint64 { return uint64(v) })
+	defer unsubSecond()
+	all, unsubAll := SubscribeToScope[int](ctx, testScope)
+	defer unsubAll()
+
+	require.NoError(t, PublishToScope(ctx, testScope, 7))
+	for _, ch := range []chan int{first, second, all} {
+		select {
+		case v := <-ch:
+			assert.Equal(t, 7, v)
+		case <-time.After(time.Second):
+			t.Fatal("value was not delivered")
+		}
+	}
+}
+
+func TestSubscribeConsistentHash_Restart(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	const n = 200
+
+	g := &groupMembers{unsubs: make(map[string]UnsubFn)}
+	for _, node := range []string{"a", "b"} {
+		g.join(ctx, testScope, "workers", node)
+	}
+	before := g.assignments(t, ctx, testScope, n)
+
+	// A member that comes back under the same node ID gets the same hashes.
+	g.unsubs["b"]()
+	g.join(ctx, testScope, "workers", "b")
+	assert.Equal(t, before, g.assignments(t, ctx, testScope, n))
+
+	for _, unsub := range g.unsubs {
+		unsub()
+	}
+	g.wg.Wait()
+}
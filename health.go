@@ -0,0 +1,143 @@
+package pubsub
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ScopeStats is a snapshot of the activity of an event scope.
+type ScopeStats struct {
+	// Subscribers is the number of active subscriptions, including subscriptions to every
+	// type such as those of bridges and plugins.
+	Subscribers int `json:"subscriber_count"`
+
+	// Published is the number of values published on the scope.
+	Published int64 `json:"published_total"`
+
+	// Dropped is the number of messages that never reached a subscriber that was still
+	// receiving, because the publish context was canceled or a spilled message could not be
+	// read back.
+	Dropped int64 `json:"dropped_total"`
+
+	// Pending is the number of messages that have not been received yet, as returned by Len.
+	Pending int `json:"pending"`
+
+	// Types lists the names of the types that have at least one subscriber, in sorted order.
+	Types []string `json:"types"`
+}
+
+// Stats returns a snapshot of the scope's activity.
+func (e *EventScope) Stats() ScopeStats {
+	stats := ScopeStats{
+		Published: e.published.Load(),
+		Dropped:   e.dropped.Load(),
+		Pending:   e.Len(),
+		Types:     []string{},
+	}
+
+	for name, n := range e.subscriberCounts() {
+		stats.Subscribers += n
+		if name != wildcardName {
+			stats.Types = append(stats.Types, name)
+		}
+	}
+	sort.Strings(stats.Types)
+	return stats
+}
+
+// wildcardName stands for the subscribers to every type in subscriberCounts.
+const wildcardName = "*"
+
+// subscriberCounts returns the number of subscribers of every type that has any, by type name.
+// Subscribers to every type are counted under wildcardName.
+func (e *EventScope) subscriberCounts() map[string]int {
+	counts := make(map[string]int)
+	e.knownTopics().Range(func(_, value any) bool {
+		top := value.(*topic)
+		n := 0
+		top.subs.Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		if n == 0 {
+			return true
+		}
+		name := wildcardName
+		if top.t.typ != nil {
+			name = top.t.name()
+		}
+		counts[name] += n
+		return true
+	})
+	return counts
+}
+
+// String describes the scope for debug logs, such as
+// EventScope{types: [int, string], subscribers: {int: 3, string: 1}, published: 1024, dropped: 5}.
+// Subscribers to every type are listed under "*".
+func (e *EventScope) String() string {
+	if e == nil {
+		return "EventScope(nil)"
+	}
+
+	counts := e.subscriberCounts()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("EventScope{types: [")
+	first := true
+	for _, name := range names {
+		if name == wildcardName {
+			continue
+		}
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+		b.WriteString(name)
+	}
+	b.WriteString("], subscribers: {")
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s: %d", name, counts[name])
+	}
+	fmt.Fprintf(&b, "}, published: %d, dropped: %d}", e.published.Load(), e.dropped.Load())
+	return b.String()
+}
+
+type healthResponse struct {
+	Status      string   `json:"status"`
+	Subscribers int      `json:"subscriber_count"`
+	Published   int64    `json:"published_total"`
+	Dropped     int64    `json:"dropped_total"`
+	Types       []string `json:"types"`
+}
+
+// HealthHandler returns an http.Handler that reports the health of the scope as JSON, for use as
+// a liveness or readiness probe. The status is "ok", or "degraded" once messages have been
+// dropped.
+func (e *EventScope) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stats := e.Stats()
+		resp := healthResponse{
+			Status:      "ok",
+			Subscribers: stats.Subscribers,
+			Published:   stats.Published,
+			Dropped:     stats.Dropped,
+			Types:       stats.Types,
+		}
+		if resp.Dropped > 0 {
+			resp.Status = "degraded"
+		}
+
+		writeJSON(w, resp)
+	})
+}
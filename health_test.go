@@ -0,0 +1,87 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkHealth(t *testing.T, e *EventScope) map[string]any {
+	rec := httptest.NewRecorder()
+	e.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestHealthHandler(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	assert.Equal(t, map[string]any{
+		"status":           "ok",
+		"subscriber_count": 0.0,
+		"published_total":  0.0,
+		"dropped_total":    0.0,
+		"types":            []any{},
+	}, checkHealth(t, testScope))
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	_, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	_, unsubOther := SubscribeToScope[string](ctx, testScope)
+	defer unsubOther()
+
+	PublishToScope(ctx, testScope, 1)
+	<-ints
+	assert.Equal(t, map[string]any{
+		"status":           "ok",
+		"subscriber_count": 3.0,
+		"published_total":  1.0,
+		"dropped_total":    0.0,
+		"types":            []any{"int", "string"},
+	}, checkHealth(t, testScope))
+
+	// The remaining subscriber never receives its values, so once it holds one, the next are
+	// dropped when their publish context is canceled.
+	unsubStrs()
+	assert.Eventually(t, func() bool {
+		publishCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+		PublishToScope(publishCtx, testScope, "dropped")
+		<-publishCtx.Done()
+		return checkHealth(t, testScope)["status"] == "degraded"
+	}, time.Second, time.Millisecond)
+
+	body := checkHealth(t, testScope)
+	assert.GreaterOrEqual(t, body["dropped_total"], 1.0)
+	assert.Equal(t, 2.0, body["subscriber_count"])
+}
+
+func TestEventScope_String(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	assert.Equal(t, "EventScope{types: [], subscribers: {}, published: 0, dropped: 0}", testScope.String())
+
+	_, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	_, unsubMore := SubscribeToScope[int](ctx, testScope)
+	defer unsubMore()
+	_, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	defer unsubStrs()
+	_, unsubAll := subscribeAll(ctx, testScope)
+	defer unsubAll()
+	PublishToScope(ctx, testScope, 1.5)
+
+	assert.Equal(t, "EventScope{types: [int, string], subscribers: {*: 1, int: 2, string: 1}, published: 1, dropped: 0}", testScope.String())
+	assert.Equal(t, "EventScope(nil)", (*EventScope)(nil).String())
+}
@@ -0,0 +1,105 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// WithHistory makes the event scope keep the last n values published of each type in a ring
+// buffer, so that subscribers created with SubscribeRecent start with them. n must be positive.
+func WithHistory(n int) EventScopeOption {
+	if n <= 0 {
+		panic("pubsub: history size must be positive")
+	}
+	return func(e *EventScope) {
+		e.history = &historyLog{
+			size:  n,
+			byKey: make(map[any]*historyRing),
+		}
+	}
+}
+
+type historyLog struct {
+	size int
+
+	mu    sync.RWMutex
+	byKey map[any]*historyRing
+}
+
+// historyRing holds the last messages of a single type. Once full, msgs[start] is the oldest.
+type historyRing struct {
+	msgs  []message
+	start int
+}
+
+// record adds msg to the history of its type, overwriting the oldest message once the history is
+// full. It is called with seqMu held, so that histories are in sequence order.
+func (h *historyLog) record(t eventType, msg message) {
+	// Like replayed messages, messages kept in the history are not delivered through their
+	// topics or acknowledged.
+	msg.top, msg.allTop, msg.acks = nil, nil, nil
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.byKey[t.key]
+	if !ok {
+		r = &historyRing{msgs: make([]message, 0, h.size)}
+		h.byKey[t.key] = r
+	}
+	if len(r.msgs) < h.size {
+		r.msgs = append(r.msgs, msg)
+		return
+	}
+	r.msgs[r.start] = msg
+	r.start = (r.start + 1) % h.size
+}
+
+// recent returns up to the n most recent messages kept for key, oldest first.
+func (h *historyLog) recent(key any, n int) []message {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r, ok := h.byKey[key]
+	if !ok || n <= 0 {
+		return nil
+	}
+	n = min(n, len(r.msgs))
+	out := make([]message, 0, n)
+	for i := len(r.msgs) - n; i < len(r.msgs); i++ {
+		out = append(out, r.msgs[(r.start+i)%len(r.msgs)])
+	}
+	return out
+}
+
+// SubscribeRecent subscribes to T on the event scope and first delivers the n most recent values
+// of T published before the subscription, oldest first, followed by live values, without gaps or
+// duplicates between the two. Values are delivered in publish order, as with WithOrdered. Only
+// the values kept by WithHistory can be delivered, so n is capped at its size, and a scope
+// created without it only delivers live values.
+func SubscribeRecent[T any](ctx context.Context, scope *EventScope, n int) (chan T, UnsubFn) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// As with SubscribeFromWatermark, the history is read while the live subscriber is
+	// registered, so that every value ends up in exactly one of the two.
+	var recent []message
+	snapshot := func(c *subscribeConfig) {
+		c.registered = func(*topic, *subscriber) {
+			if scope.history != nil {
+				recent = scope.history.recent(eventTypeOf[T]().key, n)
+			}
+		}
+	}
+	live, unsub := subscribe(ctx, scope, func(_ T, msg message) message { return msg }, WithOrdered(), snapshot)
+
+	var cutoff int64
+	if len(recent) > 0 {
+		cutoff = recent[len(recent)-1].seq
+	}
+	sub := newSubscription[T](0, func() {
+		cancel()
+		unsub()
+	})
+	go sub.forward(ctx, recent, live, cutoff)
+	return sub.C, sub.Unsubscribe
+}
@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeRecent(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithHistory(3))
+	for i := 1; i <= 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	PublishToScope(ctx, testScope, "other")
+
+	recent, unsubRecent := SubscribeRecent[int](ctx, testScope, 2)
+	defer unsubRecent()
+	all, unsubAll := SubscribeRecent[int](ctx, testScope, 10)
+	defer unsubAll()
+
+	PublishToScope(ctx, testScope, 6)
+	for _, want := range []int{4, 5, 6} {
+		assert.Equal(t, want, <-recent)
+	}
+	for _, want := range []int{3, 4, 5, 6} {
+		assert.Equal(t, want, <-all)
+	}
+}
+
+func TestSubscribeRecent_WithoutHistory(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	PublishToScope(ctx, testScope, 1)
+
+	testingCh, unsub := SubscribeRecent[int](ctx, testScope, 1)
+	defer unsub()
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 2, <-testingCh)
+}
+
+func TestSubscribeRecent_ConcurrentPublish(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithHistory(10))
+
+	const count = 200
+	go func() {
+		for i := 0; i < count; i++ {
+			PublishToScope(ctx, testScope, i)
+		}
+	}()
+	testingCh, unsub := SubscribeRecent[int](ctx, testScope, 10)
+	defer unsub()
+	prev := <-testingCh
+	for prev < count-1 {
+		val := <-testingCh
+		assert.Equal(t, prev+1, val)
+		prev = val
+	}
+}
@@ -0,0 +1,18 @@
+package pubsub
+
+import "context"
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity of the caller. Event scopes use the
+// identity to enforce the rules registered with AllowPublish and AllowSubscribe, and attach the
+// publisher's identity to every message so subscribers can read it from the Envelope.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity attached to ctx with WithIdentity.
+func IdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
@@ -0,0 +1,33 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityFromContext(t *testing.T) {
+	_, ok := IdentityFromContext(context.Background())
+	assert.False(t, ok)
+
+	identity, ok := IdentityFromContext(WithIdentity(context.Background(), "orders"))
+	assert.True(t, ok)
+	assert.Equal(t, "orders", identity)
+}
+
+func TestIdentity_Envelope(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeEnvelopes[int](ctx, testScope)
+	defer unsub()
+
+	PublishToScope(WithIdentity(ctx, "orders"), testScope, 1)
+	env := <-testingCh
+	assert.Equal(t, "orders", env.Identity)
+
+	PublishToScope(ctx, testScope, 2)
+	env = <-testingCh
+	assert.Empty(t, env.Identity)
+}
@@ -0,0 +1,91 @@
+package pubsub
+
+import "sort"
+
+// WatchInterest reports which types the scope is interested in, that is, which types have at
+// least one subscriber. fn is first called with every type that currently has subscribers, and
+// then whenever a type gains its first subscriber or loses its last one. Subscriptions that only
+// relay values elsewhere, such as the ones made by BridgeType, do not count. Types are identified
+// by their name, as used by bridges.
+//
+// fn is called with a lock held that subscribing and unsubscribing also take, so it must not block
+// and must not subscribe or unsubscribe. The returned function stops the reports.
+func (e *EventScope) WatchInterest(fn func(typeName string, interested bool)) (cancel func()) {
+	e.interestMu.Lock()
+	defer e.interestMu.Unlock()
+
+	names := make([]string, 0, len(e.interest))
+	for name := range e.interest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fn(name, true)
+	}
+
+	if e.interestWatchers == nil {
+		e.interestWatchers = make(map[*func(string, bool)]struct{})
+	}
+	e.interestWatchers[&fn] = struct{}{}
+	return func() {
+		e.interestMu.Lock()
+		delete(e.interestWatchers, &fn)
+		e.interestMu.Unlock()
+	}
+}
+
+// changed is called after a subscriber has been added to or removed from the topic.
+func (t *topic) changed() {
+	t.syncDemand()
+	t.syncInterest()
+}
+
+// syncInterest reports the topic to the scope's interest watchers if it gained its first active
+// subscriber or lost its last one.
+func (t *topic) syncInterest() {
+	if t.t.typ == nil {
+		// Subscribers to every type are not interested in any type in particular.
+		return
+	}
+
+	t.interestMu.Lock()
+	defer t.interestMu.Unlock()
+
+	interested := false
+	t.subs.Range(func(_, value any) bool {
+		interested = !value.(*subscriber).passive
+		return !interested
+	})
+	if interested == t.interested {
+		return
+	}
+	t.interested = interested
+	t.e.interestChanged(t.t.name(), interested)
+}
+
+// interestChanged counts the topics of a type with active subscribers, since the regular and the
+// sticky subscribers of a type, as well as the members of its consistent hashing groups, make the
+// scope interested in it.
+func (e *EventScope) interestChanged(name string, interested bool) {
+	e.interestMu.Lock()
+	defer e.interestMu.Unlock()
+
+	if e.interest == nil {
+		e.interest = make(map[string]int)
+	}
+	if interested {
+		e.interest[name]++
+		if e.interest[name] > 1 {
+			return
+		}
+	} else {
+		e.interest[name]--
+		if e.interest[name] > 0 {
+			return
+		}
+		delete(e.interest, name)
+	}
+	for fn := range e.interestWatchers {
+		(*fn)(name, interested)
+	}
+}
@@ -0,0 +1,50 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchInterest(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	_, unsubInts := SubscribeToScope[int](ctx, testScope)
+
+	type change struct {
+		name       string
+		interested bool
+	}
+	var changes []change
+	stop := testScope.WatchInterest(func(name string, interested bool) {
+		changes = append(changes, change{name, interested})
+	})
+	assert.Equal(t, []change{{"int", true}}, changes)
+
+	_, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	_, unsubSticky := SubscribeSticky(ctx, testScope, func(s string) string { return s })
+	_, unsubMore := SubscribeToScope[string](ctx, testScope)
+	assert.Equal(t, []change{{"int", true}, {"string", true}}, changes)
+
+	// Bridge subscriptions and subscriptions to every type do not count.
+	b := NewBridge(testScope, &failingTransport{})
+	unsubBridge := BridgeType[float64](ctx, b)
+	defer unsubBridge()
+	_, unsubAll := subscribeAll(ctx, testScope)
+	defer unsubAll()
+	assert.Len(t, changes, 2)
+
+	unsubStrs()
+	unsubMore()
+	assert.Len(t, changes, 2)
+	unsubSticky()
+	unsubInts()
+	assert.Equal(t, []change{{"int", true}, {"string", true}, {"string", false}, {"int", false}}, changes)
+
+	stop()
+	_, unsubInts = SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	assert.Len(t, changes, 4)
+}
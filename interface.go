@@ -0,0 +1,53 @@
+package pubsub
+
+import (
+	"context"
+	"reflect"
+)
+
+// SubscribeInterface subscribes to every value published on the event scope whose type
+// implements the interface type I, whatever type it was published as, for subscribers that care
+// about what an event can do rather than what it is. Whether a type implements I is checked the
+// first time a value of that type is published, and remembered for the later ones. It panics if
+// I is not an interface type.
+//
+// As with subscriptions to every type, only the types the identity attached to ctx may
+// subscribe to are received. The channel is closed when the UnsubFn is called, ctx is canceled or
+// the scope is closed.
+func SubscribeInterface[I any](ctx context.Context, scope *EventScope) (chan I, UnsubFn) {
+	iface := typeOf[I]()
+	if iface.Kind() != reflect.Interface {
+		panic("pubsub: SubscribeInterface with non-interface type " + iface.String())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch, unsub := subscribeAll(ctx, scope, WithOrdered())
+	out := make(chan I)
+	go func() {
+		defer close(out)
+		implements := make(map[reflect.Type]bool)
+		for msg := range ch {
+			t := reflect.TypeOf(msg.val)
+			if t == nil {
+				continue
+			}
+			ok, tracked := implements[t]
+			if !tracked {
+				ok = t.Implements(iface)
+				implements[t] = ok
+			}
+			if !ok {
+				continue
+			}
+			select {
+			case out <- msg.val.(I):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, func() {
+		cancel()
+		unsub()
+	}
+}
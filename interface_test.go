@@ -0,0 +1,43 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeInterface(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeInterface[fmt.Stringer](ctx, testScope)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, time.Second)
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, shutdownSignal{Reason: "bye"})
+	PublishToScope(ctx, testScope, 2*time.Second)
+
+	assert.Equal(t, "1s", (<-testingCh).String())
+	assert.Equal(t, "bye", (<-testingCh).String())
+	assert.Equal(t, "2s", (<-testingCh).String())
+}
+
+func TestSubscribeInterface_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeInterface[fmt.Stringer](ctx, testScope)
+	PublishToScope(ctx, testScope, time.Second)
+	unsub()
+
+	for range testingCh {
+	}
+}
+
+func TestSubscribeInterface_NotInterface(t *testing.T) {
+	assert.Panics(t, func() { SubscribeInterface[int](context.Background(), NewEventScope()) })
+}
@@ -0,0 +1,328 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Journal durably logs the messages published on an event scope, so that they can be replayed
+// after they have been delivered, including by a later process. Implementations must be safe for
+// concurrent use.
+type Journal interface {
+	// Append logs the serialized value published under typeName with sequence number seq.
+	// Appends are made in sequence order.
+	Append(seq int64, typeName string, data []byte) error
+
+	// Range calls fn for every logged message with a sequence number greater than or equal to
+	// from, in sequence order, until fn returns false.
+	Range(from int64, fn func(seq int64, typeName string, data []byte) bool) error
+}
+
+// ErrNoJournal is returned by SubscribeFromJournal if the scope does not have a journal.
+var ErrNoJournal = errors.New("pubsub: event scope has no journal")
+
+// journalHook holds the journal of a scope, so that it can be swapped atomically.
+type journalHook struct {
+	j Journal
+}
+
+// UseJournal logs every message published on the event scope from now on to j. Values are
+// serialized with the scope's Codec, compression and signing settings before they are appended,
+// and a publish whose value cannot be serialized or appended fails with that error without being
+// delivered.
+//
+// The messages already in j are scanned first, and the scope's numbering continues after the
+// last of them, so that a scope that resumes an existing journal does not reuse its sequence
+// numbers.
+func (e *EventScope) UseJournal(j Journal) error {
+	var last int64
+	err := j.Range(0, func(seq int64, _ string, _ []byte) bool {
+		last = seq
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	e.seqMu.Lock()
+	defer e.seqMu.Unlock()
+	if e.seq.Load() < last {
+		e.seq.Store(last)
+	}
+	e.journal.Store(&journalHook{j: j})
+	return nil
+}
+
+// journalOf returns the journal of the scope, or nil if it has none, and serializes the value of
+// msg if the scope has a journal or a message store.
+func (e *EventScope) journalOf(t eventType, msg message) (*journalHook, []byte, error) {
+	hook := e.journal.Load()
+	if hook == nil && e.store == nil {
+		return nil, nil, nil
+	}
+	data, err := e.marshal(t.name(), msg.val)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hook, data, nil
+}
+
+// SubscribeFromJournal subscribes to T on the event scope and first replays every value of type T
+// in the scope's journal with a sequence number greater than or equal to from, followed by live
+// values, without gaps or duplicates between the two. Values are delivered in publish order, as
+// with WithOrdered. ErrNoJournal is returned if UseJournal has not been called on the scope.
+func SubscribeFromJournal[T any](ctx context.Context, e *EventScope, from int64) (chan T, UnsubFn, error) {
+	if e == nil {
+		return nil, nil, ErrNilScope
+	}
+	hook := e.journal.Load()
+	if hook == nil {
+		return nil, nil, ErrNoJournal
+	}
+	t := eventTypeOf[T]()
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Everything numbered before the subscriber is registered has been appended to the journal,
+	// and everything numbered after it is delivered live.
+	var cutoff int64
+	snapshot := func(c *subscribeConfig) {
+		c.registered = func(*topic, *subscriber) {
+			cutoff = e.seq.Load()
+		}
+	}
+	live, unsub := subscribe(ctx, e, func(_ T, msg message) message { return msg }, WithOrdered(), snapshot)
+	stop := func() {
+		cancel()
+		unsub()
+	}
+
+	var replayed []message
+	name := t.name()
+	var decodeErr error
+	err := hook.j.Range(from, func(seq int64, typeName string, data []byte) bool {
+		if seq > cutoff {
+			return false
+		}
+		if typeName != name {
+			return true
+		}
+		val, err := e.unmarshal(name, data, t.decode)
+		if err != nil {
+			decodeErr = fmt.Errorf("pubsub: decoding journaled message %d: %w", seq, err)
+			return false
+		}
+		replayed = append(replayed, message{val: val, seq: seq})
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	sub := newSubscription[T](0, stop)
+	go sub.forward(ctx, replayed, live, cutoff)
+	return sub.C, sub.Unsubscribe, nil
+}
+
+// ErrCorruptJournal is returned by FileJournal when a record fails its checksum.
+var ErrCorruptJournal = errors.New("pubsub: corrupt journal")
+
+// SyncPolicy decides when a FileJournal flushes appended records to stable storage.
+type SyncPolicy struct {
+	every    int
+	interval time.Duration
+}
+
+var (
+	// SyncAlways flushes every record before Append returns.
+	SyncAlways = SyncPolicy{every: 1}
+
+	// SyncNever leaves flushing to the operating system. Records survive the process crashing
+	// but may be lost if the machine does.
+	SyncNever = SyncPolicy{}
+)
+
+// SyncEvery flushes after every n records. n must be positive.
+func SyncEvery(n int) SyncPolicy {
+	if n <= 0 {
+		panic("pubsub: sync count must be positive")
+	}
+	return SyncPolicy{every: n}
+}
+
+// SyncInterval flushes on the first Append at least d after the previous flush. Records appended
+// in the meantime are flushed by the next such Append or by Close.
+func SyncInterval(d time.Duration) SyncPolicy {
+	return SyncPolicy{interval: d}
+}
+
+// FileJournalOption configures a FileJournal at creation time.
+type FileJournalOption func(*FileJournal)
+
+// WithSyncPolicy sets when the journal flushes records to stable storage. The default is
+// SyncAlways.
+func WithSyncPolicy(p SyncPolicy) FileJournalOption {
+	return func(j *FileJournal) {
+		j.policy = p
+	}
+}
+
+// FileJournal is a Journal kept in an append-only file. Each record is written with a checksum,
+// and a record that was only partly written when the process stopped is discarded when the
+// journal is opened again.
+type FileJournal struct {
+	path   string
+	policy SyncPolicy
+
+	mu       sync.Mutex
+	f        *os.File
+	unsynced int
+	synced   time.Time
+}
+
+// recordHeaderSize is the size of the length and checksum that precede every record.
+const recordHeaderSize = 8
+
+// NewFileJournal opens the journal at path, creating the file if it does not exist.
+func NewFileJournal(path string, opts ...FileJournalOption) (*FileJournal, error) {
+	j := &FileJournal{path: path, policy: SyncAlways, synced: time.Now()}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	end, err := scanJournal(f, func(int64, string, []byte) bool { return true })
+	if err == nil {
+		err = f.Truncate(end)
+	}
+	if err == nil {
+		_, err = f.Seek(end, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	j.f = f
+	return j, nil
+}
+
+// Append writes a record to the end of the file and flushes it as the sync policy requires.
+func (j *FileJournal) Append(seq int64, typeName string, data []byte) error {
+	record := encodeRecord(seq, typeName, data)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.f.Write(record); err != nil {
+		return err
+	}
+	j.unsynced++
+	if (j.policy.every > 0 && j.unsynced >= j.policy.every) ||
+		(j.policy.interval > 0 && time.Since(j.synced) >= j.policy.interval) {
+		return j.sync()
+	}
+	return nil
+}
+
+func (j *FileJournal) sync() error {
+	if err := j.f.Sync(); err != nil {
+		return err
+	}
+	j.unsynced = 0
+	j.synced = time.Now()
+	return nil
+}
+
+// Range reads the file from the start and calls fn for the records numbered from on. Records
+// appended while Range is running may or may not be included.
+func (j *FileJournal) Range(from int64, fn func(seq int64, typeName string, data []byte) bool) error {
+	f, err := os.Open(j.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = scanJournal(f, func(seq int64, typeName string, data []byte) bool {
+		if seq < from {
+			return true
+		}
+		return fn(seq, typeName, data)
+	})
+	return err
+}
+
+// Close flushes the records that have not been flushed yet and closes the file.
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.sync()
+	if closeErr := j.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// encodeRecord returns the record of a message as it is written to a journal file.
+func encodeRecord(seq int64, typeName string, data []byte) []byte {
+	body := binary.BigEndian.AppendUint64(nil, uint64(seq))
+	body = binary.AppendUvarint(body, uint64(len(typeName)))
+	body = append(body, typeName...)
+	body = append(body, data...)
+
+	record := binary.BigEndian.AppendUint32(make([]byte, 0, recordHeaderSize+len(body)), uint32(len(body)))
+	record = binary.BigEndian.AppendUint32(record, crc32.ChecksumIEEE(body))
+	return append(record, body...)
+}
+
+// scanJournal calls fn for every complete record read from r until fn returns false, and returns
+// the offset just past the last complete record. A record cut short by the end of the input ends
+// the scan without an error.
+func scanJournal(r io.Reader, fn func(seq int64, typeName string, data []byte) bool) (int64, error) {
+	br := bufio.NewReader(r)
+	var offset int64
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return offset, nil
+			}
+			return offset, err
+		}
+		body := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(br, body); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return offset, nil
+			}
+			return offset, err
+		}
+		if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:]) || len(body) < 8 {
+			return offset, fmt.Errorf("%w: bad checksum at offset %d", ErrCorruptJournal, offset)
+		}
+
+		seq := int64(binary.BigEndian.Uint64(body))
+		n, size := binary.Uvarint(body[8:])
+		if size <= 0 || uint64(len(body)-8-size) < n {
+			return offset, fmt.Errorf("%w: bad record at offset %d", ErrCorruptJournal, offset)
+		}
+		name := string(body[8+size : 8+size+int(n)])
+		offset += int64(recordHeaderSize + len(body))
+		if !fn(seq, name, body[8+size+int(n):]) {
+			return offset, nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type journalEntry struct {
+	seq  int64
+	name string
+	data string
+}
+
+func readJournal(t *testing.T, j Journal, from int64) []journalEntry {
+	t.Helper()
+	var entries []journalEntry
+	require.NoError(t, j.Range(from, func(seq int64, name string, data []byte) bool {
+		entries = append(entries, journalEntry{seq: seq, name: name, data: string(data)})
+		return true
+	}))
+	return entries
+}
+
+func TestFileJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	j, err := NewFileJournal(path, WithSyncPolicy(SyncEvery(2)))
+	require.NoError(t, err)
+
+	require.NoError(t, j.Append(1, "a", []byte("one")))
+	require.NoError(t, j.Append(2, "b", []byte("two")))
+	require.NoError(t, j.Append(3, "a", nil))
+	assert.Equal(t, []journalEntry{{2, "b", "two"}, {3, "a", ""}}, readJournal(t, j, 2))
+	require.NoError(t, j.Close())
+
+	// A record cut short is discarded when the journal is reopened.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 9, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, err = NewFileJournal(path)
+	require.NoError(t, err)
+	defer j.Close()
+	require.NoError(t, j.Append(4, "c", []byte("four")))
+	assert.Equal(t, []journalEntry{{1, "a", "one"}, {2, "b", "two"}, {3, "a", ""}, {4, "c", "four"}}, readJournal(t, j, 0))
+}
+
+func TestFileJournal_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	j, err := NewFileJournal(path, WithSyncPolicy(SyncNever))
+	require.NoError(t, err)
+	require.NoError(t, j.Append(1, "a", []byte("one")))
+	require.NoError(t, j.Append(2, "a", []byte("two")))
+	require.NoError(t, j.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, err = NewFileJournal(path)
+	assert.ErrorIs(t, err, ErrCorruptJournal)
+}
+
+func TestUseJournal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "journal")
+
+	j, err := NewFileJournal(path)
+	require.NoError(t, err)
+	testScope := NewEventScope()
+	require.NoError(t, testScope.UseJournal(j))
+	require.NoError(t, PublishToScope(ctx, testScope, "one"))
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	require.NoError(t, PublishToScope(ctx, testScope, "three"))
+	require.NoError(t, j.Close())
+
+	// A new scope on the same journal replays what was published and continues the numbering.
+	j, err = NewFileJournal(path)
+	require.NoError(t, err)
+	defer j.Close()
+	testScope = NewEventScope()
+	require.NoError(t, testScope.UseJournal(j))
+
+	ch, unsub, err := SubscribeFromJournal[string](ctx, testScope, 0)
+	require.NoError(t, err)
+	defer unsub()
+	require.NoError(t, PublishToScope(ctx, testScope, "four"))
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case s := <-ch:
+			got = append(got, s)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for values")
+		}
+	}
+	assert.Equal(t, []string{"one", "three", "four"}, got)
+
+	entries := readJournal(t, j, 4)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(4), entries[0].seq)
+	assert.Equal(t, "string", entries[0].name)
+}
+
+type failingJournal struct{}
+
+var errJournalFull = errors.New("journal full")
+
+func (failingJournal) Append(int64, string, []byte) error { return errJournalFull }
+
+func (failingJournal) Range(int64, func(int64, string, []byte) bool) error { return nil }
+
+func TestUseJournal_AppendFails(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	require.NoError(t, testScope.UseJournal(failingJournal{}))
+
+	ch, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	assert.ErrorIs(t, PublishToScope(ctx, testScope, 1), errJournalFull)
+	select {
+	case <-ch:
+		t.Fatal("message that could not be journaled was delivered")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	_, _, err := SubscribeFromJournal[int](ctx, NewEventScope(), 0)
+	assert.ErrorIs(t, err, ErrNoJournal)
+}
@@ -0,0 +1,13 @@
+package pubsub
+
+// EnableLamportClock turns on Lamport timestamps for the event scope. Once enabled, every publish
+// increments the scope's clock and the new value is attached to the message. Subscribers can read
+// the timestamp through SubscribeEnvelopes to reconstruct the causal order of concurrent publishes.
+func (e *EventScope) EnableLamportClock() {
+	e.lamportEnabled.Store(true)
+}
+
+// LamportTime returns the current value of the scope's Lamport clock.
+func (e *EventScope) LamportTime() int64 {
+	return e.lamport.Load()
+}
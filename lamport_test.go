@@ -0,0 +1,52 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLamportClock(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.EnableLamportClock()
+
+	testingCh, unsub := SubscribeEnvelopes[int](ctx, testScope)
+	defer unsub()
+
+	const publishers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < publishers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			PublishToScope(ctx, testScope, i)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool)
+	for i := 0; i < publishers; i++ {
+		env := <-testingCh
+		assert.False(t, seen[env.Lamport], "duplicate lamport timestamp %d", env.Lamport)
+		assert.NotZero(t, env.Lamport)
+		seen[env.Lamport] = true
+	}
+	assert.Equal(t, int64(publishers), testScope.LamportTime())
+}
+
+func TestLamportClock_Disabled(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeEnvelopes[int](ctx, testScope)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 42)
+
+	env := <-testingCh
+	assert.Equal(t, 42, env.Value)
+	assert.Zero(t, env.Lamport)
+}
@@ -0,0 +1,36 @@
+package pubsub
+
+import "sync/atomic"
+
+// lastValue wraps the last value published of a type, which may be nil and so cannot be stored
+// in an atomic.Value directly.
+type lastValue struct {
+	val any
+}
+
+// recordLast caches the value of msg as the last one published under t.key. It is called with
+// seqMu held, so that the cache follows publish order.
+func (e *EventScope) recordLast(t eventType, msg message) {
+	v, ok := e.last.Load(t.key)
+	if !ok {
+		v, _ = e.last.LoadOrStore(t.key, new(atomic.Value))
+	}
+	v.(*atomic.Value).Store(lastValue{val: msg.val})
+}
+
+// LastPublished returns the value of type T most recently published on the event scope, for
+// callers that need the current state without subscribing. The boolean is false if no value of
+// T has been published yet, or if scope is nil.
+func LastPublished[T any](scope *EventScope) (T, bool) {
+	var zero T
+	if scope == nil {
+		return zero, false
+	}
+	v, ok := scope.last.Load(eventTypeOf[T]().key)
+	if !ok {
+		return zero, false
+	}
+	// The value is nil for the zero value of an interface type.
+	val, _ := v.(*atomic.Value).Load().(lastValue).val.(T)
+	return val, true
+}
@@ -0,0 +1,33 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastPublished(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	_, ok := LastPublished[int](testScope)
+	assert.False(t, ok)
+
+	// No subscriber is needed for the value to be cached.
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	require.NoError(t, PublishToScope(ctx, testScope, "other"))
+	val, ok := LastPublished[int](testScope)
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+
+	require.NoError(t, PublishToScope[error](ctx, testScope, nil))
+	errVal, ok := LastPublished[error](testScope)
+	assert.True(t, ok)
+	assert.Nil(t, errVal)
+
+	_, ok = LastPublished[int](nil)
+	assert.False(t, ok)
+}
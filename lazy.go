@@ -0,0 +1,40 @@
+package pubsub
+
+import "sync"
+
+// NewLazyEventScope creates an event scope configured by the provided options, like
+// NewEventScope, that defers allocating the map of its topics until it is first published or
+// subscribed to. It saves memory for programs that declare many scopes at startup and only use
+// some of them. Until then, the scope reports having no types and no subscribers.
+func NewLazyEventScope(opts ...EventScopeOption) *EventScope {
+	return newEventScope(opts...)
+}
+
+// IsInitialized reports whether the topics of the scope have been initialized, which a scope
+// created with NewLazyEventScope does on the first publish or subscribe, and any other scope when
+// it is created.
+func (e *EventScope) IsInitialized() bool {
+	return e.subscribers.Load() != nil
+}
+
+// topics returns the topics of the scope by key, initializing them if need be. It is used by the
+// paths that publish and subscribe.
+func (e *EventScope) topics() *sync.Map {
+	e.initOnce.Do(func() {
+		e.subscribers.Store(&sync.Map{})
+	})
+	return e.subscribers.Load()
+}
+
+// noTopics is the empty map the topics of a scope that has not been initialized are read from.
+// It is never written to.
+var noTopics sync.Map
+
+// knownTopics returns the topics of the scope by key, for the paths that only read them, without
+// initializing them.
+func (e *EventScope) knownTopics() *sync.Map {
+	if topics := e.subscribers.Load(); topics != nil {
+		return topics
+	}
+	return &noTopics
+}
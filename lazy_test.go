@@ -0,0 +1,40 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyEventScope(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewLazyEventScope()
+
+	// Reading the scope does not initialize it.
+	assert.Zero(t, testScope.Stats().Subscribers)
+	assert.NotEmpty(t, testScope.String())
+	assert.False(t, testScope.IsInitialized())
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	assert.True(t, testScope.IsInitialized())
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.Equal(t, 1, <-testingCh)
+}
+
+func TestLazyEventScope_Publish(t *testing.T) {
+	testScope := NewLazyEventScope()
+	require.NoError(t, PublishToScope(context.Background(), testScope, 1))
+	assert.True(t, testScope.IsInitialized())
+
+	assert.True(t, NewEventScope().IsInitialized())
+}
+
+func TestLazyEventScope_Close(t *testing.T) {
+	testScope := NewLazyEventScope()
+	require.NoError(t, testScope.Close())
+	assert.False(t, testScope.IsInitialized())
+}
@@ -0,0 +1,141 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// PublishFn passes a published value on to the next publish middleware, or to the scope once
+// every middleware has handled it.
+type PublishFn func(ctx context.Context, typeName string, val any)
+
+// ReceiveFn passes a value on its way to a subscriber on to the next receive middleware, or to
+// the subscriber once every middleware has handled it.
+type ReceiveFn func(ctx context.Context, typeName string, val any)
+
+// Middleware intercepts the values published on an event scope and the values delivered to its
+// subscribers, see EventScope.Use. Unlike a function, a Middleware can keep state across both,
+// such as a circuit breaker per type.
+//
+// HandlePublish is called with every value published on the scope by an identity that may
+// publish it, along with the name of its type, and HandleReceive with every value about to be
+// sent to a subscriber, with the subscriber's context, into which the scope's trace injector has
+// restored the trace of the publish, see WithTraceInjector. Both pass the value on by calling
+// next, before they return, possibly with another context or value. The value must keep its type. A
+// value a middleware does not pass on is dropped: a publish still returns nil, and a subscriber
+// goes on with the next value.
+type Middleware interface {
+	HandlePublish(ctx context.Context, typeName string, val any, next PublishFn)
+	HandleReceive(ctx context.Context, typeName string, val any, next ReceiveFn)
+}
+
+// PublishMiddlewareFunc is a Middleware that handles published values with the function, and
+// passes every received value on unchanged.
+type PublishMiddlewareFunc func(ctx context.Context, typeName string, val any, next PublishFn)
+
+func (f PublishMiddlewareFunc) HandlePublish(ctx context.Context, typeName string, val any, next PublishFn) {
+	f(ctx, typeName, val, next)
+}
+
+func (f PublishMiddlewareFunc) HandleReceive(ctx context.Context, typeName string, val any, next ReceiveFn) {
+	next(ctx, typeName, val)
+}
+
+// ReceiveMiddlewareFunc is a Middleware that handles received values with the function, and
+// passes every published value on unchanged.
+type ReceiveMiddlewareFunc func(ctx context.Context, typeName string, val any, next ReceiveFn)
+
+func (f ReceiveMiddlewareFunc) HandlePublish(ctx context.Context, typeName string, val any, next PublishFn) {
+	next(ctx, typeName, val)
+}
+
+func (f ReceiveMiddlewareFunc) HandleReceive(ctx context.Context, typeName string, val any, next ReceiveFn) {
+	f(ctx, typeName, val, next)
+}
+
+// middlewares holds the middleware of a scope. list is replaced, never modified, under mu.
+type middlewares struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]Middleware]
+}
+
+// Use adds mw to the middleware of the event scope. It handles the values published and received
+// after Use returns. Middleware is applied in the order it was added, the first added seeing
+// every value first.
+func (e *EventScope) Use(mw Middleware) {
+	e.middleware.mu.Lock()
+	defer e.middleware.mu.Unlock()
+
+	var list []Middleware
+	if old := e.middleware.list.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, mw)
+	e.middleware.list.Store(&list)
+}
+
+// interceptPublish passes a value of type t published with ctx through the scope's middleware.
+// It returns the context and value passed on by the last middleware, and false if the value was
+// dropped.
+func (e *EventScope) interceptPublish(ctx context.Context, t eventType, val any) (context.Context, any, bool, error) {
+	ctx, val, ok := e.intercept(ctx, t.name(), val, func(mw Middleware, ctx context.Context, typeName string, val any, next func(context.Context, string, any)) {
+		mw.HandlePublish(ctx, typeName, val, next)
+	})
+	if ok && !holds(t, val) {
+		return ctx, nil, false, fmt.Errorf("pubsub: middleware replaced a published %s with a %T", t.name(), val)
+	}
+	return ctx, val, ok, nil
+}
+
+// interceptReceive passes the value of msg through the scope's middleware on its way to sub,
+// with the delivery context ctx. It returns the message with the value passed on by the last
+// middleware, and false if the value was dropped.
+func (e *EventScope) interceptReceive(ctx context.Context, sub *subscriber, msg message) (message, bool) {
+	typeName := msg.typeName
+	if !sub.all {
+		typeName = sub.t.name()
+	}
+	_, val, ok := e.intercept(ctx, typeName, msg.val, func(mw Middleware, ctx context.Context, typeName string, val any, next func(context.Context, string, any)) {
+		mw.HandleReceive(ctx, typeName, val, next)
+	})
+	if ok && !sub.all && !holds(sub.t, val) {
+		e.reportError(fmt.Errorf("pubsub: middleware replaced a received %s with a %T", typeName, val))
+		return msg, false
+	}
+	msg.val = val
+	return msg, ok
+}
+
+// intercept calls handle for every middleware of the scope in turn, each passing the value on to
+// the next.
+func (e *EventScope) intercept(ctx context.Context, typeName string, val any, handle func(mw Middleware, ctx context.Context, typeName string, val any, next func(context.Context, string, any))) (context.Context, any, bool) {
+	list := e.middleware.list.Load()
+	if list == nil {
+		return ctx, val, true
+	}
+
+	var passed bool
+	next := func(nextCtx context.Context, _ string, nextVal any) {
+		ctx, val, passed = nextCtx, nextVal, true
+	}
+	for i := len(*list) - 1; i >= 0; i-- {
+		mw, inner := (*list)[i], next
+		next = func(ctx context.Context, typeName string, val any) {
+			handle(mw, ctx, typeName, val, inner)
+		}
+	}
+	next(ctx, typeName, val)
+	return ctx, val, passed
+}
+
+// holds reports whether val can be delivered as a value of type t. val is nil for the zero value
+// of an interface type.
+func holds(t eventType, val any) bool {
+	if val == nil {
+		return t.typ.Kind() == reflect.Interface
+	}
+	return reflect.TypeOf(val).AssignableTo(t.typ)
+}
@@ -0,0 +1,143 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMiddleware counts the values it sees per type name, and drops published values of
+// types that are in blocked.
+type countingMiddleware struct {
+	blocked   map[string]bool
+	published map[string]int
+	received  map[string]int
+}
+
+func (m *countingMiddleware) HandlePublish(ctx context.Context, typeName string, val any, next PublishFn) {
+	m.published[typeName]++
+	if !m.blocked[typeName] {
+		next(ctx, typeName, val)
+	}
+}
+
+func (m *countingMiddleware) HandleReceive(ctx context.Context, typeName string, val any, next ReceiveFn) {
+	m.received[typeName]++
+	next(ctx, typeName, val)
+}
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	mw := &countingMiddleware{
+		blocked:   map[string]bool{"string": true},
+		published: make(map[string]int),
+		received:  make(map[string]int),
+	}
+	testScope.Use(mw)
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+	strs, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	defer unsubStrs()
+
+	assert.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.Equal(t, 1, <-ints)
+	assert.NoError(t, PublishToScope(ctx, testScope, "dropped"))
+	assert.Equal(t, int64(1), testScope.Stats().Published)
+
+	assert.Equal(t, map[string]int{"int": 1, "string": 1}, mw.published)
+	assert.Equal(t, map[string]int{"int": 1}, mw.received)
+	assert.Empty(t, strs)
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.Use(PublishMiddlewareFunc(func(ctx context.Context, typeName string, val any, next PublishFn) {
+		next(ctx, typeName, val.(int)*10)
+	}))
+	testScope.Use(PublishMiddlewareFunc(func(ctx context.Context, typeName string, val any, next PublishFn) {
+		next(ctx, typeName, val.(int)+1)
+	}))
+	testScope.Use(ReceiveMiddlewareFunc(func(ctx context.Context, typeName string, val any, next ReceiveFn) {
+		assert.Equal(t, "int", typeName)
+		if val.(int) > 100 {
+			return
+		}
+		next(ctx, typeName, -val.(int))
+	}))
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsub()
+	all, unsubAll := subscribeAll(ctx, testScope)
+	defer unsubAll()
+
+	PublishToScope(ctx, testScope, 20)
+	PublishToScope(ctx, testScope, 2)
+	// 20 is published as 201, which the receive middleware drops, and 2 as 21.
+	assert.Equal(t, -21, <-ints)
+	assert.Equal(t, -21, (<-all).val)
+}
+
+func TestMiddleware_TypeChange(t *testing.T) {
+	ctx := context.Background()
+	errs := make(chan error, 1)
+	testScope := NewEventScope(WithErrorHandler(func(err error) { errs <- err }))
+	testScope.Use(PublishMiddlewareFunc(func(ctx context.Context, typeName string, val any, next PublishFn) {
+		if val == 1 {
+			val = "one"
+		}
+		next(ctx, typeName, val)
+	}))
+	testScope.Use(ReceiveMiddlewareFunc(func(ctx context.Context, typeName string, val any, next ReceiveFn) {
+		if val == 2 {
+			val = "two"
+		}
+		next(ctx, typeName, val)
+	}))
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsub()
+	assert.Error(t, PublishToScope(ctx, testScope, 1))
+
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	assert.Error(t, <-errs)
+	require.NoError(t, PublishToScope(ctx, testScope, 3))
+	assert.Equal(t, 3, <-ints)
+}
+
+func TestMiddleware_Nil(t *testing.T) {
+	ctx := context.Background()
+	errs := make(chan error, 1)
+	testScope := NewEventScope(WithErrorHandler(func(err error) { errs <- err }))
+	testScope.Use(PublishMiddlewareFunc(func(ctx context.Context, typeName string, val any, next PublishFn) {
+		if val == 1 || val == errNotFound {
+			val = nil
+		}
+		next(ctx, typeName, val)
+	}))
+	testScope.Use(ReceiveMiddlewareFunc(func(ctx context.Context, typeName string, val any, next ReceiveFn) {
+		if val == 2 {
+			val = nil
+		}
+		next(ctx, typeName, val)
+	}))
+
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsubInts()
+	assert.Error(t, PublishToScope(ctx, testScope, 1))
+
+	require.NoError(t, PublishToScope(ctx, testScope, 2))
+	assert.Error(t, <-errs)
+	require.NoError(t, PublishToScope(ctx, testScope, 3))
+	assert.Equal(t, 3, <-ints)
+
+	// nil is the zero value of an interface type.
+	received, unsubErrors := SubscribeToScope[error](ctx, testScope)
+	defer unsubErrors()
+	require.NoError(t, PublishToScope[error](ctx, testScope, errNotFound))
+	assert.Nil(t, <-received)
+}
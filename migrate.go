@@ -0,0 +1,68 @@
+package pubsub
+
+import "errors"
+
+// ErrWrongScope is returned by MigrateSubscription when the subscription is not on the scope it
+// is moved from, because it was made on another scope, already moved away or has ended.
+var ErrWrongScope = errors.New("pubsub: subscription is not on the event scope")
+
+// MigrateSubscription moves a Subscription made with NewSubscription or
+// NewSubscriptionFromWatermark from one event scope to another without closing C. Its subscriber
+// is registered on to with the same ID before it is removed from from, so it is on exactly one
+// of the two when MigrateSubscription returns, and values published on to from then on are
+// received on C as they would be on from. The watermark restarts at to's sequence number at the
+// time of the move, since the two scopes number their messages independently.
+//
+// Values published on from while the subscription is being moved may be lost: those that had
+// not been received on C yet when the subscriber was removed are dropped, as if it had
+// unsubscribed. Values published on to before the move are not replayed.
+//
+// If the subscription cannot be registered on to, it is left on from and the error is returned:
+// ErrScopeClosed if to has been closed, ErrUnauthorized if the identity attached to the
+// subscription's context may not subscribe to T on to, or the context's error if the subscription
+// has been unsubscribed. ErrNilScope is returned if either scope is nil, and ErrWrongScope if the
+// subscription is not on from.
+func MigrateSubscription[T any](sub *Subscription[T], from, to *EventScope) error {
+	if from == nil || to == nil {
+		return ErrNilScope
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.scope != from {
+		return ErrWrongScope
+	}
+	if from == to {
+		return nil
+	}
+
+	var registered bool
+	var watermark int64
+	snapshot := func(c *subscribeConfig) {
+		c.registered = func(*topic, *subscriber) {
+			registered = true
+			watermark = to.seq.Load()
+		}
+	}
+	live, detach := subscribe(sub.ctx, to, func(_ T, msg message) message { return msg }, WithOrdered(), withSubscriberID(sub.id), snapshot)
+	if !registered {
+		return subscriptionErr(sub.ctx, to)
+	}
+
+	// Only MigrateSubscription hands feeds to forward, under sub.mu, so replacing one forward has
+	// not switched to yet leaves room for the new one. The subscriber of the replaced feed is the
+	// current one, which is detached below.
+	select {
+	case <-sub.feeds:
+	default:
+	}
+	sub.feeds <- feed{live: live, watermark: watermark}
+
+	old := sub.detach
+	sub.scope, sub.detach = to, detach
+	untrackSubscription(from, sub.id)
+	trackSubscription(to, sub)
+	old()
+	return nil
+}
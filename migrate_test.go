@@ -0,0 +1,85 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateSubscription(t *testing.T) {
+	ctx := context.Background()
+	from := NewEventScope()
+	to := NewEventScope()
+
+	sub := NewSubscription[int](ctx, from)
+	defer sub.Unsubscribe()
+
+	PublishToScope(ctx, from, 1)
+	assert.Equal(t, 1, <-sub.C)
+
+	PublishToScope(ctx, to, 2)
+	require.NoError(t, MigrateSubscription(sub, from, to))
+	assert.Zero(t, from.Stats().Subscribers)
+	assert.Equal(t, 1, to.Stats().Subscribers)
+
+	// Published after the move, so only the value on to is received.
+	PublishToScope(ctx, from, 3)
+	PublishToScope(ctx, to, 4)
+	assert.Equal(t, 4, <-sub.C)
+	assert.Equal(t, int64(2), sub.Watermark())
+
+	assert.ErrorIs(t, MigrateSubscription(sub, from, to), ErrWrongScope)
+}
+
+func TestMigrateSubscription_SameID(t *testing.T) {
+	ctx := context.Background()
+	from := NewEventScope()
+	to := NewEventScope()
+	observer := to.Observe()
+	defer observer.Stop()
+
+	sub := NewSubscription[int](ctx, from)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, MigrateSubscription(sub, from, to))
+	added := <-observer.Added
+	assert.Equal(t, sub.ID(), added.ID)
+
+	state, err := to.SerializeSubscriberState()
+	require.NoError(t, err)
+	assert.Contains(t, string(state), sub.ID().String())
+}
+
+func TestMigrateSubscription_Errors(t *testing.T) {
+	ctx := context.Background()
+	from := NewEventScope()
+	closed := NewEventScope()
+	require.NoError(t, closed.Close())
+
+	sub := NewSubscription[int](ctx, from)
+	defer sub.Unsubscribe()
+
+	assert.ErrorIs(t, MigrateSubscription(sub, nil, from), ErrNilScope)
+	assert.ErrorIs(t, MigrateSubscription(sub, closed, from), ErrWrongScope)
+	assert.ErrorIs(t, MigrateSubscription(sub, from, closed), ErrScopeClosed)
+
+	// The subscription stays on from.
+	PublishToScope(ctx, from, 1)
+	assert.Equal(t, 1, <-sub.C)
+}
+
+func TestMigrateSubscription_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	from := NewEventScope()
+	to := NewEventScope()
+
+	sub := NewSubscription[int](ctx, from)
+	require.NoError(t, MigrateSubscription(sub, from, to))
+	sub.Unsubscribe()
+
+	for range sub.C {
+	}
+	assert.Zero(t, to.Stats().Subscribers)
+}
@@ -0,0 +1,28 @@
+package pubsub
+
+import "context"
+
+// SubscribeNotify returns a channel that is signaled whenever a value of the type named typeName
+// is published on the event scope, for subscribers that only need to be woken up, not to receive
+// the value. Type names are formatted like reflect.Type.String, and a typeName of "*" matches
+// every type. The channel holds at most one pending signal: publishes made before the previous
+// signal was received are coalesced into it. As with subscriptions to every type, only the types
+// the identity attached to ctx may subscribe to signal the channel. The channel is closed when
+// the UnsubFn is called, ctx is canceled or the scope is closed.
+func SubscribeNotify(ctx context.Context, scope *EventScope, typeName string) (<-chan struct{}, UnsubFn) {
+	ch, unsub := subscribeAll(ctx, scope)
+	notify := make(chan struct{}, 1)
+	go func() {
+		defer close(notify)
+		for msg := range ch {
+			if typeName != "*" && msg.typeName != typeName {
+				continue
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return notify, unsub
+}
@@ -0,0 +1,44 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeNotify(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	ints, unsubInts := SubscribeNotify(ctx, testScope, "int")
+	defer unsubInts()
+	all, unsubAll := SubscribeNotify(ctx, testScope, "*")
+
+	PublishToScope(ctx, testScope, "ignored")
+	<-all
+	select {
+	case <-ints:
+		t.Fatal("signaled for another type")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Rapid publishes are coalesced into a single signal.
+	for i := 0; i < 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	assert.Eventually(t, func() bool { return testScope.Len() == 0 }, time.Second, time.Millisecond)
+	// Give the last value received the time to signal.
+	time.Sleep(10 * time.Millisecond)
+	<-ints
+	select {
+	case <-ints:
+		t.Fatal("signaled more than once")
+	default:
+	}
+
+	unsubAll()
+	<-all
+	_, ok := <-all
+	assert.False(t, ok)
+}
@@ -0,0 +1,44 @@
+package pubsub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WithNUMALocalDelivery makes the goroutine that forwards the subscription's values run on the
+// CPUs of the NUMA node the subscribing goroutine is running on when it subscribes, so that a
+// CPU intensive subscriber receiving on that node reads its values from local memory. The
+// goroutine is locked to an OS thread whose CPU affinity is restricted to the node, and the thread
+// is discarded when the subscription ends. On systems with a single NUMA node, or other than
+// Linux, the option has no effect.
+func WithNUMALocalDelivery() SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.numaLocal = true
+	}
+}
+
+// parseCPUList parses a list of CPUs in the format of the Linux sysfs, such as "0-3,8,10-11".
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	for _, r := range strings.Split(strings.TrimSpace(list), ",") {
+		if r == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(r, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("pubsub: malformed CPU list %q", list)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				return nil, fmt.Errorf("pubsub: malformed CPU list %q", list)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package pubsub
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const nodeDir = "/sys/devices/system/node"
+
+// numaPinner returns a function that pins the goroutine calling it to the CPUs of the NUMA node
+// the calling goroutine is running on now, or nil if the system has a single node or its nodes
+// cannot be read.
+func numaPinner() func() {
+	online, err := os.ReadFile(nodeDir + "/online")
+	if err != nil {
+		return nil
+	}
+	if nodes, err := parseCPUList(string(online)); err != nil || len(nodes) < 2 {
+		return nil
+	}
+
+	var cpu, node uint32
+	if _, _, errno := unix.RawSyscall(unix.SYS_GETCPU, uintptr(unsafe.Pointer(&cpu)), uintptr(unsafe.Pointer(&node)), 0); errno != 0 {
+		return nil
+	}
+	list, err := os.ReadFile(fmt.Sprintf("%s/node%d/cpulist", nodeDir, node))
+	if err != nil {
+		return nil
+	}
+	cpus, err := parseCPUList(string(list))
+	if err != nil || len(cpus) == 0 {
+		return nil
+	}
+
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	return func() {
+		// The thread is never unlocked, so the runtime discards it with its affinity once the
+		// goroutine exits instead of running other goroutines on it.
+		runtime.LockOSThread()
+		unix.SchedSetaffinity(0, &set)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package pubsub
+
+// numaPinner returns nil, NUMA local delivery is only supported on Linux.
+func numaPinner() func() {
+	return nil
+}
@@ -0,0 +1,41 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3,8,10-11\n")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 8, 10, 11}, cpus)
+
+	cpus, err = parseCPUList("")
+	require.NoError(t, err)
+	assert.Empty(t, cpus)
+
+	for _, list := range []string{"a", "3-1", "1-b"} {
+		_, err := parseCPUList(list)
+		assert.Error(t, err, list)
+	}
+}
+
+func TestNUMALocalDelivery(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	// Whether the system has several NUMA nodes or not, values are delivered as usual.
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithNUMALocalDelivery(), WithOrdered())
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, i, <-testingCh)
+	}
+	unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+}
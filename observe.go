@@ -0,0 +1,196 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriberAdded is observed when a subscriber is registered on the scope.
+type SubscriberAdded struct {
+	ID uuid.UUID
+
+	// TypeName is the name of the type subscribed to, or "*" for subscribers to every type.
+	TypeName string
+}
+
+// SubscriberRemoved is observed when a subscriber leaves the scope, because it unsubscribed, its
+// context was canceled or the scope was closed.
+type SubscriberRemoved struct {
+	ID       uuid.UUID
+	TypeName string
+}
+
+// MessagePublished is observed for every value published on the scope.
+type MessagePublished struct {
+	TypeName string
+	Seq      int64
+	Time     time.Time
+}
+
+// MessageDropped is observed for every message that never reached a subscriber that was still
+// receiving, as counted in ScopeStats.Dropped.
+type MessageDropped struct {
+	TypeName string
+	Seq      int64
+
+	// SubscriberID is the subscriber the message was on its way to, or the zero UUID if it was
+	// dropped before being handed to its subscribers, such as a spilled message that could not
+	// be read back.
+	SubscriberID uuid.UUID
+}
+
+// observerBuffer is the number of events of each kind an Observer buffers.
+const observerBuffer = 128
+
+// Observer receives the lifecycle events of an event scope, see EventScope.Observe. Each kind of
+// event is sent on a channel of its own, buffered so that observing never slows the scope down:
+// events that do not fit in the buffer are left out and counted by Missed. Every channel that
+// is not of interest can be left alone.
+type Observer struct {
+	Added     <-chan SubscriberAdded
+	Removed   <-chan SubscriberRemoved
+	Published <-chan MessagePublished
+	Dropped   <-chan MessageDropped
+
+	scope     *EventScope
+	added     chan SubscriberAdded
+	removed   chan SubscriberRemoved
+	published chan MessagePublished
+	dropped   chan MessageDropped
+	missed    atomic.Int64
+
+	// mu is held for reading while events are sent, so that the channels can be closed once
+	// stopped is set under it.
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// observers holds the observers of a scope. list is replaced, never modified, under mu.
+type observers struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]*Observer]
+}
+
+// Observe returns an Observer that receives the lifecycle events of the scope from now on:
+// subscribers being added and removed, and messages being published and dropped. It is the
+// introspection layer monitoring tools and dashboards build on. Stop detaches it.
+func (e *EventScope) Observe() *Observer {
+	o := &Observer{
+		scope:     e,
+		added:     make(chan SubscriberAdded, observerBuffer),
+		removed:   make(chan SubscriberRemoved, observerBuffer),
+		published: make(chan MessagePublished, observerBuffer),
+		dropped:   make(chan MessageDropped, observerBuffer),
+	}
+	o.Added, o.Removed, o.Published, o.Dropped = o.added, o.removed, o.published, o.dropped
+
+	e.observers.mu.Lock()
+	defer e.observers.mu.Unlock()
+	var list []*Observer
+	if old := e.observers.list.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, o)
+	e.observers.list.Store(&list)
+	return o
+}
+
+// Stop detaches the observer from the scope and closes its channels once the events already
+// buffered have been received. Calling Stop more than once has no effect.
+func (o *Observer) Stop() {
+	e := o.scope
+	e.observers.mu.Lock()
+	if old := e.observers.list.Load(); old != nil {
+		list := make([]*Observer, 0, len(*old))
+		for _, other := range *old {
+			if other != o {
+				list = append(list, other)
+			}
+		}
+		e.observers.list.Store(&list)
+	}
+	e.observers.mu.Unlock()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.stopped {
+		return
+	}
+	o.stopped = true
+	close(o.added)
+	close(o.removed)
+	close(o.published)
+	close(o.dropped)
+}
+
+// Missed returns the number of events left out because their channel's buffer was full.
+func (o *Observer) Missed() int64 {
+	return o.missed.Load()
+}
+
+// observe sends an event to every observer of the scope with send, which reports whether the
+// event fit in the observer's buffer.
+func (e *EventScope) observe(send func(o *Observer) bool) {
+	list := e.observers.list.Load()
+	if list == nil {
+		return
+	}
+	for _, o := range *list {
+		o.mu.RLock()
+		if !o.stopped && !send(o) {
+			o.missed.Add(1)
+		}
+		o.mu.RUnlock()
+	}
+}
+
+// trySend sends val on ch unless its buffer is full, and reports whether it did.
+func trySend[T any](ch chan T, val T) bool {
+	select {
+	case ch <- val:
+		return true
+	default:
+		return false
+	}
+}
+
+// observeAdded, observeRemoved, observePublished and observeDropped send the events of each kind
+// to the scope's observers.
+
+func (e *EventScope) observeAdded(t eventType, id uuid.UUID) {
+	e.observe(func(o *Observer) bool {
+		return trySend(o.added, SubscriberAdded{ID: id, TypeName: topicName(t)})
+	})
+}
+
+func (e *EventScope) observeRemoved(t eventType, id uuid.UUID) {
+	e.observe(func(o *Observer) bool {
+		return trySend(o.removed, SubscriberRemoved{ID: id, TypeName: topicName(t)})
+	})
+}
+
+func (e *EventScope) observePublished(t eventType, msg message) {
+	e.observe(func(o *Observer) bool {
+		return trySend(o.published, MessagePublished{TypeName: t.name(), Seq: msg.seq, Time: e.clock.Now()})
+	})
+}
+
+// drop counts a message of the named type that never reached its subscriber, if any.
+func (e *EventScope) drop(typeName string, msg message, subscriberID uuid.UUID) {
+	e.dropped.Add(1)
+	e.observe(func(o *Observer) bool {
+		return trySend(o.dropped, MessageDropped{TypeName: typeName, Seq: msg.seq, SubscriberID: subscriberID})
+	})
+}
+
+// topicName returns the name of the type of a topic, or wildcardName for the topic of the
+// subscribers to every type.
+func topicName(t eventType) string {
+	if t.typ == nil {
+		return wildcardName
+	}
+	return t.name()
+}
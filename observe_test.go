@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserve(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	o := testScope.Observe()
+	defer o.Stop()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	added := <-o.Added
+	assert.Equal(t, "int", added.TypeName)
+
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	published := <-o.Published
+	assert.Equal(t, "int", published.TypeName)
+	assert.Equal(t, int64(1), published.Seq)
+	assert.False(t, published.Time.IsZero())
+
+	// The subscriber does not receive, so a publish whose context is canceled is dropped once
+	// the first value is waiting to be received. Values taken before that are held back until
+	// the first one has been received.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	var dropped MessageDropped
+	assert.Eventually(t, func() bool {
+		PublishToScope(canceled, testScope, 2)
+		select {
+		case dropped = <-o.Dropped:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, MessageDropped{TypeName: "int", Seq: dropped.Seq, SubscriberID: added.ID}, dropped)
+	assert.Equal(t, 1, <-testingCh)
+
+	unsub()
+	assert.Equal(t, SubscriberRemoved{ID: added.ID, TypeName: "int"}, <-o.Removed)
+
+	_, unsubAll := subscribeAll(ctx, testScope)
+	assert.Equal(t, wildcardName, (<-o.Added).TypeName)
+	unsubAll()
+}
+
+func TestObserve_Stop(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	o := testScope.Observe()
+	kept := testScope.Observe()
+	defer kept.Stop()
+
+	// Events that do not fit in the buffer are missed rather than waited for.
+	for i := 0; i < observerBuffer+10; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	assert.Equal(t, int64(10), o.Missed())
+	assert.Len(t, o.Published, observerBuffer)
+
+	o.Stop()
+	o.Stop()
+	received := 0
+	for range o.Published {
+		received++
+	}
+	assert.Equal(t, observerBuffer, received)
+	_, ok := <-o.Added
+	assert.False(t, ok)
+
+	// The other observers are still attached.
+	_, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	assert.Equal(t, "int", (<-kept.Added).TypeName)
+}
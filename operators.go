@@ -0,0 +1,213 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// demand runs start when the topic it watches gains its first subscriber and stop when the topic
+// loses its last one, so that derived scopes only do work while someone is listening.
+type demand struct {
+	mu      sync.Mutex
+	running bool
+	start   func()
+	stop    func()
+}
+
+// sync starts or stops the demand to match whether the topic has subscribers. The state is read
+// with the demand's lock held, so concurrent calls settle on the topic's latest state.
+func (d *demand) sync(t *topic) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	active := false
+	t.subs.Range(func(_, _ any) bool {
+		active = true
+		return false
+	})
+	switch {
+	case active && !d.running:
+		d.start()
+	case !active && d.running:
+		d.stop()
+	default:
+		return
+	}
+	d.running = active
+}
+
+// syncDemand notifies the topic's demands of a change to its subscribers. It must not be called
+// with seqMu held, since starting a demand subscribes to another scope, which may be the same one.
+func (t *topic) syncDemand() {
+	t.mu.RLock()
+	demands := t.demands
+	t.mu.RUnlock()
+
+	for _, d := range demands {
+		d.sync(t)
+	}
+}
+
+// onDemand runs start whenever T gains its first subscriber on the event scope, and stop
+// whenever it loses its last one. start is called right away if T already has subscribers.
+func onDemand[T any](e *EventScope, start, stop func()) {
+	t := eventTypeOf[T]()
+	d := &demand{start: start, stop: stop}
+
+	// The topic is looked up under seqMu, so that it cannot be deleted before the demand keeps
+	// it alive.
+	e.seqMu.Lock()
+	v, _ := e.topics().LoadOrStore(t.key, &topic{e: e, t: t})
+	top := v.(*topic)
+	top.mu.Lock()
+	top.demands = append(top.demands, d)
+	top.mu.Unlock()
+	e.seqMu.Unlock()
+	d.sync(top)
+}
+
+// MapScope publishes fn(val) on dst for every value of type In published on src. The mapped
+// values are published in the order the values were published on src. The subscription to src
+// only exists while Out has subscribers on dst: it is created when the first subscriber of Out
+// subscribes, and released when the last one unsubscribes.
+func MapScope[In, Out any](src *EventScope, fn func(In) Out, dst *EventScope) {
+	var unsub UnsubFn
+	onDemand[Out](dst, func() {
+		unsub = SubscribeCallback(context.Background(), src, func(val In) {
+			PublishToScope(context.Background(), dst, fn(val))
+		}, WithOrdered())
+	}, func() {
+		unsub()
+	})
+}
+
+// FilterScope returns a new event scope that carries the values of type T published on src for
+// which pred returns true, in the order they were published. Like MapScope, the returned scope
+// only subscribes to src while T has subscribers on it. Derived scopes can be chained with
+// further calls to FilterScope and MapScope to build pipelines.
+func FilterScope[T any](src *EventScope, pred func(T) bool) *EventScope {
+	dst := NewEventScope()
+	var unsub UnsubFn
+	onDemand[T](dst, func() {
+		unsub = SubscribeCallback(context.Background(), src, func(val T) {
+			if pred(val) {
+				PublishToScope(context.Background(), dst, val)
+			}
+		}, WithOrdered())
+	}, func() {
+		unsub()
+	})
+	return dst
+}
+
+// FlatMapScope returns a new event scope that carries the values of type Out published on the
+// scopes returned by fn. fn is called for every value of type In published on src, and the
+// returned scope is subscribed to until it is closed, so that everything it publishes after fn
+// returns is forwarded. fn may return nil to skip a value. Like MapScope, the returned scope only
+// subscribes to src and the inner scopes while Out has subscribers on it.
+func FlatMapScope[In, Out any](src *EventScope, fn func(In) *EventScope) *EventScope {
+	dst := NewEventScope()
+
+	var mu sync.Mutex
+	var unsubOuter UnsubFn
+	inner := make(map[*UnsubFn]struct{})
+
+	onDemand[Out](dst, func() {
+		unsubOuter = SubscribeCallback(context.Background(), src, func(val In) {
+			scope := fn(val)
+			if scope == nil {
+				return
+			}
+
+			ch, unsub := SubscribeToScope[Out](context.Background(), scope, WithOrdered())
+			mu.Lock()
+			inner[&unsub] = struct{}{}
+			mu.Unlock()
+
+			go func() {
+				for out := range ch {
+					PublishToScope(context.Background(), dst, out)
+				}
+				mu.Lock()
+				delete(inner, &unsub)
+				mu.Unlock()
+			}()
+		}, WithOrdered())
+	}, func() {
+		unsubOuter()
+
+		mu.Lock()
+		defer mu.Unlock()
+		for unsub := range inner {
+			(*unsub)()
+		}
+	})
+	return dst
+}
+
+// Mux publishes every value read from input on each of the outputs, bridging a channel that is
+// fed from outside the event system, such as a database change listener, into one or more
+// scopes. The publishes of a value to the outputs are made concurrently with ctx, so they are
+// bounded by its deadline, and the next value is only read once they have all returned, so every
+// output sees the values in the order they were read. Mux returns right away; reading stops once
+// ctx is canceled or input is closed. Values an output refuses, for example because it has been
+// closed, are dropped for that output.
+func Mux[T any](ctx context.Context, input <-chan T, outputs ...*EventScope) {
+	go func() {
+		for {
+			var val T
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-input:
+				if !ok {
+					return
+				}
+				val = v
+			}
+
+			var wg sync.WaitGroup
+			for _, out := range outputs {
+				wg.Add(1)
+				go func(out *EventScope) {
+					defer wg.Done()
+					PublishToScope(ctx, out, val)
+				}(out)
+			}
+			wg.Wait()
+		}
+	}()
+}
+
+// ScopeStage is a stage of a pipeline built with Pipe: it derives an output scope from its input
+// scope, for example with FilterScope or MapScope.
+type ScopeStage func(in *EventScope) *EventScope
+
+// Pipe chains the stages into a pipeline fed by the event scope, where each stage takes the
+// output of the one before it as its input, and returns the output of the last stage. Without
+// stages, it returns the scope itself.
+func (e *EventScope) Pipe(stages ...ScopeStage) *EventScope {
+	out := e
+	for _, stage := range stages {
+		out = stage(out)
+	}
+	return out
+}
+
+// FilterStage returns a stage that keeps the values of type T for which pred returns true, as
+// with FilterScope.
+func FilterStage[T any](pred func(T) bool) ScopeStage {
+	return func(in *EventScope) *EventScope {
+		return FilterScope(in, pred)
+	}
+}
+
+// MapStage returns a stage that publishes fn(val) on a new scope for every value of type In, as
+// with MapScope.
+func MapStage[In, Out any](fn func(In) Out) ScopeStage {
+	return func(in *EventScope) *EventScope {
+		out := NewEventScope()
+		MapScope(in, fn, out)
+		return out
+	}
+}
@@ -0,0 +1,220 @@
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapScope(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	dst := NewEventScope()
+
+	MapScope(src, strconv.Itoa, dst)
+	assert.Zero(t, src.Stats().Subscribers)
+	PublishToScope(ctx, src, 0)
+
+	strs, unsub := SubscribeToScope[string](ctx, dst)
+	assert.Equal(t, 1, src.Stats().Subscribers)
+
+	for i := 1; i <= 3; i++ {
+		PublishToScope(ctx, src, i)
+		assert.Equal(t, strconv.Itoa(i), <-strs)
+	}
+
+	// A second subscriber shares the mapping subscription.
+	more, unsubMore := SubscribeToScope[string](ctx, dst)
+	assert.Equal(t, 1, src.Stats().Subscribers)
+
+	unsub()
+	assert.Equal(t, 1, src.Stats().Subscribers)
+	PublishToScope(ctx, src, 4)
+	assert.Equal(t, "4", <-more)
+
+	unsubMore()
+	assert.Zero(t, src.Stats().Subscribers)
+
+	// The mapping is recreated for the next subscriber.
+	strs, unsub = SubscribeToScope[string](ctx, dst)
+	defer unsub()
+	PublishToScope(ctx, src, 5)
+	assert.Equal(t, "5", <-strs)
+}
+
+func TestMapScope_SameScope(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	MapScope(testScope, strconv.Itoa, testScope)
+
+	strs, unsub := SubscribeToScope[string](ctx, testScope)
+	defer unsub()
+	PublishToScope(ctx, testScope, 1)
+	assert.Equal(t, "1", <-strs)
+}
+
+func TestFilterScope(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	even := FilterScope(src, func(i int) bool { return i%2 == 0 })
+	assert.Zero(t, src.Stats().Subscribers)
+
+	ints, unsub := SubscribeToScope[int](ctx, even, WithOrdered())
+	assert.Equal(t, 1, src.Stats().Subscribers)
+	for i := 1; i <= 4; i++ {
+		PublishToScope(ctx, src, i)
+	}
+	assert.Equal(t, 2, <-ints)
+	assert.Equal(t, 4, <-ints)
+
+	unsub()
+	assert.Zero(t, src.Stats().Subscribers)
+}
+
+func TestFilterScope_Pipeline(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	labels := NewEventScope()
+	positive := FilterScope(src, func(i int) bool { return i > 0 })
+	even := FilterScope(positive, func(i int) bool { return i%2 == 0 })
+	MapScope(even, strconv.Itoa, labels)
+
+	strs, unsub := SubscribeToScope[string](ctx, labels, WithOrdered())
+	for _, i := range []int{-2, 1, 2, 3, 4} {
+		PublishToScope(ctx, src, i)
+	}
+	assert.Equal(t, "2", <-strs)
+	assert.Equal(t, "4", <-strs)
+
+	// Releasing the last subscriber releases every stage of the pipeline.
+	unsub()
+	assert.Zero(t, even.Stats().Subscribers)
+	assert.Zero(t, positive.Stats().Subscribers)
+	assert.Zero(t, src.Stats().Subscribers)
+}
+
+func TestPipe(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	assert.Same(t, src, src.Pipe())
+	labels := src.Pipe(
+		FilterStage(func(i int) bool { return i > 0 }),
+		FilterStage(func(i int) bool { return i%2 == 0 }),
+		MapStage(strconv.Itoa),
+	)
+
+	strs, unsub := SubscribeToScope[string](ctx, labels, WithOrdered())
+	for _, i := range []int{-2, 1, 2, 3, 4} {
+		PublishToScope(ctx, src, i)
+	}
+	assert.Equal(t, "2", <-strs)
+	assert.Equal(t, "4", <-strs)
+
+	unsub()
+	assert.Zero(t, src.Stats().Subscribers)
+}
+
+func TestSubscribeCallback(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	received := make(chan int)
+	unsub := SubscribeCallback(ctx, testScope, func(i int) { received <- i }, WithOrdered())
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, i, <-received)
+	}
+
+	unsub()
+	assert.Zero(t, testScope.Stats().Subscribers)
+}
+
+func TestFlatMapScope(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+
+	// Every job spawns a scope on which its progress is reported.
+	jobs := make(chan *EventScope, 2)
+	out := FlatMapScope[string, int](src, func(job string) *EventScope {
+		if job == "skip" {
+			return nil
+		}
+		scope := NewEventScope()
+		jobs <- scope
+		return scope
+	})
+
+	progress, unsub := SubscribeToScope[int](ctx, out, WithOrdered())
+	PublishToScope(ctx, src, "first")
+	PublishToScope(ctx, src, "skip")
+	PublishToScope(ctx, src, "second")
+	first, second := <-jobs, <-jobs
+
+	// The inner subscription is made once fn returns.
+	assert.Eventually(t, func() bool {
+		return first.Stats().Subscribers == 1 && second.Stats().Subscribers == 1
+	}, time.Second, time.Millisecond)
+
+	PublishToScope(ctx, first, 1)
+	assert.Equal(t, 1, <-progress)
+	PublishToScope(ctx, second, 2)
+	assert.Equal(t, 2, <-progress)
+
+	// Closing an inner scope ends its subscription, the others keep forwarding.
+	first.Close()
+	PublishToScope(ctx, second, 3)
+	assert.Equal(t, 3, <-progress)
+
+	unsub()
+	assert.Zero(t, src.Stats().Subscribers)
+	assert.Zero(t, second.Stats().Subscribers)
+}
+
+func TestMux(t *testing.T) {
+	ctx := context.Background()
+	first := NewEventScope()
+	second := NewEventScope()
+	closed := NewEventScope()
+	closed.Close()
+
+	firstCh, unsubFirst := SubscribeToScope[int](ctx, first, WithOrdered())
+	defer unsubFirst()
+	secondCh, unsubSecond := SubscribeToScope[int](ctx, second, WithOrdered())
+	defer unsubSecond()
+
+	input := make(chan int)
+	Mux(ctx, input, first, closed, second)
+	for i := 0; i < 3; i++ {
+		input <- i
+	}
+	close(input)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, i, <-firstCh)
+		assert.Equal(t, i, <-secondCh)
+	}
+}
+
+func TestMux_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testScope := NewEventScope()
+	testingCh, unsub := SubscribeToScope[int](context.Background(), testScope)
+	defer unsub()
+
+	input := make(chan int, 1)
+	Mux(ctx, input, testScope)
+	input <- 1
+	assert.Equal(t, 1, <-testingCh)
+
+	// Values sent after the context is canceled are left in the input.
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	input <- 2
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, input, 1)
+}
@@ -0,0 +1,84 @@
+package pubsub
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// EventScopeOption configures an EventScope at creation time.
+type EventScopeOption func(*EventScope)
+
+// WithCodec sets the codec the scope uses whenever a message needs to be serialized. The default
+// is JSONCodec.
+func WithCodec(c Codec) EventScopeOption {
+	return func(e *EventScope) {
+		e.codec = c
+	}
+}
+
+// WithCopyOnWrite makes the scope keep the subscribers of each type in a slice that is copied and
+// replaced on every subscribe and unsubscribe. Publishing then iterates over a consistent snapshot
+// of the subscribers, without the overhead of iterating a sync.Map, at the cost of copying the
+// slice whenever subscribers change. It suits scopes whose subscribers rarely change.
+func WithCopyOnWrite() EventScopeOption {
+	return func(e *EventScope) {
+		e.copyOnWrite = true
+	}
+}
+
+// SubscribeOption configures a single subscription.
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	ordered bool
+
+	priority    int
+	prioritized bool
+	passive     bool
+	compression *compression
+	numaLocal   bool
+	persistent  bool
+	priorityFn  func(any) int
+
+	// id, if set, is the ID the subscriber is registered with instead of a new one.
+	id uuid.UUID
+
+	// registered, if set, is called with the subscriber once it has been registered. It is
+	// called with the scope's seqMu held, so no message is published while it runs.
+	registered func(*topic, *subscriber)
+}
+
+// withPassive marks the subscriber as passive, so that it does not make the scope interested in
+// its type. It is used by subscriptions that only relay values elsewhere.
+func withPassive() SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.passive = true
+	}
+}
+
+// withSubscriberID registers the subscriber with the given ID, so that a Subscription and its
+// subscriber share one, even as the subscriber is moved from one scope to another.
+func withSubscriberID(id uuid.UUID) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.id = id
+	}
+}
+
+// WithErrorHandler sets a function that is called with the errors that happen while delivering
+// values and cannot be returned to anyone, such as the error of a SubscribeWithRetry handler that
+// kept failing. Without a handler such errors are logged with the log package.
+func WithErrorHandler(fn func(err error)) EventScopeOption {
+	return func(e *EventScope) {
+		e.onError = fn
+	}
+}
+
+// reportError passes err to the scope's error handler, or logs it if there is none.
+func (e *EventScope) reportError(err error) {
+	if e.onError != nil {
+		e.onError(err)
+		return
+	}
+	log.Print(err)
+}
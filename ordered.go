@@ -0,0 +1,96 @@
+package pubsub
+
+import "sync"
+
+// WithOrdered makes a subscription receive messages in the order they were published. Every
+// message is numbered when it is published, and messages that arrive early are held back until
+// the ones before them have been forwarded. Messages whose delivery is abandoned because the
+// publish context was canceled are skipped rather than waited for.
+func WithOrdered() SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.ordered = true
+	}
+}
+
+// WithTypeOrderingMutex makes the scope deliver the values of each type in the order they were
+// published to every subscriber, not only to those created with WithOrdered. A publish takes a
+// lock of its type before the message is numbered, which is released once every subscriber has
+// taken the message, so concurrent publishes of a type are delivered one after the other in the
+// order they took the lock. Publishing a type waits for the previous message of the type to be
+// taken by every subscriber, or abandoned once its publish context is canceled, so a subscriber
+// that stops reading without unsubscribing holds up the publishers of its type. Scopes created
+// with WithSerialDelivery already deliver in order and take no lock. With WithBoundedBuffer,
+// messages are ordered as they enter the buffer.
+func WithTypeOrderingMutex() EventScopeOption {
+	return func(e *EventScope) {
+		e.typeOrdering = true
+	}
+}
+
+// lockType takes the ordering lock of t if the scope was created with WithTypeOrderingMutex, and
+// returns the function that releases it.
+func (e *EventScope) lockType(t eventType) func() {
+	if !e.typeOrdering || e.serial != nil {
+		return func() {}
+	}
+	v, _ := e.typeLocks.LoadOrStore(t.key, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// messageHeap is a min-heap of messages ordered by topicSeq. It implements heap.Interface.
+type messageHeap []message
+
+func (h messageHeap) Len() int           { return len(h) }
+func (h messageHeap) Less(i, j int) bool { return h[i].topicSeq < h[j].topicSeq }
+func (h messageHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *messageHeap) Push(x any) {
+	*h = append(*h, x.(message))
+}
+
+func (h *messageHeap) Pop() any {
+	old := *h
+	n := len(old)
+	msg := old[n-1]
+	old[n-1] = message{}
+	*h = old[:n-1]
+	return msg
+}
+
+// skipList records the sequence numbers of deliveries that were abandoned, so an ordered
+// subscriber does not wait for them forever.
+type skipList struct {
+	mu     sync.Mutex
+	seqs   map[int64]bool
+	notify chan struct{}
+}
+
+func (s *skipList) init() {
+	s.seqs = make(map[int64]bool)
+	s.notify = make(chan struct{}, 1)
+}
+
+func (s *skipList) add(seq int64) {
+	s.mu.Lock()
+	s.seqs[seq] = true
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// take reports whether seq was skipped and forgets it.
+func (s *skipList) take(seq int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.seqs[seq] {
+		return false
+	}
+	delete(s.seqs, seq)
+	return true
+}
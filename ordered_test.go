@@ -0,0 +1,120 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdered(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsub()
+
+	// Each publish hands its message to a separate goroutine, so without sequencing the
+	// messages race each other to the subscriber.
+	const count = 200
+	for i := 0; i < count; i++ {
+		PublishToScope(ctx, testScope, i)
+		if i%7 == 0 {
+			runtime.Gosched()
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, <-testingCh)
+	}
+}
+
+func TestOrdered_SkipsAbandoned(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsub()
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	// The canceled publish may or may not reach the subscriber, but it must not block the
+	// messages after it.
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(cancelled, testScope, 2)
+	PublishToScope(ctx, testScope, 3)
+
+	assert.Equal(t, 1, <-testingCh)
+	val := <-testingCh
+	if val == 2 {
+		val = <-testingCh
+	}
+	assert.Equal(t, 3, val)
+}
+
+func TestTypeOrderingMutex(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithTypeOrderingMutex())
+
+	// Neither subscriber is ordered, so without the lock the messages race each other to them.
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	seqs, unsubSeqs := subscribe(ctx, testScope, func(_ int, msg message) int64 { return msg.seq })
+	defer unsubSeqs()
+
+	const publishers, count = 4, 50
+	for p := 0; p < publishers; p++ {
+		p := p
+		go func() {
+			for i := 0; i < count; i++ {
+				PublishToScope(ctx, testScope, p*count+i)
+			}
+		}()
+	}
+
+	next := make([]int, publishers)
+	var last int64
+	for i := 0; i < publishers*count; i++ {
+		val := <-testingCh
+		p := val / count
+		assert.Equal(t, p*count+next[p], val)
+		next[p]++
+
+		seq := <-seqs
+		assert.Greater(t, seq, last)
+		last = seq
+	}
+}
+
+func BenchmarkPublish_TypeOrdering(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts []EventScopeOption
+	}{
+		{name: "Unordered"},
+		{name: "TypeOrderingMutex", opts: []EventScopeOption{WithTypeOrderingMutex()}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			ctx := context.Background()
+			testScope := NewEventScope(bench.opts...)
+			ch, unsub := SubscribeToScope[int](ctx, testScope)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for range ch {
+				}
+			}()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					PublishToScope(ctx, testScope, 1)
+				}
+			})
+			b.StopTimer()
+			unsub()
+			<-done
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultPartitionIdleTTL is how long a partition of a PartitionedScope may go without
+// subscribers and messages before it is evicted, unless WithPartitionIdleTTL says otherwise.
+const DefaultPartitionIdleTTL = 5 * time.Minute
+
+// PartitionOption configures a PartitionedScope at creation time.
+type PartitionOption func(*partitionConfig)
+
+type partitionConfig struct {
+	idleTTL time.Duration
+	opts    []EventScopeOption
+}
+
+// WithPartitionIdleTTL sets how long a partition may go without subscribers and without messages
+// before it is evicted. ttl must be positive.
+func WithPartitionIdleTTL(ttl time.Duration) PartitionOption {
+	if ttl <= 0 {
+		panic("pubsub: partition idle TTL must be positive")
+	}
+	return func(c *partitionConfig) {
+		c.idleTTL = ttl
+	}
+}
+
+// WithPartitionScopeOptions sets the options every partition's event scope is created with, for
+// example WithReplay so that subscribers can catch up on what a partition received before they
+// subscribed.
+func WithPartitionScopeOptions(opts ...EventScopeOption) PartitionOption {
+	return func(c *partitionConfig) {
+		c.opts = append(c.opts, opts...)
+	}
+}
+
+// PartitionedScope routes the values of type T published on a source scope to one event scope per
+// key, so that the values of a key can be subscribed to on their own, like the partitions of a
+// topic. Call Close to stop routing.
+type PartitionedScope[T any, K comparable] struct {
+	clock  Clock
+	key    func(T) K
+	config partitionConfig
+
+	mu         sync.Mutex
+	partitions map[K]*partition
+
+	unsub     UnsubFn
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type partition struct {
+	scope *EventScope
+
+	// used is when the partition was last returned by Partition or received a value.
+	used time.Time
+}
+
+// NewPartitionedScope subscribes to T on src and publishes each value on the partition of the key
+// key returns for it, in the order the values were published on src. A partition is created the
+// first time a value is routed to it or it is requested with Partition.
+//
+// A partition that has had no subscribers and no values for longer than the idle TTL, which is
+// DefaultPartitionIdleTTL unless set with WithPartitionIdleTTL, is evicted and its scope closed.
+// Idle time is measured with the clock of src.
+// Callers should therefore get a partition from Partition when they subscribe to it rather than
+// hold on to its scope while it is idle.
+func NewPartitionedScope[T any, K comparable](src *EventScope, key func(T) K, opts ...PartitionOption) *PartitionedScope[T, K] {
+	p := &PartitionedScope[T, K]{
+		clock:      src.clock,
+		key:        key,
+		config:     partitionConfig{idleTTL: DefaultPartitionIdleTTL},
+		partitions: make(map[K]*partition),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&p.config)
+	}
+
+	p.unsub = SubscribeCallback(context.Background(), src, func(val T) {
+		PublishToScope(context.Background(), p.Partition(p.key(val)), val)
+	}, WithOrdered())
+	// The ticker is created before returning, so that a clock advanced right after cannot be
+	// missed.
+	go p.evictIdle(p.clock.NewTicker(p.config.idleTTL / 2))
+	return p
+}
+
+// Partition returns the event scope of the partition for k, creating it if it does not exist.
+func (p *PartitionedScope[T, K]) Partition(k K) *EventScope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	part, ok := p.partitions[k]
+	if !ok {
+		part = &partition{scope: NewEventScope(append([]EventScopeOption{WithClock(p.clock)}, p.config.opts...)...)}
+		p.partitions[k] = part
+	}
+	part.used = p.clock.Now()
+	return part.scope
+}
+
+// Len returns the number of partitions that currently exist.
+func (p *PartitionedScope[T, K]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.partitions)
+}
+
+// evictIdle periodically evicts the partitions that have been idle for longer than the idle TTL,
+// until the partitioned scope is closed.
+func (p *PartitionedScope[T, K]) evictIdle(ticker *Ticker) {
+	defer close(p.done)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.evict(p.clock.Now().Add(-p.config.idleTTL))
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// evict closes and forgets the partitions without subscribers or pending messages that have not
+// been used since before.
+func (p *PartitionedScope[T, K]) evict(before time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for k, part := range p.partitions {
+		if !part.used.Before(before) || part.scope.Len() > 0 || part.scope.Stats().Subscribers > 0 {
+			continue
+		}
+		delete(p.partitions, k)
+		part.scope.Close()
+	}
+}
+
+// Close stops routing values to the partitions and closes every partition's scope. Calling Close
+// more than once has no effect.
+func (p *PartitionedScope[T, K]) Close() {
+	p.closeOnce.Do(func() {
+		p.unsub()
+		close(p.stop)
+		<-p.done
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for k, part := range p.partitions {
+			delete(p.partitions, k)
+			part.scope.Close()
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	Customer string
+	ID       int
+}
+
+func TestPartitionedScope(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	p := NewPartitionedScope(src, func(o order) string { return o.Customer })
+	defer p.Close()
+
+	alice, unsubAlice := SubscribeToScope[order](ctx, p.Partition("alice"), WithOrdered())
+	defer unsubAlice()
+	bob, unsubBob := SubscribeToScope[order](ctx, p.Partition("bob"), WithOrdered())
+	defer unsubBob()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, PublishToScope(ctx, src, order{Customer: "alice", ID: i}))
+		require.NoError(t, PublishToScope(ctx, src, order{Customer: "bob", ID: i}))
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, order{Customer: "alice", ID: i}, <-alice)
+		assert.Equal(t, order{Customer: "bob", ID: i}, <-bob)
+	}
+	assert.Equal(t, 2, p.Len())
+}
+
+func TestPartitionedScope_EvictsIdle(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	p := NewPartitionedScope(src, func(o order) string { return o.Customer }, WithPartitionIdleTTL(20*time.Millisecond))
+	defer p.Close()
+
+	_, unsub := SubscribeToScope[order](ctx, p.Partition("alice"))
+	require.NoError(t, PublishToScope(ctx, src, order{Customer: "bob"}))
+	require.Eventually(t, func() bool { return p.Len() == 2 }, time.Second, time.Millisecond)
+
+	// bob has no subscribers, alice keeps hers.
+	idle := p.Partition("bob")
+	assert.Eventually(t, func() bool { return p.Len() == 1 }, time.Second, time.Millisecond)
+	ch, _ := SubscribeToScope[order](ctx, idle)
+	_, ok := <-ch
+	assert.False(t, ok, "evicted partition is closed")
+
+	unsub()
+	assert.Eventually(t, func() bool { return p.Len() == 0 }, time.Second, time.Millisecond)
+
+	// A partition is created again when it is needed.
+	assert.NotSame(t, idle, p.Partition("bob"))
+	assert.Equal(t, 1, p.Len())
+}
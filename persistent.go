@@ -0,0 +1,28 @@
+package pubsub
+
+// WithPersistentDeliveryGoroutine makes a subscription receive its messages through a queue of
+// its own instead of a goroutine started for every message. A publish adds the message to the
+// queue of every such subscriber from the publishing goroutine, and the goroutine that forwards
+// the subscription's values takes them from there, which saves starting a goroutine per publish
+// and subscriber and keeps the messages of a publisher in order. The queue holds as many messages
+// as the scope's high water mark, see WithHighWaterMark; once it is full, publishing blocks until
+// the subscriber catches up, stops receiving, or the publish context is canceled. Subscribers of
+// topics with prioritized subscribers, see WithPriority, are delivered as usual.
+func WithPersistentDeliveryGoroutine() SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.persistent = true
+	}
+}
+
+// drain settles the messages left in the queue of a persistent subscriber that stopped
+// receiving.
+func (s *subscriber) drain() {
+	for {
+		select {
+		case msg := <-s.ch:
+			s.settle(msg)
+		default:
+			return
+		}
+	}
+}
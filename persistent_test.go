@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentDeliveryGoroutine(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithPersistentDeliveryGoroutine())
+	defer unsub()
+
+	// Publishing queues the messages without starting goroutines, and in publish order.
+	before := runtime.NumGoroutine()
+	const count = 20
+	for i := 0; i < count; i++ {
+		require.NoError(t, PublishToScope(ctx, testScope, i))
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, <-testingCh)
+	}
+}
+
+func TestPersistentDeliveryGoroutine_Full(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithHighWaterMark(2))
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithPersistentDeliveryGoroutine())
+
+	// One message waits to be received and two fill the queue.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, PublishToScope(ctx, testScope, i))
+	}
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, PublishToScope(timeout, testScope, 3))
+	assert.Equal(t, int64(1), testScope.Stats().Dropped)
+	assert.Equal(t, 0, <-testingCh)
+
+	// Messages left in the queue are settled once the subscriber unsubscribes.
+	unsub()
+	drained, cancelDrained := context.WithTimeout(ctx, time.Second)
+	defer cancelDrained()
+	assert.NoError(t, testScope.WaitUntilDrained(drained))
+}
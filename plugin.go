@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Plugin extends an EventScope. Init is called when the plugin is added to a scope with
+// UsePlugin, and Close is called when the scope is closed.
+type Plugin interface {
+	Init(scope *EventScope) error
+	Close() error
+}
+
+// UsePlugin initializes p with the event scope and registers it to be closed by Close. If Init
+// returns an error, the plugin is not registered. ErrScopeClosed is returned if the scope has
+// been closed, in which case the plugin is closed again if it was already initialized.
+func (e *EventScope) UsePlugin(p Plugin) error {
+	if e.closed.Load() {
+		return ErrScopeClosed
+	}
+	if err := p.Init(e); err != nil {
+		return err
+	}
+
+	e.pluginsMu.Lock()
+	defer e.pluginsMu.Unlock()
+
+	// Close sets the flag before collecting the plugins, so a plugin that is added after
+	// that would never be closed.
+	if e.closed.Load() {
+		return errors.Join(ErrScopeClosed, p.Close())
+	}
+	e.plugins = append(e.plugins, p)
+	return nil
+}
+
+// MetricsPlugin is a Plugin that counts the messages published on a scope per type. Values are
+// counted under the type they were published as, such as an interface type rather than the type
+// of the value that implements it.
+type MetricsPlugin struct {
+	counts sync.Map // type name -> *atomic.Int64
+	unsub  UnsubFn
+	done   chan struct{}
+}
+
+// NewMetricsPlugin creates a MetricsPlugin. It starts counting once it is added to a scope.
+func NewMetricsPlugin() *MetricsPlugin {
+	return &MetricsPlugin{}
+}
+
+func (m *MetricsPlugin) Init(scope *EventScope) error {
+	ch, unsub := subscribeAll(context.Background(), scope)
+	m.unsub = unsub
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+		for msg := range ch {
+			c, _ := m.counts.LoadOrStore(msg.typeName, &atomic.Int64{})
+			c.(*atomic.Int64).Add(1)
+		}
+	}()
+	return nil
+}
+
+func (m *MetricsPlugin) Close() error {
+	if m.unsub == nil {
+		return nil
+	}
+	m.unsub()
+	<-m.done
+	return nil
+}
+
+// Published returns the number of messages of the named type that have been counted. Type names
+// are formatted like reflect.Type.String.
+func (m *MetricsPlugin) Published(typeName string) int64 {
+	c, ok := m.counts.Load(typeName)
+	if !ok {
+		return 0
+	}
+	return c.(*atomic.Int64).Load()
+}
+
+// Counts returns a snapshot of the message counts for every type seen so far.
+func (m *MetricsPlugin) Counts() map[string]int64 {
+	counts := make(map[string]int64)
+	m.counts.Range(func(name, c any) bool {
+		counts[name.(string)] = c.(*atomic.Int64).Load()
+		return true
+	})
+	return counts
+}
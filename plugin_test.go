@@ -0,0 +1,140 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPlugin struct {
+	name    string
+	initErr error
+	closed  *[]string
+	scope   *EventScope
+}
+
+func (p *recordingPlugin) Init(scope *EventScope) error {
+	p.scope = scope
+	return p.initErr
+}
+
+func (p *recordingPlugin) Close() error {
+	*p.closed = append(*p.closed, p.name)
+	return nil
+}
+
+func TestUsePlugin(t *testing.T) {
+	testScope := NewEventScope()
+	var closed []string
+
+	first := &recordingPlugin{name: "first", closed: &closed}
+	second := &recordingPlugin{name: "second", closed: &closed}
+	failing := &recordingPlugin{name: "failing", closed: &closed, initErr: errors.New("init failed")}
+
+	require.NoError(t, testScope.UsePlugin(first))
+	require.NoError(t, testScope.UsePlugin(second))
+	assert.Error(t, testScope.UsePlugin(failing))
+	assert.Equal(t, testScope, first.scope)
+
+	require.NoError(t, testScope.Close())
+	assert.Equal(t, []string{"second", "first"}, closed)
+
+	require.NoError(t, testScope.Close())
+	assert.Len(t, closed, 2)
+}
+
+func TestClose_Unsubscribes(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, testScope.Close())
+
+	_, ok := <-testingCh
+	assert.False(t, ok)
+}
+
+func TestMetricsPlugin(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	metrics := NewMetricsPlugin()
+	require.NoError(t, testScope.UsePlugin(metrics))
+
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, 2)
+	PublishToScope(ctx, testScope, "three")
+	PublishToScope[error](ctx, testScope, nil)
+	PublishToScope[error](ctx, testScope, errors.New("four"))
+
+	assert.Eventually(t, func() bool {
+		return metrics.Published("int") == 2 && metrics.Published("string") == 1 &&
+			metrics.Published("error") == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, map[string]int64{"int": 2, "string": 1, "error": 2}, metrics.Counts())
+
+	require.NoError(t, testScope.Close())
+}
+
+func TestClose_Closed(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	require.NoError(t, testScope.Close())
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+
+	var closed []string
+	plugin := &recordingPlugin{name: "late", closed: &closed}
+	assert.ErrorIs(t, testScope.UsePlugin(plugin), ErrScopeClosed)
+	assert.Nil(t, plugin.scope)
+
+	assert.ErrorIs(t, PublishToScope(ctx, testScope, 1), ErrScopeClosed)
+}
+
+func TestClose_ConcurrentPublish(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	go func() {
+		for range testingCh {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = PublishToScope(ctx, testScope, i)
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, testScope.Close())
+	}()
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, ErrScopeClosed)
+		}
+	}
+	assert.ErrorIs(t, PublishToScope(ctx, testScope, 0), ErrScopeClosed)
+}
+
+func TestMetricsPlugin_CloseWithoutInit(t *testing.T) {
+	assert.NoError(t, NewMetricsPlugin().Close())
+}
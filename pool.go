@@ -0,0 +1,136 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// SharedPool is a fixed set of goroutines that deliver the messages of the event scopes added to
+// it. Without a pool, a scope starts a goroutine for every message and subscriber, which suits
+// most applications; with many scopes or a lot of slow subscribers, a pool bounds the number of
+// goroutines instead, at the cost of deliveries waiting for a free goroutine.
+//
+// Deliveries are queued without limit, so publishing never blocks on the pool. A subscriber that
+// does not receive its messages holds a pool goroutine for each pending delivery, until the
+// delivery's context is canceled or the subscriber unsubscribes, and can therefore stall every
+// scope of the pool.
+type SharedPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	tasks  []func()
+	closed bool
+	scopes map[*EventScope]struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSharedPool starts a pool of n goroutines and adds the scopes to it. n must be positive.
+func NewSharedPool(n int, scopes ...*EventScope) *SharedPool {
+	if n <= 0 {
+		panic("pubsub: shared pool size must be positive")
+	}
+
+	p := &SharedPool{scopes: make(map[*EventScope]struct{})}
+	p.cond = sync.NewCond(&p.mu)
+	p.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go p.work()
+	}
+	for _, scope := range scopes {
+		p.Add(scope)
+	}
+	return p
+}
+
+// Add makes the scope deliver its messages with the pool's goroutines. A scope can only belong
+// to a single pool; adding it to another pool moves it there.
+func (p *SharedPool) Add(scope *EventScope) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	if old := scope.pool.Swap(p); old != nil && old != p {
+		old.forget(scope)
+	}
+	p.scopes[scope] = struct{}{}
+}
+
+// Remove makes the scope start its own goroutines again. Deliveries already queued on the pool
+// are still made by the pool.
+func (p *SharedPool) Remove(scope *EventScope) {
+	scope.pool.CompareAndSwap(p, nil)
+	p.forget(scope)
+}
+
+func (p *SharedPool) forget(scope *EventScope) {
+	p.mu.Lock()
+	delete(p.scopes, scope)
+	p.mu.Unlock()
+}
+
+// Close removes every scope from the pool and stops its goroutines once the queued deliveries
+// have been made.
+func (p *SharedPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	for scope := range p.scopes {
+		scope.pool.CompareAndSwap(p, nil)
+	}
+	p.scopes = nil
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// submit queues task to be run by the pool. It reports false if the pool has been closed.
+func (p *SharedPool) submit(task func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+	p.tasks = append(p.tasks, task)
+	p.cond.Signal()
+	return true
+}
+
+func (p *SharedPool) work() {
+	defer p.wg.Done()
+
+	for {
+		p.mu.Lock()
+		for len(p.tasks) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.tasks) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		task := p.tasks[0]
+		p.tasks[0] = nil
+		p.tasks = p.tasks[1:]
+		p.mu.Unlock()
+
+		task()
+	}
+}
+
+// spawn runs fn, which delivers a message published with ctx, through the scope's delivery
+// channel if it was created with NewChannelScope, on the scope's pool, or on a new goroutine,
+// within the scope's concurrency limit, if the scope has neither.
+func (e *EventScope) spawn(ctx context.Context, fn func()) {
+	if e.channel != nil && e.channel.submit(ctx, fn) {
+		return
+	}
+	if p := e.pool.Load(); p != nil && p.submit(fn) {
+		return
+	}
+	e.goLimited(ctx, fn)
+}
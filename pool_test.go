@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedPool(t *testing.T) {
+	ctx := context.Background()
+	first := NewEventScope()
+	second := NewEventScope()
+	pool := NewSharedPool(4, first)
+	defer pool.Close()
+	pool.Add(second)
+
+	ints, unsubInts := SubscribeToScope[int](ctx, first, WithOrdered())
+	defer unsubInts()
+	strs, unsubStrs := SubscribeToScope[string](ctx, second)
+	defer unsubStrs()
+
+	// Nothing is received yet, so every delivery is pending. Without the pool each of them
+	// would hold a goroutine.
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		PublishToScope(ctx, first, i)
+		PublishToScope(ctx, second, "value")
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+4)
+
+	var gotInts []int
+	gotStrs := 0
+	for len(gotInts) < 100 || gotStrs < 100 {
+		select {
+		case i := <-ints:
+			gotInts = append(gotInts, i)
+		case <-strs:
+			gotStrs++
+		}
+	}
+	for i, got := range gotInts {
+		assert.Equal(t, i, got)
+	}
+}
+
+func TestSharedPool_Remove(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	pool := NewSharedPool(1, testScope)
+	defer pool.Close()
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope, WithPriority(1))
+	defer unsub()
+	PublishToScope(ctx, testScope, 1)
+	assert.Equal(t, 1, <-ints)
+
+	pool.Remove(testScope)
+	assert.Nil(t, testScope.pool.Load())
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 2, <-ints)
+
+	pool.Add(testScope)
+	pool.Close()
+	assert.Nil(t, testScope.pool.Load())
+	PublishToScope(ctx, testScope, 3)
+	assert.Equal(t, 3, <-ints)
+}
@@ -0,0 +1,172 @@
+package pubsub
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// WithPriority gives the subscriber a priority. Each message is handed to the subscribers of its
+// type in order of decreasing priority: subscribers with a lower priority only receive the message
+// once every subscriber with a higher priority has received it, or stopped receiving. Subscribers
+// without a priority have priority 0, and subscribers with the same priority receive the message
+// concurrently.
+//
+// A slow subscriber delays every subscriber with a lower priority, so priorities are best kept for
+// subscribers that need to observe a message before others do.
+func WithPriority(p int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.priority = p
+		c.prioritized = true
+	}
+}
+
+// add registers sub on the topic. Once a prioritized subscriber has been added, the topic also
+// keeps its subscribers sorted by priority.
+func (t *topic) add(sub *subscriber, prioritized bool) {
+	t.subs.Store(sub.id, sub)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.e.copyOnWrite {
+		var subs []*subscriber
+		if old := t.snapshot.Load(); old != nil {
+			subs = append(subs, *old...)
+		}
+		subs = append(subs, sub)
+		t.snapshot.Store(&subs)
+	}
+	if t.sorted == nil && !prioritized {
+		return
+	}
+	if t.sorted == nil {
+		t.subs.Range(func(_, value any) bool {
+			if s := value.(*subscriber); s != sub {
+				t.sorted = append(t.sorted, s)
+			}
+			return true
+		})
+	}
+	// Subscribers with the same priority stay in the order they were added.
+	i := sort.Search(len(t.sorted), func(i int) bool { return t.sorted[i].priority < sub.priority })
+	t.sorted = append(t.sorted, nil)
+	copy(t.sorted[i+1:], t.sorted[i:])
+	t.sorted[i] = sub
+}
+
+// remove unregisters the subscriber with the given id from the topic. Removing a subscriber that
+// is not registered has no effect.
+func (t *topic) remove(id uuid.UUID) {
+	if _, ok := t.subs.LoadAndDelete(id); !ok {
+		return
+	}
+
+	t.mu.Lock()
+	for i, sub := range t.sorted {
+		if sub.id == id {
+			t.sorted = append(t.sorted[:i:i], t.sorted[i+1:]...)
+			break
+		}
+	}
+	if old := t.snapshot.Load(); old != nil {
+		subs := make([]*subscriber, 0, len(*old))
+		for _, sub := range *old {
+			if sub.id != id {
+				subs = append(subs, sub)
+			}
+		}
+		t.snapshot.Store(&subs)
+	}
+	t.mu.Unlock()
+
+	t.release()
+	t.changed()
+	t.e.observeRemoved(t.t, id)
+}
+
+// release drops the reference of a removed subscriber, and deletes the topic from the scope if
+// that was its last one, so that the scope does not accumulate topics for types that are only
+// subscribed to for a while. Subscribers registered later start a new topic.
+func (t *topic) release() {
+	t.e.seqMu.Lock()
+	defer t.e.seqMu.Unlock()
+
+	t.refs--
+	if t.refs > 0 {
+		return
+	}
+	t.mu.RLock()
+	demanded := len(t.demands) > 0
+	t.mu.RUnlock()
+	if !demanded {
+		t.e.topics().CompareAndDelete(t.t.key, t)
+	}
+}
+
+// each calls fn for every subscriber of the topic, in order of decreasing priority if it has
+// prioritized subscribers, and from a consistent snapshot if the scope was created with
+// WithCopyOnWrite. It reports whether the subscribers are prioritized.
+func (t *topic) each(fn func(*subscriber)) bool {
+	t.mu.RLock()
+	sorted := t.sorted
+	t.mu.RUnlock()
+
+	if sorted == nil {
+		if snapshot := t.snapshot.Load(); snapshot != nil {
+			for _, sub := range *snapshot {
+				fn(sub)
+			}
+			return false
+		}
+		t.subs.Range(func(_, value any) bool {
+			fn(value.(*subscriber))
+			return true
+		})
+		return false
+	}
+	for _, sub := range sorted {
+		fn(sub)
+	}
+	return true
+}
+
+// deliverByPriority hands the deliveries to their subscribers one priority at a time, starting
+// with the highest, and calls done, if not nil, once every subscriber has received the message,
+// stopped receiving, or given up because ctx was canceled. It does not wait: each priority is
+// started by the last delivery of the one before it to settle.
+func (e *EventScope) deliverByPriority(ctx context.Context, deliveries []delivery, done func()) {
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].sub.priority > deliveries[j].sub.priority
+	})
+
+	var start func(deliveries []delivery)
+	start = func(deliveries []delivery) {
+		if len(deliveries) == 0 {
+			if done != nil {
+				done()
+			}
+			return
+		}
+
+		n := 1
+		for n < len(deliveries) && deliveries[n].sub.priority == deliveries[0].sub.priority {
+			n++
+		}
+		group, rest := deliveries[:n], deliveries[n:]
+
+		remaining := atomic.Int64{}
+		remaining.Store(int64(n))
+		for _, d := range group {
+			d := d
+			d.msg.received = func() {
+				if remaining.Add(-1) == 0 {
+					start(rest)
+				}
+			}
+			e.spawn(ctx, func() { e.send(ctx, d) })
+		}
+	}
+	start(deliveries)
+}
@@ -0,0 +1,60 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPriority(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	low, unsubLow := SubscribeToScope[int](ctx, testScope, WithPriority(1))
+	defer unsubLow()
+	plain, unsubPlain := SubscribeToScope[int](ctx, testScope)
+	defer unsubPlain()
+	high, unsubHigh := SubscribeToScope[int](ctx, testScope, WithPriority(3))
+	defer unsubHigh()
+
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+
+		// Nothing reaches the lower priorities until the highest priority subscriber has
+		// received the value.
+		select {
+		case <-low:
+			t.Fatal("priority 1 subscriber received before priority 3")
+		case <-plain:
+			t.Fatal("subscriber without priority received before priority 3")
+		case <-time.After(20 * time.Millisecond):
+		}
+		assert.Equal(t, i, <-high)
+
+		select {
+		case <-plain:
+			t.Fatal("subscriber without priority received before priority 1")
+		case <-time.After(20 * time.Millisecond):
+		}
+		assert.Equal(t, i, <-low)
+		assert.Equal(t, i, <-plain)
+	}
+}
+
+func TestWithPriority_Unsubscribed(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	low, unsubLow := SubscribeToScope[int](ctx, testScope, WithPriority(1))
+	defer unsubLow()
+	_, unsubHigh := SubscribeToScope[int](ctx, testScope, WithPriority(3))
+
+	PublishToScope(ctx, testScope, 1)
+	unsubHigh()
+	assert.Equal(t, 1, <-low)
+
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 2, <-low)
+}
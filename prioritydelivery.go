@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// WithPriorityDelivery makes the subscription hand its messages to the receiver in order of the
+// priority priorityFn assigns to their values, lower values first, instead of in the order they
+// arrive. Messages wait for the receiver in a queue of the subscription, so that a message with a
+// higher priority, such as a shutdown notice, overtakes those that are already waiting when the
+// receiver is behind. Messages with the same priority are received in publish order.
+//
+// priorityFn is called from the publishing goroutine with every value delivered to the
+// subscription. The queue is not bounded: publishing does not wait for the receiver, whose
+// backlog can be watched with WithBackpressureCallback. The messages of a subscription with
+// priority delivery are not ordered as with WithOrdered.
+func WithPriorityDelivery(priorityFn func(any) int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.priorityFn = priorityFn
+	}
+}
+
+// priorityQueue holds the messages delivered to a subscriber created with WithPriorityDelivery
+// until its forwarder takes them. notify is signaled whenever a message is added.
+type priorityQueue struct {
+	priorityFn func(any) int
+	notify     chan struct{}
+
+	mu      sync.Mutex
+	pending prioritizedHeap
+	closed  bool
+}
+
+func newPriorityQueue(priorityFn func(any) int) *priorityQueue {
+	return &priorityQueue{
+		priorityFn: priorityFn,
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// push adds msg to the queue of sub. It reports false, and settles the message, if the
+// subscriber has stopped receiving.
+func (q *priorityQueue) push(sub *subscriber, msg message) bool {
+	priority := q.priorityFn(msg.val)
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		sub.settle(msg)
+		return false
+	}
+	heap.Push(&q.pending, prioritizedMessage{priority: priority, msg: msg})
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// pop takes the message with the highest priority out of the queue, if there is one.
+func (q *priorityQueue) pop() (message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pending.Len() == 0 {
+		return message{}, false
+	}
+	return heap.Pop(&q.pending).(prioritizedMessage).msg, true
+}
+
+// forward sends the messages of the queue with send, highest priority first, until ctx is done or
+// send fails. The messages left in the queue are settled, and later ones rejected.
+func (q *priorityQueue) forward(ctx context.Context, sub *subscriber, send func(message) bool) {
+	defer func() {
+		q.mu.Lock()
+		q.closed = true
+		pending := q.pending
+		q.pending = nil
+		q.mu.Unlock()
+		for _, p := range pending {
+			sub.settle(p.msg)
+		}
+	}()
+
+	for {
+		msg, ok := q.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.notify:
+			}
+			continue
+		}
+		if !send(msg) {
+			return
+		}
+	}
+}
+
+type prioritizedMessage struct {
+	priority int
+	msg      message
+}
+
+// prioritizedHeap orders messages by priority, and messages with the same priority by sequence
+// number.
+type prioritizedHeap []prioritizedMessage
+
+func (h prioritizedHeap) Len() int { return len(h) }
+func (h prioritizedHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].msg.seq < h[j].msg.seq
+}
+func (h prioritizedHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *prioritizedHeap) Push(x any) {
+	*h = append(*h, x.(prioritizedMessage))
+}
+
+func (h *prioritizedHeap) Pop() any {
+	old := *h
+	n := len(old)
+	p := old[n-1]
+	old[n-1] = prioritizedMessage{}
+	*h = old[:n-1]
+	return p
+}
@@ -0,0 +1,66 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityDelivery(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	// Messages are pushed to the queue of a persistent subscriber before the publish returns.
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope,
+		WithPriorityDelivery(func(val any) int { return val.(int) }),
+		WithPersistentDeliveryGoroutine())
+	defer unsub()
+
+	for _, val := range []int{5, 3, 4, 1, 2} {
+		PublishToScope(ctx, testScope, val)
+	}
+
+	var received []int
+	for i := 0; i < 5; i++ {
+		received = append(received, <-testingCh)
+	}
+	// The forwarder may have taken the first message before the others were published.
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, received)
+	assert.IsIncreasing(t, received[1:])
+}
+
+func TestPriorityDelivery_SamePriority(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope,
+		WithPriorityDelivery(func(any) int { return 0 }),
+		WithPersistentDeliveryGoroutine())
+	defer unsub()
+
+	for i := 1; i <= 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	for i := 1; i <= 5; i++ {
+		assert.Equal(t, i, <-testingCh)
+	}
+}
+
+func TestPriorityDelivery_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope,
+		WithPriorityDelivery(func(any) int { return 0 }),
+		WithPersistentDeliveryGoroutine())
+	for i := 1; i <= 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	unsub()
+
+	for range testingCh {
+	}
+	assert.Eventually(t, func() bool { return testScope.Len() == 0 }, time.Second, time.Millisecond)
+}
@@ -4,8 +4,14 @@
 package pubsub
 
 import (
+	"container/heap"
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -24,47 +30,532 @@ func init() {
 // event scope. Multiple event scopes should only be used when you need to publish data with
 // the same type but different handlers.
 type EventScope struct {
-	subscribers *sync.Map
+	// subscribers holds the topics of the scope by key. It is set by topics, when the scope is
+	// created or, for a scope created with NewLazyEventScope, when it is first used.
+	subscribers atomic.Pointer[sync.Map]
+	initOnce    sync.Once
+
+	// seqMu serializes numbering messages with registering subscribers, so that every
+	// subscriber sees a gap-free suffix of the scope's messages in sequence order.
+	seqMu             sync.Mutex
+	seq               atomic.Int64
+	sequencingEnabled atomic.Bool
+
+	lamportEnabled atomic.Bool
+	lamport        atomic.Int64
+
+	clockMu sync.Mutex
+	id      string
+	vclock  VectorClock
+
+	codec       Codec
+	compression compression
+	signingKeys [][]byte
+	spill       *spillBuffer
+	serial      *serialQueue
+	replay      *replayLog
+	history     *historyLog
+	last        sync.Map // topic key -> *atomic.Value holding a lastValue
+	journal     atomic.Pointer[journalHook]
+	store       MessageStore
+	handoff     handoffRegistry
+
+	acl        accessList
+	rewriters  map[reflect.Type][]func(any) any
+	middleware middlewares
+	hooks      doHooks
+	rings      sync.Map // reflect.Type -> *ringSet
+
+	traceExtractor func(ctx context.Context) map[string]string
+	traceInjector  func(ctx context.Context, headers map[string]string) context.Context
+
+	duplicatePolicy DuplicatePolicy
+	duplicates      duplicateSubscriptions
+
+	// typeLocks holds the ordering lock of every type published if typeOrdering is set.
+	typeOrdering bool
+	typeLocks    sync.Map // topic key -> *sync.Mutex
+
+	highWaterMark  int
+	onBackpressure func(subscriberID uuid.UUID, pending int)
+	backlog        backlog
+
+	// forwarders counts the castAndForward goroutines of the scope that are still running.
+	forwarders backlog
+
+	published atomic.Int64
+	dropped   atomic.Int64
+
+	observers   observers
+	pool        atomic.Pointer[SharedPool]
+	inFlight    chan struct{} // slots of WithConcurrencyLimit, nil without a limit
+	channel     *deliveryChannel
+	copyOnWrite bool
+	clock       Clock
+	onError     func(err error)
+
+	interestMu       sync.Mutex
+	interest         map[string]int // type name -> topics with active subscribers
+	interestWatchers map[*func(string, bool)]struct{}
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	done      chan struct{} // closed by Close once closed is set
+	// finalizing is set by Finalize, after which publishes fail as if the scope were closed.
+	finalizing atomic.Bool
+	pluginsMu  sync.Mutex
+	plugins    []Plugin
+
+	// onClose holds the functions registered with OnClose until Close calls them and sets
+	// onCloseDone.
+	onCloseMu   sync.Mutex
+	onClose     []func()
+	onCloseDone bool
+}
+
+// topic holds the subscribers registered under a single key.
+type topic struct {
+	e    *EventScope
+	t    eventType
+	subs sync.Map // uuid.UUID -> *subscriber
+
+	// seq numbers the messages published to this topic, so that ordered subscribers can
+	// detect gaps.
+	seq atomic.Int64
+
+	// sorted holds every subscriber of the topic by decreasing priority once a subscriber
+	// created with WithPriority has been added, and is nil until then.
+	mu     sync.RWMutex
+	sorted []*subscriber
+
+	// snapshot holds every subscriber of the topic if the scope was created with
+	// WithCopyOnWrite. It is replaced, never modified, under mu.
+	snapshot atomic.Pointer[[]*subscriber]
+
+	// demands are notified whenever the topic gains its first subscriber or loses its last.
+	demands []*demand
+
+	// refs counts the subscribers of the topic. The topic is deleted from the scope once it
+	// has neither subscribers nor demands. refs is guarded by the scope's seqMu, which every
+	// lookup of a topic to register a subscriber or number a message holds as well.
+	refs int
+
+	// interested records whether the topic has subscribers that are not passive, as last
+	// reported to the scope's interest watchers.
+	interestMu sync.Mutex
+	interested bool
+}
+
+// subscriber is a single subscription registered on the event scope.
+type subscriber struct {
+	id uuid.UUID
+
+	// ch is never closed. Deliveries still in flight when the subscriber stops receiving give
+	// up once done is closed, so unsubscribing never races with a send on ch.
+	ch     chan message
+	cancel context.CancelFunc
+
+	// done is closed when the subscriber stops receiving, so that pending deliveries to it
+	// are abandoned instead of blocking forever.
+	done <-chan struct{}
+
+	// e and t are the scope and type the subscriber is registered for.
+	e *EventScope
+	t eventType
+
+	// all is set for subscribers to every type. ctx is the context they subscribed with, which
+	// carries the identity their access to each message is checked against.
+	all bool
+	ctx context.Context
+
+	// ordered subscribers receive messages in topicSeq order. Deliveries that are abandoned
+	// are reported to skipped so the gap they leave does not stall the subscriber.
+	ordered bool
+	skips   skipList
+
+	// forwarded is the topicSeq of the last message an ordered subscriber has forwarded or
+	// skipped. progress is signaled every time it advances.
+	forwarded atomic.Int64
+	progress  chan struct{}
+
+	// pending counts the messages handed to the subscriber that its receiver has not taken yet,
+	// including deliveries still waiting to be accepted by castAndForward. backlog is the
+	// scope wide count the messages are also counted in.
+	pending atomic.Int64
+	backlog *backlog
+
+	// priority orders the deliveries of a message between subscribers of a topic that has
+	// subscribers created with WithPriority.
+	priority int
+
+	// passive subscribers, such as the ones bridges forward values with, do not make the
+	// scope interested in their type.
+	passive bool
+
+	// compression, if set, compresses the messages an ordered subscriber holds back.
+	compression *compression
+
+	// persistent subscribers have a buffered ch, which publishes send to from the publishing
+	// goroutine.
+	persistent bool
+
+	// queue, if set, holds the messages of a subscriber created with WithPriorityDelivery,
+	// which are pushed to it instead of being sent on ch.
+	queue *priorityQueue
+}
+
+// message is the internal unit of delivery. It carries the published value along with
+// any metadata the scope attached to it at publish time.
+type message struct {
+	val any
+
+	// seq is assigned by the scope to every publish, regardless of type. It is strictly
+	// increasing in the order messages are published.
+	seq int64
+
+	// topicSeq numbers the messages of a topic without gaps, so that ordered subscribers can
+	// restore the order of concurrent deliveries. topicSeq and allSeq are assigned together
+	// with seq, for the topic of the message's type and the wildcard topic respectively, and
+	// are zero if the topic had no subscribers at the time. Each delivery carries the number
+	// of the topic it is made for in topicSeq.
+	topicSeq int64
+	allSeq   int64
+
+	// top and allTop are the topics the message was numbered in, and the ones it is delivered
+	// to, since a topic that loses its last subscriber is replaced by a new one with a
+	// numbering of its own.
+	top    *topic
+	allTop *topic
+
+	lamport int64
+	vclock  VectorClock
+
+	// identity is the identity of the publisher, and correlationID the ID set with
+	// WithCorrelationID, taken from the publish context.
+	identity      string
+	correlationID string
+
+	// trace holds the trace headers taken from the publish context by the scope's trace
+	// extractor, if it has one.
+	trace map[string]string
+
+	// typeName and publishedAt are the name of the type the message was published as and the
+	// time it was numbered at, and eventType the type itself. They are only set if the scope
+	// has subscribers to every type, which cannot tell the type from the value alone.
+	typeName    string
+	eventType   eventType
+	publishedAt time.Time
+
+	// origin identifies the bridge a message was received from, if any, so that it is not
+	// forwarded back to where it came from.
+	origin *Bridge
+
+	// stickyKey routes the message between the sticky subscribers of its type, if keyed is
+	// set. hashes are the positions of the message on the hash rings of its type, and routes
+	// the ring members it was routed to.
+	stickyKey string
+	keyed     bool
+	hashes    []ringHashing
+	routes    []ringRoute
+
+	// received, if not nil, is called once the receiver of the subscriber the message is
+	// delivered to has taken it, or the message has been abandoned or dropped on its way.
+	received func()
+
+	// acks counts the subscribers that received the message, if it was published with
+	// PublishAcked.
+	acks *ackCounter
 }
 
 // UnSubFn is a function which unsubscribes from the data type. Calling this will close the
 // channel returned by SubscribeTo/SubscribeToScope.
 type UnsubFn func()
 
-func NewEventScope() *EventScope {
-	return &EventScope{
-		subscribers: &sync.Map{},
+// NewEventScope creates an event scope configured by the provided options.
+func NewEventScope(opts ...EventScopeOption) *EventScope {
+	e := newEventScope(opts...)
+	e.topics()
+	return e
+}
+
+// newEventScope creates an event scope configured by opts whose topics are not initialized yet.
+func newEventScope(opts ...EventScopeOption) *EventScope {
+	e := &EventScope{
+		codec:       JSONCodec{},
+		compression: compression{minSize: DefaultCompressionMinSize},
+
+		highWaterMark: DefaultHighWaterMark,
+		clock:         systemClock{},
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
+	return e
 }
 
 // Publish will send the value val into the global event scope. If the context is canceled,
 // the value may not be sent to all subscribers.
-func Publish[T any](ctx context.Context, val T) {
-	PublishToScope(ctx, Global, val)
+func Publish[T any](ctx context.Context, val T) error {
+	return PublishToScope(ctx, Global, val)
 }
 
+// ErrNilScope is returned when a value is published on a nil event scope.
+var ErrNilScope = errors.New("pubsub: nil event scope")
+
 // PublishToScope will send the value val on the specified event scope. If the context is canceled,
-// the value may not be sent to all subscribers.
-func PublishToScope[T any](ctx context.Context, e *EventScope, val T) {
-	var zero T
-	subs, ok := e.subscribers.Load(zero)
-	if !ok {
+// the value may not be sent to all subscribers. ErrUnauthorized is returned if the scope's access
+// rules do not allow the identity attached to ctx to publish values of type T, ErrScopeClosed if
+// the scope has been closed, and ErrNilScope if e is nil.
+func PublishToScope[T any](ctx context.Context, e *EventScope, val T) error {
+	if e == nil {
+		return ErrNilScope
+	}
+	return e.publish(ctx, eventTypeOf[T](), e.newMessage(ctx, val))
+}
+
+// newMessage wraps val in a message stamped with the scope's clocks and the publisher's identity.
+func (e *EventScope) newMessage(ctx context.Context, val any) message {
+	msg := message{val: val}
+	msg.identity, _ = IdentityFromContext(ctx)
+	msg.correlationID, _ = CorrelationIDFromContext(ctx)
+	if e.lamportEnabled.Load() {
+		msg.lamport = e.lamport.Add(1)
+	}
+	msg.vclock = e.tickVectorClock()
+	return msg
+}
+
+// publish sends msg to the subscribers of its type. The type is also used to serialize the
+// message value if the scope has to do so on the way. Every publish goes through publish, which
+// returns ErrScopeClosed once the scope has been closed, ErrUnauthorized if the identity attached
+// to ctx may not publish the type, and the error of the scope's journal or message store if the
+// message cannot be logged. Messages dropped by the scope's middleware return nil.
+func (e *EventScope) publish(ctx context.Context, t eventType, msg message) error {
+	t.checkHashable()
+	if e.closed.Load() || e.finalizing.Load() {
+		return ErrScopeClosed
+	}
+	if !e.acl.allowPublish(ctx, t.name) {
+		return ErrUnauthorized
+	}
+	ctx, val, ok, err := e.interceptPublish(ctx, t, msg.val)
+	if err != nil || !ok {
+		return err
+	}
+	msg.val = e.rewrite(t, val)
+	msg.trace = e.extractTrace(ctx)
+	e.hashRings(t, &msg)
+	journal, record, err := e.journalOf(t, msg)
+	if err != nil {
+		return err
+	}
+
+	unlock := e.lockType(t)
+	e.seqMu.Lock()
+	// Close and Finalize set their flags under seqMu, so nothing is numbered after they have.
+	if e.closed.Load() || e.finalizing.Load() {
+		e.seqMu.Unlock()
+		unlock()
+		return ErrScopeClosed
+	}
+	msg.seq = e.seq.Add(1)
+	if journal != nil {
+		// Records are appended under seqMu so that the journal is in sequence order.
+		if err := journal.j.Append(msg.seq, t.name(), record); err != nil {
+			e.seqMu.Unlock()
+			unlock()
+			return err
+		}
+	}
+	if e.store != nil {
+		if err := e.store.Append(t.name(), msg.seq, record); err != nil {
+			e.seqMu.Unlock()
+			unlock()
+			return err
+		}
+	}
+	if v, ok := e.topics().Load(t.key); ok {
+		msg.top = v.(*topic)
+		msg.topicSeq = msg.top.seq.Add(1)
+	}
+	if v, ok := e.topics().Load(wildcardKey{}); ok {
+		msg.allTop = v.(*topic)
+		msg.allSeq = msg.allTop.seq.Add(1)
+		msg.typeName = t.name()
+		msg.eventType = t
+		msg.publishedAt = e.clock.Now()
+	}
+	if e.replay != nil {
+		e.replay.record(t, msg)
+	}
+	if e.history != nil {
+		e.history.record(t, msg)
+	}
+	e.recordLast(t, msg)
+	e.route(&msg)
+	if e.serial != nil {
+		e.serial.push(ctx, t, msg)
+	}
+	e.seqMu.Unlock()
+	e.published.Add(1)
+	e.recordGlobal(t, msg.val)
+	e.observePublished(t, msg)
+	e.runHooks(t, msg.val)
+
+	if e.serial != nil {
+		return nil
+	}
+	if e.spill != nil {
+		e.spill.publish(ctx, t, msg)
+		unlock()
+		return nil
+	}
+	e.deliver(ctx, t, msg, unlock)
+	return nil
+}
+
+// delivery is a message on its way to a single subscriber.
+type delivery struct {
+	sub *subscriber
+	msg message
+}
+
+// deliver sends msg to every subscriber of its type. If done is not nil, it is called once every
+// subscriber has either received the message, stopped receiving, or given up because ctx was
+// canceled.
+func (e *EventScope) deliver(ctx context.Context, t eventType, msg message, done func()) {
+	deliveries, prioritized := e.deliveriesOf(t, msg)
+	msg.acks.expect(len(deliveries))
+	if len(deliveries) == 0 {
+		if done != nil {
+			done()
+		}
 		return
 	}
 
-	subMap := subs.(*sync.Map)
-	subMap.Range(func(_, value any) bool {
-		go func() {
-			dest := value.(chan any)
-			select {
-			case dest <- val:
-			case <-ctx.Done():
+	for _, d := range deliveries {
+		e.enqueue(d.sub)
+	}
+	if prioritized {
+		e.deliverByPriority(ctx, deliveries, done)
+		return
+	}
+
+	remaining := atomic.Int64{}
+	remaining.Store(int64(len(deliveries)))
+	var queued []func()
+	for _, d := range deliveries {
+		d := d
+		send := func() {
+			defer func() {
+				if remaining.Add(-1) == 0 && done != nil {
+					done()
+				}
+			}()
+			e.send(ctx, d)
+		}
+		if d.sub.persistent {
+			queued = append(queued, send)
+			continue
+		}
+		e.spawn(ctx, send)
+	}
+	// Persistent subscribers are sent to from the publishing goroutine, after the others since
+	// their queues may be full.
+	for _, send := range queued {
+		send()
+	}
+}
+
+// deliveriesOf returns a delivery of msg for every subscriber it is for: the subscribers of its
+// topic and of the wildcard topic the identity of their context may receive it, and the ring
+// members it was routed to. It reports whether the topics have prioritized subscribers.
+func (e *EventScope) deliveriesOf(t eventType, msg message) ([]delivery, bool) {
+	var deliveries []delivery
+	prioritized := false
+	for _, top := range [...]*topic{msg.top, msg.allTop} {
+		if top == nil {
+			continue
+		}
+		tmsg := msg
+		if top == msg.allTop {
+			tmsg.topicSeq = msg.allSeq
+		}
+		prioritized = top.each(func(sub *subscriber) {
+			// Subscribers to a single type were checked when they subscribed, subscribers to
+			// every type are checked against each message instead.
+			if sub.all && !e.acl.allowSubscribe(sub.ctx, t.name) {
+				if sub.ordered {
+					sub.skips.add(tmsg.topicSeq)
+				}
 				return
 			}
+			deliveries = append(deliveries, delivery{sub: sub, msg: tmsg})
+		}) || prioritized
+	}
+	for _, r := range msg.routes {
+		tmsg := msg
+		tmsg.topicSeq = r.seq
+		deliveries = append(deliveries, delivery{sub: r.sub, msg: tmsg})
+	}
+	return deliveries, prioritized
+}
 
-		}()
+// send hands d to its subscriber, or abandons it once the subscriber stops receiving or ctx is
+// canceled, in which case the message is counted as dropped. It reports whether the subscriber
+// accepted the message.
+func (e *EventScope) send(ctx context.Context, d delivery) bool {
+	if d.sub.queue != nil {
+		return d.sub.queue.push(d.sub, d.msg)
+	}
+	select {
+	case d.sub.ch <- d.msg:
+		if d.sub.persistent {
+			// A subscriber that stopped receiving may not take the message out of its queue
+			// anymore.
+			select {
+			case <-d.sub.done:
+				d.sub.drain()
+			default:
+			}
+		}
 		return true
-	})
+	case <-d.sub.done:
+		d.sub.settle(d.msg)
+	case <-ctx.Done():
+		typeName := d.msg.typeName
+		if !d.sub.all {
+			typeName = d.sub.t.name()
+		}
+		e.drop(typeName, d.msg, d.sub.id)
+		if d.sub.ordered {
+			d.sub.skips.add(d.msg.topicSeq)
+		}
+		d.sub.settle(d.msg)
+	}
+	return false
+}
+
+// enqueue counts a message handed to sub, and reports the subscriber to the backpressure
+// callback when its backlog crosses the high water mark.
+func (e *EventScope) enqueue(sub *subscriber) {
+	e.backlog.add(1)
+	n := sub.pending.Add(1)
+	if e.onBackpressure != nil && n == int64(e.highWaterMark)+1 {
+		e.onBackpressure(sub.id, int(n))
+	}
+}
+
+// settle counts a message handed to the subscriber as no longer pending, once it has been
+// received or will never be.
+func (s *subscriber) settle(msg message) {
+	s.pending.Add(-1)
+	s.backlog.add(-1)
+	msg.acks.settle()
+	if msg.received != nil {
+		msg.received()
+	}
 }
 
 // SubscribeTo creates a channel to listen for events of type T. When listeners are finished
@@ -74,51 +565,248 @@ func SubscribeTo[T any](ctx context.Context) (chan T, UnsubFn) {
 }
 
 // SubscribeTo creates a channel to listen for events of type T published on the provided event scope.
-// When listeners are finished processing these events, the UnsubFn should be called.
-func SubscribeToScope[T any](ctx context.Context, e *EventScope) (chan T, UnsubFn) {
-	ch := make(chan T)
-	untypedCh := make(chan any)
-	id := uuid.New()
+// When listeners are finished processing these events, the UnsubFn should be called. If the scope's
+// access rules do not allow the identity attached to ctx to subscribe to T, if ctx is already
+// done, or if e is nil, the returned channel is already closed. A subscriber that is already
+// subscribed to T is handled according to the scope's WithDuplicateSubscribePolicy.
+func SubscribeToScope[T any](ctx context.Context, e *EventScope, opts ...SubscribeOption) (chan T, UnsubFn) {
+	if e != nil && e.duplicatePolicy != DuplicateAllow {
+		if key, ok := SubscriberKeyFromContext(ctx); ok {
+			return subscribeOnce[T](ctx, e, key, opts...)
+		}
+	}
+	return subscribe(ctx, e, func(val T, _ message) T { return val }, opts...)
+}
+
+// SubscribeCallback calls fn with every value of type T published on the event scope until the
+// returned UnsubFn is called. fn is called from a single goroutine, one value at a time, so a slow
+// callback delays the values after it. If the scope's access rules do not allow the identity
+// attached to ctx to subscribe to T, fn is never called.
+func SubscribeCallback[T any](ctx context.Context, e *EventScope, fn func(T), opts ...SubscribeOption) UnsubFn {
+	ch, unsub := SubscribeToScope[T](ctx, e, opts...)
+	go func() {
+		for val := range ch {
+			fn(val)
+		}
+	}()
+	return unsub
+}
+
+// subscribe registers a subscriber for type T on the event scope. Each message received is passed
+// through wrap before being sent on the returned channel.
+func subscribe[T, O any](ctx context.Context, e *EventScope, wrap func(T, message) O, opts ...SubscribeOption) (chan O, UnsubFn) {
+	return subscribeKey(ctx, e, eventTypeOf[T](), wrap, opts...)
+}
+
+// wildcardKey is the key that subscribers to every type are registered under.
+type wildcardKey struct{}
 
-	var zero T
+// subscribeAll registers a subscriber that receives every message published on the event scope,
+// regardless of its type.
+// Messages of types the identity attached to ctx may not subscribe to are left out.
+func subscribeAll(ctx context.Context, e *EventScope, opts ...SubscribeOption) (chan message, UnsubFn) {
+	all := eventType{key: wildcardKey{}}
+	return subscribeKey(ctx, e, all, func(_ any, msg message) message { return msg }, opts...)
+}
+
+// subscribeKey registers a subscriber to t. Every message delivered to it must hold a value of
+// type T. Every subscription goes through subscribeKey, which returns an already closed channel
+// if e is nil, ctx is already done, the identity attached to ctx may not subscribe to t or the
+// scope has been closed.
+func subscribeKey[T, O any](ctx context.Context, e *EventScope, t eventType, wrap func(T, message) O, opts ...SubscribeOption) (chan O, UnsubFn) {
+	var cfg subscribeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
-	// This line can panic if a non-hashable value is passed in
-	subs, _ := e.subscribers.LoadOrStore(zero, &sync.Map{})
-	subMap := subs.(*sync.Map)
+	t.checkHashable()
+	_, all := t.key.(wildcardKey)
+	if e == nil || ctx.Err() != nil || (!all && !e.acl.allowSubscribe(ctx, t.name)) {
+		ch := make(chan O)
+		close(ch)
+		return ch, func() {}
+	}
 
-	subMap.Store(id, untypedCh)
+	ch := make(chan O)
+	untypedCh := make(chan message)
+	if cfg.persistent {
+		untypedCh = make(chan message, e.highWaterMark)
+	}
+	id := cfg.id
+	if id == (uuid.UUID{}) {
+		id = uuid.New()
+	}
 
 	forwardCtx, cancel := context.WithCancel(ctx)
-	go castAndForward(forwardCtx, untypedCh, ch)
+	sub := &subscriber{
+		id:          id,
+		ch:          untypedCh,
+		cancel:      cancel,
+		done:        forwardCtx.Done(),
+		e:           e,
+		t:           t,
+		all:         all,
+		ctx:         ctx,
+		ordered:     cfg.ordered && cfg.priorityFn == nil,
+		priority:    cfg.priority,
+		passive:     cfg.passive,
+		compression: cfg.compression,
+		persistent:  cfg.persistent,
+		backlog:     &e.backlog,
+	}
+	if sub.ordered {
+		sub.skips.init()
+		sub.progress = make(chan struct{}, 1)
+	}
+	if cfg.priorityFn != nil {
+		sub.queue = newPriorityQueue(cfg.priorityFn)
+	}
+
+	// Registering under seqMu splits the messages of the topic in two: those numbered up to
+	// the topic's current sequence were published before the subscriber was registered, and
+	// every later one reaches it. An ordered subscriber starts expecting the one after it.
+	e.seqMu.Lock()
+	if e.closed.Load() {
+		e.seqMu.Unlock()
+		cancel()
+		close(ch)
+		return ch, func() {}
+	}
+	v, _ := e.topics().LoadOrStore(t.key, &topic{e: e, t: t})
+	top := v.(*topic)
+	top.refs++
+	top.add(sub, cfg.prioritized)
+	next := top.seq.Load() + 1
+	sub.forwarded.Store(next - 1)
+	if cfg.registered != nil {
+		cfg.registered(top, sub)
+	}
+	e.seqMu.Unlock()
+	top.changed()
+	e.observeAdded(t, id)
+
+	// The node to run on is the one of the goroutine subscribing, not of the forwarder.
+	var pin func()
+	if cfg.numaLocal {
+		pin = numaPinner()
+	}
+	e.forwarders.add(1)
+	go func() {
+		defer e.forwarders.add(-1)
+		if pin != nil {
+			pin()
+		}
+		castAndForward(forwardCtx, sub, next, ch, wrap)
+		// The forwarder also stops when ctx is canceled, in which case the subscriber has to
+		// leave the topic as if it had unsubscribed.
+		top.remove(id)
+	}()
 
 	unsub := func() {
-		subMap.Delete(id)
+		top.remove(id)
 		cancel()
 	}
 
 	return ch, unsub
 }
 
-func castAndForward[T any](ctx context.Context, in <-chan any, out chan<- T) {
+func castAndForward[T, O any](ctx context.Context, sub *subscriber, next int64, out chan<- O, wrap func(T, message) O) {
 	defer close(out)
 
+	send := func(msg message) bool {
+		msg, err := release[T](sub, msg)
+		if err != nil {
+			sub.e.reportError(fmt.Errorf("pubsub: decoding held %s: %w", typeName[T](), err))
+			sub.settle(msg)
+			return true
+		}
+		msg, ok := sub.e.interceptReceive(sub.e.injectTrace(sub.ctx, msg), sub, msg)
+		if !ok {
+			// The middleware dropped the value, the subscriber goes on with the next one.
+			sub.settle(msg)
+			return true
+		}
+		// A nil value was published as the zero value of an interface type T, which the
+		// assertion does not accept.
+		typedVal, ok := msg.val.(T)
+		if !ok && msg.val != nil {
+			panic("mismatched type")
+		}
+		select {
+		case out <- wrap(typedVal, msg):
+			msg.acks.receive()
+			sub.settle(msg)
+			return true
+		case <-ctx.Done():
+			sub.settle(msg)
+			return false
+		}
+	}
+
+	advance := func() {
+		next++
+		sub.forwarded.Store(next - 1)
+		select {
+		case sub.progress <- struct{}{}:
+		default:
+		}
+	}
+
+	if sub.queue != nil {
+		sub.queue.forward(ctx, sub, send)
+		return
+	}
+	if sub.persistent {
+		defer sub.drain()
+	}
+	var pending messageHeap
+	defer func() {
+		// Messages that were still waiting for their turn are never going to be received.
+		for _, msg := range pending {
+			sub.settle(msg)
+		}
+	}()
 	for {
+		var skipped <-chan struct{}
+		if sub.ordered {
+			skipped = sub.skips.notify
+		}
+
 		select {
 		case <-ctx.Done():
 			return
-		case val, ok := <-in:
+		case <-skipped:
+		case msg, ok := <-sub.ch:
 			if !ok {
 				return
 			}
-			typedVal, ok := val.(T)
-			if !ok {
-				panic("mismatched type")
+			if !sub.ordered {
+				if !send(msg) {
+					return
+				}
+				continue
 			}
-			select {
-			case out <- typedVal:
-			case <-ctx.Done():
+			if msg.topicSeq < next {
+				// The message was published before the subscriber was registered.
+				sub.settle(msg)
+				continue
+			}
+			heap.Push(&pending, sub.hold(msg))
+		}
+
+		// Forward every message that is now in sequence, stepping over abandoned deliveries.
+		for {
+			if sub.skips.take(next) {
+				advance()
+				continue
+			}
+			if pending.Len() == 0 || pending[0].topicSeq != next {
+				break
+			}
+			if !send(heap.Pop(&pending).(message)) {
 				return
 			}
+			advance()
 		}
 	}
 }
@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// Operations driven by FuzzPublishSubscribe, one per input byte.
+const (
+	opSubscribe                = iota // subscribe to int
+	opSubscribeCancelable             // subscribe to string, ordered, ended by canceling its context
+	opPublishInt                      // publish an int
+	opPublishString                   // publish a string
+	opUnsubscribe                     // end the oldest subscription still running
+	opUnsubscribeDuringPublish        // end the newest subscription while publishing to it
+	opPublishUnhashable               // publish a slice, which is expected to panic
+	opPublishUnsubscribed             // publish a type nobody subscribes to
+	numOps
+)
+
+type fuzzEvent struct{}
+
+// maxFuzzOps bounds the number of operations run for a single input.
+const maxFuzzOps = 64
+
+func FuzzPublishSubscribe(f *testing.F) {
+	f.Add([]byte{opSubscribe, opPublishInt, opUnsubscribe})
+	f.Add([]byte{opPublishInt, opPublishString, opPublishUnsubscribed})
+	f.Add([]byte{opSubscribe, opSubscribe, opPublishInt, opPublishInt, opUnsubscribe, opPublishInt})
+	f.Add([]byte{opSubscribeCancelable, opPublishString, opPublishString, opUnsubscribe, opPublishString})
+	f.Add([]byte{opSubscribe, opUnsubscribeDuringPublish, opSubscribeCancelable, opUnsubscribeDuringPublish})
+	f.Add([]byte{opPublishUnhashable, opSubscribe, opPublishUnhashable, opPublishInt})
+	f.Add([]byte{opUnsubscribe, opUnsubscribeDuringPublish})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+		if len(ops) > maxFuzzOps {
+			ops = ops[:maxFuzzOps]
+		}
+
+		ctx := context.Background()
+		testScope := NewEventScope()
+		var readers sync.WaitGroup
+		var unsubs []UnsubFn
+		read := func(recv func() bool) {
+			readers.Add(1)
+			go func() {
+				defer readers.Done()
+				for recv() {
+				}
+			}()
+		}
+
+		for i, op := range ops {
+			switch op % numOps {
+			case opSubscribe:
+				ch, unsub := SubscribeToScope[int](ctx, testScope)
+				unsubs = append(unsubs, unsub)
+				read(func() bool { _, ok := <-ch; return ok })
+			case opSubscribeCancelable:
+				subCtx, cancel := context.WithCancel(ctx)
+				ch, _ := SubscribeToScope[string](subCtx, testScope, WithOrdered())
+				unsubs = append(unsubs, UnsubFn(cancel))
+				read(func() bool { _, ok := <-ch; return ok })
+			case opPublishInt:
+				PublishToScope(ctx, testScope, i)
+			case opPublishString:
+				PublishToScope(ctx, testScope, fmt.Sprint(i))
+			case opUnsubscribe:
+				if len(unsubs) > 0 {
+					unsubs[0]()
+					unsubs = unsubs[1:]
+				}
+			case opUnsubscribeDuringPublish:
+				if len(unsubs) == 0 {
+					continue
+				}
+				unsub := unsubs[len(unsubs)-1]
+				unsubs = unsubs[:len(unsubs)-1]
+
+				var wg sync.WaitGroup
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					PublishToScope(ctx, testScope, i)
+					PublishToScope(ctx, testScope, fmt.Sprint(i))
+				}()
+				go func() {
+					defer wg.Done()
+					unsub()
+				}()
+				wg.Wait()
+			case opPublishUnhashable:
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil || !strings.Contains(fmt.Sprint(r), "unhashable") {
+							t.Fatalf("publishing a slice: got panic %v, want a panic about an unhashable type", r)
+						}
+					}()
+					PublishToScope(ctx, testScope, []int{i})
+				}()
+			case opPublishUnsubscribed:
+				PublishToScope(ctx, testScope, fuzzEvent{})
+			}
+		}
+
+		for _, unsub := range unsubs {
+			unsub()
+		}
+		readers.Wait()
+
+		waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		if err := testScope.WaitForQuiescence(waitCtx); err != nil {
+			t.Fatalf("%d forwarders still running after every subscription ended", testScope.ActiveGoroutines())
+		}
+	})
+}
@@ -2,11 +2,19 @@ package pubsub
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
 func TestPubSub(t *testing.T) {
 	ctx := context.Background()
 	testScope := NewEventScope()
@@ -62,38 +70,29 @@ func TestPubsub_Struct(t *testing.T) {
 	assert.Equal(t, val, incVal)
 }
 
+// The types that can be subscribed to satisfy Hashable.
+var (
+	_ Hashable[int]
+	_ Hashable[*int]
+	_ Hashable[struct{ foo int }]
+	_ Hashable[chan any]
+	_ Hashable[error]
+)
+
 func TestPubSub_SlicePanics(t *testing.T) {
 	ctx := context.Background()
 	testScope := NewEventScope()
 
-	success := false
-	defer func() {
-		recover()
-		if success {
-			t.FailNow()
-		}
-	}()
-
-	SubscribeToScope[[]bool](ctx, testScope)
-
-	success = true
+	// var _ Hashable[[]bool] would not compile.
+	assert.Panics(t, func() { SubscribeToScope[[]bool](ctx, testScope) })
 }
 
 func TestPubSub_MapPanics(t *testing.T) {
 	ctx := context.Background()
 	testScope := NewEventScope()
 
-	success := false
-	defer func() {
-		recover()
-		if success {
-			t.FailNow()
-		}
-	}()
-
-	SubscribeToScope[map[any]any](ctx, testScope)
-
-	success = true
+	// var _ Hashable[map[any]any] would not compile.
+	assert.Panics(t, func() { SubscribeToScope[map[any]any](ctx, testScope) })
 }
 
 func TestPubSub_Chan(t *testing.T) {
@@ -117,17 +116,8 @@ func TestPubSub_FnPanics(t *testing.T) {
 	ctx := context.Background()
 	testScope := NewEventScope()
 
-	success := false
-	defer func() {
-		recover()
-		if success {
-			t.FailNow()
-		}
-	}()
-
-	SubscribeToScope[func()](ctx, testScope)
-
-	success = true
+	// var _ Hashable[func()] would not compile.
+	assert.Panics(t, func() { SubscribeToScope[func()](ctx, testScope) })
 }
 
 type testInterface interface {
@@ -154,6 +144,28 @@ func TestPubSub_Intf(t *testing.T) {
 	assert.Equal(t, val, incVal)
 }
 
+func TestPubSub_NilIntf(t *testing.T) {
+	for name, opts := range map[string][]SubscribeOption{
+		"Unordered":  nil,
+		"Ordered":    {WithOrdered()},
+		"Persistent": {WithPersistentDeliveryGoroutine()},
+		"Priority":   {WithPriorityDelivery(func(any) int { return 0 })},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			testScope := NewEventScope()
+
+			testingCh, unsub := SubscribeToScope[testInterface](ctx, testScope, opts...)
+			defer unsub()
+
+			require.NoError(t, PublishToScope[testInterface](ctx, testScope, nil))
+			incVal, ok := <-testingCh
+			assert.True(t, ok)
+			assert.Nil(t, incVal)
+		})
+	}
+}
+
 func TestPubSub_Unsub(t *testing.T) {
 	ctx := context.Background()
 	testScope := NewEventScope()
@@ -185,3 +197,156 @@ func TestPubSub_CtxCancelled(t *testing.T) {
 	_, ok := <-testingCh
 	assert.False(t, ok)
 }
+
+func TestPubSub_CtxCancelledBeforeSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testScope := NewEventScope()
+
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+	assert.Zero(t, testScope.Stats().Subscribers)
+}
+
+func TestPubSub_CtxCancelledUnsubscribes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testScope := NewEventScope()
+
+	testingCh, _ := SubscribeToScope[int](ctx, testScope)
+	assert.Equal(t, 1, testScope.Stats().Subscribers)
+	cancel()
+
+	_, ok := <-testingCh
+	assert.False(t, ok)
+	assert.NoError(t, testScope.WaitForQuiescence(context.Background()))
+	assert.Zero(t, testScope.Stats().Subscribers)
+}
+
+func TestCopyOnWrite(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithCopyOnWrite())
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope)
+	defer unsubSecond()
+
+	PublishToScope(ctx, testScope, 1)
+	assert.Equal(t, 1, <-first)
+	assert.Equal(t, 1, <-second)
+
+	unsubFirst()
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.Equal(t, 1, testScope.Stats().Subscribers)
+
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 2, <-second)
+}
+
+func TestCopyOnWrite_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithCopyOnWrite())
+
+	ch, unsub := SubscribeToScope[int](ctx, testScope, WithOrdered())
+	defer unsub()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, unsub := SubscribeToScope[int](ctx, testScope)
+				unsub()
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, i, <-ch)
+	}
+	wg.Wait()
+}
+
+func TestPubSub_UnsubscribeDuringPublish(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		ch, unsub := SubscribeToScope[int](ctx, testScope)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for range ch {
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				PublishToScope(ctx, testScope, n)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			unsub()
+		}()
+	}
+	wg.Wait()
+
+	// Every delivery in flight when its subscriber left has been abandoned.
+	assert.Eventually(t, func() bool { return testScope.Len() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestPubSub_UnsubscribeDeletesTopic(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	topics := func() int {
+		n := 0
+		testScope.knownTopics().Range(func(_, _ any) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	for i := 0; i < 100; i++ {
+		_, unsubInts := SubscribeToScope[int](ctx, testScope)
+		_, unsubStrs := SubscribeToScope[string](ctx, testScope, WithOrdered())
+		PublishToScope(ctx, testScope, "dropped")
+		unsubInts()
+		unsubStrs()
+	}
+	assert.Zero(t, topics())
+
+	// A topic that is created again numbers its messages from the start, and ordered subscribers
+	// are not held back by what was numbered before.
+	ch, unsub := SubscribeToScope[string](ctx, testScope, WithOrdered())
+	defer unsub()
+	assert.Equal(t, 1, topics())
+	PublishToScope(ctx, testScope, "a")
+	PublishToScope(ctx, testScope, "b")
+	assert.Equal(t, "a", <-ch)
+	assert.Equal(t, "b", <-ch)
+}
+
+func TestPubSub_NilScope(t *testing.T) {
+	ctx := context.Background()
+
+	assert.ErrorIs(t, PublishToScope(ctx, nil, 1), ErrNilScope)
+	assert.ErrorIs(t, PublishSticky(ctx, nil, 1, "key"), ErrNilScope)
+
+	testingCh, unsub := SubscribeToScope[int](ctx, nil)
+	defer unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+
+	sticky, unsubSticky := SubscribeSticky(ctx, nil, func(int) string { return "" })
+	defer unsubSticky()
+	_, ok = <-sticky
+	assert.False(t, ok)
+}
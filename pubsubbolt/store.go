@@ -0,0 +1,89 @@
+// Package pubsubbolt provides a pubsub.MessageStore backed by a bbolt database.
+package pubsubbolt
+
+import (
+	"encoding/binary"
+
+	"github.com/WillYingling/pubsub"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is a pubsub.MessageStore that keeps each type in a bucket of a bbolt database, keyed by
+// sequence number.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ pubsub.MessageStore = (*Store)(nil)
+
+// Open opens the database at path, creating it if it does not exist.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o644, nil)
+	if err != nil {
+		return nil, err
+	}
+	return New(db), nil
+}
+
+// New returns a store kept in db. Its buckets are named after the types they hold, so db
+// should not hold other buckets with the same names.
+func New(db *bolt.DB) *Store {
+	return &Store{db: db}
+}
+
+// key encodes seq so that keys sort in sequence order. Sequence numbers are never negative.
+func key(seq int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(seq))
+}
+
+func (s *Store) Append(typeName string, seqNum int64, data []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(typeName))
+		if err != nil {
+			return err
+		}
+		return b.Put(key(seqNum), data)
+	})
+}
+
+func (s *Store) ReadFrom(typeName string, fromSeq int64) ([]pubsub.StoredMessage, error) {
+	var msgs []pubsub.StoredMessage
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(typeName))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		for k, v := c.Seek(key(max(fromSeq, 0))); k != nil; k, v = c.Next() {
+			// Values are only valid for the life of the transaction.
+			msgs = append(msgs, pubsub.StoredMessage{
+				Seq:  int64(binary.BigEndian.Uint64(k)),
+				Data: append([]byte(nil), v...),
+			})
+		}
+		return nil
+	})
+	return msgs, err
+}
+
+func (s *Store) Trim(typeName string, beforeSeq int64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(typeName))
+		if b == nil {
+			return nil
+		}
+		// Deleting moves the cursor, so it goes back to the first key after every deletion.
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && int64(binary.BigEndian.Uint64(k)) < beforeSeq; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
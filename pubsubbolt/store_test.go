@@ -0,0 +1,59 @@
+package pubsubbolt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	store, err := Open(path)
+	require.NoError(t, err)
+
+	for seq := int64(1); seq <= 5; seq++ {
+		require.NoError(t, store.Append("int", seq, []byte{byte(seq)}))
+	}
+	msgs, err := store.ReadFrom("int", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []pubsub.StoredMessage{{Seq: 4, Data: []byte{4}}, {Seq: 5, Data: []byte{5}}}, msgs)
+
+	require.NoError(t, store.Trim("int", 3))
+	require.NoError(t, store.Trim("unknown", 3))
+	msgs, err = store.ReadFrom("int", 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, int64(3), msgs[0].Seq)
+	require.NoError(t, store.Close())
+
+	// The values survive reopening the database.
+	store, err = Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+	msgs, err = store.ReadFrom("int", 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 3)
+}
+
+func TestStore_Scope(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	scope := pubsub.NewEventScope(pubsub.WithStore(store))
+
+	require.NoError(t, pubsub.PublishToScope(ctx, scope, "first"))
+	require.NoError(t, pubsub.PublishToScope(ctx, scope, "second"))
+	ch, unsub := pubsub.SubscribeFromWatermark[string](ctx, scope, 1)
+	defer unsub()
+	assert.Equal(t, "second", <-ch)
+}
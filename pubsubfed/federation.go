@@ -0,0 +1,162 @@
+package pubsubfed
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/WillYingling/pubsub"
+)
+
+// Federation is a pubsub.Bridge that negotiates which types are exchanged. Types still have to
+// be registered on the bridge with pubsub.BridgeType, on both sides, but values of a registered
+// type are only forwarded while the remote scope has subscribers for it.
+type Federation struct {
+	scope     *pubsub.EventScope
+	transport pubsub.Transport
+	bridge    *pubsub.Bridge
+
+	mu     sync.Mutex
+	remote map[string]bool
+
+	// local holds the changes to the local interest that have not been sent yet, by type name.
+	local   map[string]bool
+	changed chan struct{}
+}
+
+// New creates a federation between scope and the remote side of transport. The remote side must
+// also use a Federation. opts configure the underlying bridge. Call Run to start the federation.
+func New(scope *pubsub.EventScope, transport pubsub.Transport, opts ...pubsub.BridgeOption) *Federation {
+	f := &Federation{
+		scope:     scope,
+		transport: transport,
+		remote:    make(map[string]bool),
+		local:     make(map[string]bool),
+		changed:   make(chan struct{}, 1),
+	}
+	opts = append(opts, pubsub.WithBridgeFilter(f.Interested))
+	f.bridge = pubsub.NewBridge(scope, interestTransport{f}, opts...)
+	return f
+}
+
+// Bridge returns the bridge types are registered on with pubsub.BridgeType.
+func (f *Federation) Bridge() *pubsub.Bridge {
+	return f.bridge
+}
+
+// Interested reports whether the remote side has announced subscribers for the type.
+func (f *Federation) Interested(typeName string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.remote[typeName]
+}
+
+// Run announces the types the local scope is interested in, keeps the remote side updated, and
+// receives messages from the remote side until ctx is canceled or the transport fails.
+func (f *Federation) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := f.scope.WatchInterest(func(typeName string, interested bool) {
+		f.mu.Lock()
+		f.local[typeName] = interested
+		f.mu.Unlock()
+
+		select {
+		case f.changed <- struct{}{}:
+		default:
+		}
+	})
+	defer stop()
+
+	// Whichever of the two fails first stops the other.
+	errs := make(chan error, 2)
+	go func() { errs <- f.announce(ctx) }()
+	go func() { errs <- f.bridge.Run(ctx) }()
+
+	err := <-errs
+	cancel()
+	<-errs
+	return err
+}
+
+// announce sends a snapshot of the local interest, followed by an update every time it changes.
+func (f *Federation) announce(ctx context.Context) error {
+	snapshot := true
+	for {
+		f.mu.Lock()
+		update := InterestUpdate{Snapshot: snapshot}
+		for name, interested := range f.local {
+			if interested {
+				update.Add = append(update.Add, name)
+			} else if !snapshot {
+				update.Remove = append(update.Remove, name)
+			}
+		}
+		f.local = make(map[string]bool)
+		f.mu.Unlock()
+
+		if snapshot || len(update.Add) > 0 || len(update.Remove) > 0 {
+			sort.Strings(update.Add)
+			sort.Strings(update.Remove)
+			data, err := update.Marshal()
+			if err != nil {
+				return err
+			}
+			if err := f.transport.Send(ctx, TypeInterestProtocol, data); err != nil {
+				return err
+			}
+		}
+		snapshot = false
+
+		select {
+		case <-f.changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// apply records an update received from the remote side.
+func (f *Federation) apply(u InterestUpdate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if u.Snapshot {
+		f.remote = make(map[string]bool)
+	}
+	for _, name := range u.Add {
+		f.remote[name] = true
+	}
+	for _, name := range u.Remove {
+		delete(f.remote, name)
+	}
+}
+
+// interestTransport is the transport of the federation's bridge. It takes the messages of the
+// interest protocol out of the stream of received messages.
+type interestTransport struct {
+	f *Federation
+}
+
+func (t interestTransport) Send(ctx context.Context, typeName string, data []byte) error {
+	return t.f.transport.Send(ctx, typeName, data)
+}
+
+func (t interestTransport) Receive(ctx context.Context) (string, []byte, error) {
+	for {
+		typeName, data, err := t.f.transport.Receive(ctx)
+		if err != nil || typeName != TypeInterestProtocol {
+			return typeName, data, err
+		}
+		// A malformed update is ignored, the next snapshot or update corrects it.
+		if u, err := ParseInterestUpdate(data); err == nil {
+			t.f.apply(u)
+		}
+	}
+}
+
+func (t interestTransport) Close() error {
+	return t.f.transport.Close()
+}
@@ -0,0 +1,146 @@
+package pubsubfed
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type frame struct {
+	name string
+	data []byte
+}
+
+// pipeTransport is an in-memory pubsub.Transport that counts the frames it sends per type.
+type pipeTransport struct {
+	in   <-chan frame
+	out  chan<- frame
+	sent atomic.Int64
+}
+
+func newPipe() (*pipeTransport, *pipeTransport) {
+	a := make(chan frame, 16)
+	b := make(chan frame, 16)
+	return &pipeTransport{in: a, out: b}, &pipeTransport{in: b, out: a}
+}
+
+func (p *pipeTransport) Send(ctx context.Context, name string, data []byte) error {
+	if name != TypeInterestProtocol {
+		p.sent.Add(1)
+	}
+	select {
+	case p.out <- frame{name: name, data: data}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pipeTransport) Receive(ctx context.Context) (string, []byte, error) {
+	select {
+	case f := <-p.in:
+		return f.name, f.data, nil
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
+
+func (p *pipeTransport) Close() error {
+	return nil
+}
+
+type fedEvent struct {
+	Msg string
+}
+
+func TestFederation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	local, remote := pubsub.NewEventScope(), pubsub.NewEventScope()
+	localPipe, remotePipe := newPipe()
+	localFed, remoteFed := New(local, localPipe), New(remote, remotePipe)
+	defer pubsub.BridgeType[fedEvent](ctx, localFed.Bridge())()
+	defer pubsub.BridgeType[fedEvent](ctx, remoteFed.Bridge())()
+
+	errs := make(chan error, 2)
+	go func() { errs <- localFed.Run(ctx) }()
+	go func() { errs <- remoteFed.Run(ctx) }()
+
+	// Nobody on the remote side subscribes to fedEvent, so nothing is sent.
+	pubsub.PublishToScope(ctx, local, fedEvent{Msg: "dropped"})
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, localPipe.sent.Load())
+	assert.False(t, localFed.Interested("pubsubfed.fedEvent"))
+
+	events, unsub := pubsub.SubscribeToScope[fedEvent](ctx, remote)
+	require.Eventually(t, func() bool {
+		return localFed.Interested("pubsubfed.fedEvent")
+	}, time.Second, time.Millisecond)
+
+	pubsub.PublishToScope(ctx, local, fedEvent{Msg: "hello"})
+	assert.Equal(t, fedEvent{Msg: "hello"}, <-events)
+	assert.Equal(t, int64(1), localPipe.sent.Load())
+
+	// The remote side's own bridge subscription does not make it interested, so the value is
+	// not sent back.
+	assert.False(t, remoteFed.Interested("pubsubfed.fedEvent"))
+	assert.Zero(t, remotePipe.sent.Load())
+
+	unsub()
+	require.Eventually(t, func() bool {
+		return !localFed.Interested("pubsubfed.fedEvent")
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	for i := 0; i < 2; i++ {
+		assert.True(t, errors.Is(<-errs, context.Canceled))
+	}
+}
+
+func TestFederation_SnapshotOnStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	local, remote := pubsub.NewEventScope(), pubsub.NewEventScope()
+	events, unsub := pubsub.SubscribeToScope[fedEvent](ctx, remote)
+	defer unsub()
+
+	localPipe, remotePipe := newPipe()
+	localFed, remoteFed := New(local, localPipe), New(remote, remotePipe)
+	defer pubsub.BridgeType[fedEvent](ctx, localFed.Bridge())()
+	defer pubsub.BridgeType[fedEvent](ctx, remoteFed.Bridge())()
+	go localFed.Run(ctx)
+	go remoteFed.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return localFed.Interested("pubsubfed.fedEvent")
+	}, time.Second, time.Millisecond)
+	pubsub.PublishToScope(ctx, local, fedEvent{Msg: "hello"})
+	assert.Equal(t, fedEvent{Msg: "hello"}, <-events)
+}
+
+func TestInterestUpdate(t *testing.T) {
+	u := InterestUpdate{Snapshot: true, Add: []string{"a", "b"}}
+	data, err := u.Marshal()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"snapshot":true,"add":["a","b"]}`, string(data))
+
+	parsed, err := ParseInterestUpdate(data)
+	require.NoError(t, err)
+	assert.Equal(t, u, parsed)
+
+	_, err = ParseInterestUpdate([]byte("{"))
+	assert.ErrorIs(t, err, errMalformedUpdate)
+}
@@ -0,0 +1,46 @@
+// Package pubsubfed federates event scopes so that only the events the remote side is interested
+// in cross the connection. When a Federation starts, each side sends the other the names of the
+// types it has subscribers for, and keeps it updated as subscribers come and go. A side only
+// forwards values of the types the other side has announced.
+package pubsubfed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// TypeInterestProtocol is the type name interest messages are sent under on the transport. It
+// also identifies the version of the message format, which is InterestUpdate encoded as JSON.
+//
+// The first message each side sends is a snapshot listing every type it is interested in. Every
+// later message lists the types it became interested in and the types it lost interest in since
+// the previous message.
+const TypeInterestProtocol = "pubsubfed.TypeInterest/1"
+
+var errMalformedUpdate = errors.New("pubsubfed: malformed interest update")
+
+// InterestUpdate is a message of the type interest protocol.
+type InterestUpdate struct {
+	// Snapshot is set if Add lists every type the sender is interested in, replacing what
+	// the receiver knew about the sender.
+	Snapshot bool `json:"snapshot,omitempty"`
+
+	// Add and Remove list the names of the types the sender gained and lost interest in.
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Marshal encodes the update for the transport.
+func (u InterestUpdate) Marshal() ([]byte, error) {
+	return json.Marshal(u)
+}
+
+// ParseInterestUpdate decodes an update received from the transport.
+func ParseInterestUpdate(data []byte) (InterestUpdate, error) {
+	var u InterestUpdate
+	if err := json.Unmarshal(data, &u); err != nil {
+		return InterestUpdate{}, fmt.Errorf("%w: %w", errMalformedUpdate, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,45 @@
+package pubsubquic
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/quic-go/quic-go"
+)
+
+// Listener accepts QUIC connections from remote transports.
+type Listener struct {
+	ln *quic.Listener
+}
+
+// Listen listens for QUIC connections on addr. The TLS configuration must contain a
+// certificate, and conf may be nil to use the quic-go defaults.
+func Listen(addr string, tlsConf *tls.Config, conf *quic.Config) (*Listener, error) {
+	ln, err := quic.ListenAddr(addr, withALPN(tlsConf), conf)
+	if err != nil {
+		return nil, err
+	}
+	return &Listener{ln: ln}, nil
+}
+
+// Accept waits for the next remote transport to connect.
+func (l *Listener) Accept(ctx context.Context) (*Transport, error) {
+	conn, err := l.ln.Accept(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t := newTransport(nil)
+	t.setConn(conn)
+	return t, nil
+}
+
+// Addr returns the address the listener is bound to.
+func (l *Listener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Close stops accepting connections. Transports that have already been accepted are not closed.
+func (l *Listener) Close() error {
+	return l.ln.Close()
+}
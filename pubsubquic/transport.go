@@ -0,0 +1,300 @@
+// Package pubsubquic provides a pubsub.Transport backed by QUIC. Every event type is sent on
+// its own unidirectional QUIC stream, so a slow or lost message of one type never delays
+// messages of another type.
+package pubsubquic
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/quic-go/quic-go"
+)
+
+// ALPN is the application protocol negotiated by the transport. It is added to the NextProtos
+// of the provided TLS configuration if it is not already present.
+const ALPN = "pubsub"
+
+// maxFrameSize bounds the size of a single message so that a corrupt length prefix cannot cause
+// an unbounded allocation.
+const maxFrameSize = 64 << 20
+
+var (
+	// ErrClosed is returned by Send and Receive once the transport has been closed.
+	ErrClosed = errors.New("pubsubquic: transport closed")
+
+	errFrameTooLarge = errors.New("pubsubquic: frame too large")
+)
+
+var _ pubsub.Transport = (*Transport)(nil)
+
+type frame struct {
+	typeName string
+	data     []byte
+}
+
+// Transport is a pubsub.Transport over a single QUIC connection. Transports created by Dial
+// reconnect automatically when the connection is lost. Transports returned by Listener.Accept
+// cannot reconnect; the remote side is expected to dial again.
+type Transport struct {
+	dial func(ctx context.Context) (quic.Connection, error)
+
+	mu      sync.Mutex
+	conn    quic.Connection
+	streams map[string]quic.SendStream
+	closed  bool
+
+	incoming chan frame
+	done     chan struct{}
+}
+
+// Dial connects to the transport listening on addr. The TLS configuration must be suitable for
+// a QUIC client, and conf may be nil to use the quic-go defaults.
+func Dial(ctx context.Context, addr string, tlsConf *tls.Config, conf *quic.Config) (*Transport, error) {
+	tlsConf = withALPN(tlsConf)
+	t := newTransport(func(ctx context.Context) (quic.Connection, error) {
+		return quic.DialAddr(ctx, addr, tlsConf, conf)
+	})
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.setConn(conn)
+	return t, nil
+}
+
+func newTransport(dial func(ctx context.Context) (quic.Connection, error)) *Transport {
+	return &Transport{
+		dial:     dial,
+		streams:  make(map[string]quic.SendStream),
+		incoming: make(chan frame),
+		done:     make(chan struct{}),
+	}
+}
+
+func withALPN(tlsConf *tls.Config) *tls.Config {
+	tlsConf = tlsConf.Clone()
+	for _, proto := range tlsConf.NextProtos {
+		if proto == ALPN {
+			return tlsConf
+		}
+	}
+	tlsConf.NextProtos = append(tlsConf.NextProtos, ALPN)
+	return tlsConf
+}
+
+func (t *Transport) setConn(conn quic.Connection) {
+	t.mu.Lock()
+	t.conn = conn
+	t.streams = make(map[string]quic.SendStream)
+	t.mu.Unlock()
+
+	go t.acceptStreams(conn)
+}
+
+// Send writes data to the stream dedicated to typeName, opening the stream if needed. If the
+// stream has been reset, a new one is opened and the write is retried once. For dialed
+// transports the connection is re-established if it has been lost.
+func (t *Transport) Send(ctx context.Context, typeName string, data []byte) error {
+	err := t.send(ctx, typeName, data)
+	if err == nil || errors.Is(err, ErrClosed) {
+		return err
+	}
+
+	if err := t.reconnect(ctx); err != nil {
+		return err
+	}
+	return t.send(ctx, typeName, data)
+}
+
+func (t *Transport) send(ctx context.Context, typeName string, data []byte) error {
+	stream, err := t.stream(ctx, typeName)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFrame(stream, data); err != nil {
+		t.mu.Lock()
+		if t.streams[typeName] == stream {
+			delete(t.streams, typeName)
+		}
+		t.mu.Unlock()
+		stream.CancelWrite(0)
+		return err
+	}
+	return nil
+}
+
+// stream returns the send stream for typeName, opening it and writing the stream header if it
+// does not exist yet. The stream is opened without holding the lock, since opening can block
+// until the peer allows more streams.
+func (t *Transport) stream(ctx context.Context, typeName string) (quic.SendStream, error) {
+	for {
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if stream, ok := t.streams[typeName]; ok {
+			t.mu.Unlock()
+			return stream, nil
+		}
+		conn := t.conn
+		t.mu.Unlock()
+
+		stream, err := conn.OpenUniStreamSync(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFrame(stream, []byte(typeName)); err != nil {
+			stream.CancelWrite(0)
+			return nil, err
+		}
+
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			stream.CancelWrite(0)
+			return nil, ErrClosed
+		}
+		if _, ok := t.streams[typeName]; ok || t.conn != conn {
+			// Another sender opened a stream for the type first, or the connection was
+			// replaced while ours was being opened. Use whatever is current instead.
+			t.mu.Unlock()
+			stream.CancelWrite(0)
+			continue
+		}
+		t.streams[typeName] = stream
+		t.mu.Unlock()
+		return stream, nil
+	}
+}
+
+// reconnect replaces the current connection if it has been closed. Streams that were merely
+// reset are reopened by the next send without a reconnect.
+func (t *Transport) reconnect(ctx context.Context) error {
+	t.mu.Lock()
+	conn := t.conn
+	closed := t.closed
+	t.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+	if conn.Context().Err() == nil {
+		return nil
+	}
+	if t.dial == nil {
+		return fmt.Errorf("pubsubquic: connection lost: %w", context.Cause(conn.Context()))
+	}
+
+	newConn, err := t.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		newConn.CloseWithError(0, "")
+		return ErrClosed
+	}
+	if t.conn != conn {
+		// Another sender reconnected first.
+		t.mu.Unlock()
+		newConn.CloseWithError(0, "")
+		return nil
+	}
+	t.mu.Unlock()
+	t.setConn(newConn)
+	return nil
+}
+
+// Receive returns the next message received on any stream of the connection.
+func (t *Transport) Receive(ctx context.Context) (string, []byte, error) {
+	select {
+	case f := <-t.incoming:
+		return f.typeName, f.data, nil
+	case <-t.done:
+		return "", nil, ErrClosed
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
+
+// Close closes the underlying connection.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	return t.conn.CloseWithError(0, "")
+}
+
+func (t *Transport) acceptStreams(conn quic.Connection) {
+	for {
+		stream, err := conn.AcceptUniStream(conn.Context())
+		if err != nil {
+			return
+		}
+		go t.readStream(stream)
+	}
+}
+
+func (t *Transport) readStream(stream quic.ReceiveStream) {
+	r := bufio.NewReader(stream)
+	name, err := readFrame(r)
+	if err != nil {
+		stream.CancelRead(0)
+		return
+	}
+
+	for {
+		data, err := readFrame(r)
+		if err != nil {
+			stream.CancelRead(0)
+			return
+		}
+		select {
+		case t.incoming <- frame{typeName: string(name), data: data}:
+		case <-t.done:
+			stream.CancelRead(0)
+			return
+		}
+	}
+}
+
+// writeFrame writes data prefixed with its length as a uvarint.
+func writeFrame(w io.Writer, data []byte) error {
+	buf := make([]byte, binary.MaxVarintLen64+len(data))
+	n := binary.PutUvarint(buf, uint64(len(data)))
+	n += copy(buf[n:], data)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxFrameSize {
+		return nil, errFrameTooLarge
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
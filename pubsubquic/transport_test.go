@@ -0,0 +1,212 @@
+package pubsubquic
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func testTLSConfigs(t *testing.T) (server, client *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	server = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	client = &tls.Config{
+		RootCAs:    pool,
+		ServerName: "localhost",
+	}
+	return server, client
+}
+
+func testTransports(t *testing.T) (server, client *Transport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverTLS, clientTLS := testTLSConfigs(t)
+	ln, err := Listen("127.0.0.1:0", serverTLS, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	accepted := make(chan *Transport, 1)
+	go func() {
+		tr, err := ln.Accept(ctx)
+		if err == nil {
+			accepted <- tr
+		}
+		close(accepted)
+	}()
+
+	client, err = Dial(ctx, ln.Addr().String(), clientTLS, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	// The server only learns about the connection once the client opens a stream.
+	require.NoError(t, client.Send(ctx, "hello", []byte("hi")))
+	server, ok := <-accepted
+	require.True(t, ok)
+	t.Cleanup(func() { server.Close() })
+
+	name, data, err := server.Receive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+	assert.Equal(t, []byte("hi"), data)
+
+	return server, client
+}
+
+func TestTransport_SendReceive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, client := testTransports(t)
+
+	require.NoError(t, client.Send(ctx, "a", []byte("1")))
+	require.NoError(t, client.Send(ctx, "b", []byte("2")))
+	require.NoError(t, server.Send(ctx, "c", []byte("3")))
+
+	received := map[string]string{}
+	for i := 0; i < 2; i++ {
+		name, data, err := server.Receive(ctx)
+		require.NoError(t, err)
+		received[name] = string(data)
+	}
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, received)
+
+	name, data, err := client.Receive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "c", name)
+	assert.Equal(t, []byte("3"), data)
+}
+
+func TestTransport_StreamReset(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, client := testTransports(t)
+
+	require.NoError(t, client.Send(ctx, "a", []byte("1")))
+	_, _, err := server.Receive(ctx)
+	require.NoError(t, err)
+
+	client.mu.Lock()
+	client.streams["a"].CancelWrite(0)
+	client.mu.Unlock()
+
+	// The first write after a reset may still succeed locally, so keep sending until a new
+	// stream delivers the message.
+	assert.Eventually(t, func() bool {
+		client.Send(ctx, "a", []byte("2"))
+		recvCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, data, err := server.Receive(recvCtx)
+		return err == nil && string(data) == "2"
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestTransport_Closed(t *testing.T) {
+	ctx := context.Background()
+	_, client := testTransports(t)
+
+	require.NoError(t, client.Close())
+	assert.ErrorIs(t, client.Send(ctx, "a", nil), ErrClosed)
+	_, _, err := client.Receive(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+type quicEvent struct {
+	Msg string
+}
+
+func TestTransport_Bridge(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, client := testTransports(t)
+
+	local := pubsub.NewEventScope()
+	remote := pubsub.NewEventScope()
+
+	localBridge := pubsub.NewBridge(local, client)
+	defer pubsub.BridgeType[quicEvent](ctx, localBridge)()
+	remoteBridge := pubsub.NewBridge(remote, server)
+	defer pubsub.BridgeType[quicEvent](ctx, remoteBridge)()
+	go remoteBridge.Run(ctx)
+
+	testingCh, unsub := pubsub.SubscribeToScope[quicEvent](ctx, remote)
+	defer unsub()
+
+	pubsub.PublishToScope(ctx, local, quicEvent{Msg: "over quic"})
+
+	select {
+	case val := <-testingCh:
+		assert.Equal(t, "over quic", val.Msg)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for bridged event")
+	}
+}
+
+func TestTransport_Reconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverTLS, clientTLS := testTLSConfigs(t)
+	ln, err := Listen("127.0.0.1:0", serverTLS, nil)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	client, err := Dial(ctx, ln.Addr().String(), clientTLS, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(ctx, "a", []byte("1")))
+	server, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	_, _, err = server.Receive(ctx)
+	require.NoError(t, err)
+
+	// Closing the accepted side loses the connection. The next send dials again and is
+	// received by a newly accepted transport.
+	require.NoError(t, server.Close())
+	client.mu.Lock()
+	lost := client.conn.Context()
+	client.mu.Unlock()
+	<-lost.Done()
+
+	require.NoError(t, client.Send(ctx, "a", []byte("2")))
+	reconnected, err := ln.Accept(ctx)
+	require.NoError(t, err)
+	defer reconnected.Close()
+
+	name, data, err := reconnected.Receive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", name)
+	assert.Equal(t, []byte("2"), data)
+}
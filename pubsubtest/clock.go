@@ -0,0 +1,146 @@
+// Package pubsubtest provides helpers for testing code built on pubsub, such as a clock that is
+// advanced by hand so that time-based behavior can be tested without sleeping.
+package pubsubtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WillYingling/pubsub"
+)
+
+// TestClock is a pubsub.Clock whose time only moves when Advance is called. Pass it to an event
+// scope with pubsub.WithClock.
+type TestClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending After or AfterFunc, or a running ticker.
+type waiter struct {
+	at     time.Time
+	period time.Duration // zero for After and AfterFunc
+	c      chan time.Time
+	fn     func() // set for AfterFunc, instead of c
+}
+
+var _ pubsub.Clock = (*TestClock)(nil)
+
+// NewTestClock returns a clock set to an arbitrary fixed time.
+func NewTestClock() *TestClock {
+	return &TestClock{now: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// Now returns the clock's current time.
+func (c *TestClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock has been advanced by d.
+func (c *TestClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &waiter{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- c.now
+		return w.c
+	}
+	c.waiters = append(c.waiters, w)
+	return w.c
+}
+
+// AfterFunc calls f once the clock has been advanced by d, from the goroutine calling Advance.
+// If d is not positive, f is called right away on its own goroutine, like time.AfterFunc does.
+// stop cancels the call and reports whether f had not been called yet.
+func (c *TestClock) AfterFunc(d time.Duration, f func()) (stop func() bool) {
+	if d <= 0 {
+		go f()
+		return func() bool { return false }
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &waiter{at: c.now.Add(d), fn: f}
+	c.waiters = append(c.waiters, w)
+	return func() bool { return c.remove(w) }
+}
+
+// NewTicker returns a ticker that ticks every time the clock is advanced past a multiple of d.
+// Like time.Ticker, it drops ticks its receiver is not ready for. d must be positive.
+func (c *TestClock) NewTicker(d time.Duration) *pubsub.Ticker {
+	if d <= 0 {
+		panic("pubsubtest: non-positive interval for NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &waiter{at: c.now.Add(d), period: d, c: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return pubsub.NewTicker(w.c, func() { c.remove(w) })
+}
+
+// remove forgets w, and reports whether it was still pending.
+func (c *TestClock) remove(w *waiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the number of calls to After and AfterFunc that have not fired, and have not
+// been stopped, plus the number of running tickers. It lets tests check that the code they test
+// stops the timers it no longer needs.
+func (c *TestClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// Advance moves the clock forward by d. Every After, AfterFunc and ticker that comes due on the
+// way fires, in the order of their deadlines, with the clock set to the deadline as it fires.
+func (c *TestClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for {
+		sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+		if len(c.waiters) == 0 || c.waiters[0].at.After(end) {
+			break
+		}
+
+		w := c.waiters[0]
+		c.now = w.at
+		if w.fn != nil {
+			// f may use the clock, so it is called without holding the lock.
+			c.waiters = c.waiters[1:]
+			c.mu.Unlock()
+			w.fn()
+			c.mu.Lock()
+			continue
+		}
+		select {
+		case w.c <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			c.waiters = c.waiters[1:]
+		}
+	}
+	c.now = end
+}
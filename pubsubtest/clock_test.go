@@ -0,0 +1,131 @@
+package pubsubtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestTestClock_After(t *testing.T) {
+	clock := NewTestClock()
+	start := clock.Now()
+	c := clock.After(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-c:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-c)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+}
+
+func TestTestClock_AfterFunc(t *testing.T) {
+	clock := NewTestClock()
+	start := clock.Now()
+	var fired []time.Time
+	stop := clock.AfterFunc(time.Minute, func() { fired = append(fired, clock.Now()) })
+	stopped := clock.AfterFunc(time.Minute, func() { t.Fatal("stopped AfterFunc fired") })
+	assert.Equal(t, 2, clock.Pending())
+
+	assert.True(t, stopped())
+	assert.False(t, stopped())
+	clock.Advance(59 * time.Second)
+	assert.Empty(t, fired)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, []time.Time{start.Add(time.Minute)}, fired)
+	assert.False(t, stop())
+	assert.Zero(t, clock.Pending())
+
+	done := make(chan struct{})
+	clock.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestTestClock_Ticker(t *testing.T) {
+	clock := NewTestClock()
+	start := clock.Now()
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-ticker.C)
+
+	// Ticks the receiver is not ready for are dropped.
+	clock.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C)
+	select {
+	case <-ticker.C:
+		t.Fatal("dropped tick was delivered")
+	default:
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Second)
+	select {
+	case <-ticker.C:
+		t.Fatal("stopped ticker ticked")
+	default:
+	}
+}
+
+func TestTestClock_PartitionedScope(t *testing.T) {
+	clock := NewTestClock()
+	src := pubsub.NewEventScope(pubsub.WithClock(clock))
+	p := pubsub.NewPartitionedScope(src, func(s string) string { return s }, pubsub.WithPartitionIdleTTL(time.Minute))
+	defer p.Close()
+
+	require.NoError(t, pubsub.PublishToScope(context.Background(), src, "a"))
+	require.Eventually(t, func() bool { return p.Len() == 1 }, time.Second, time.Millisecond)
+
+	// The partition is not idle for long enough yet.
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, 1, p.Len())
+
+	assert.Eventually(t, func() bool {
+		clock.Advance(time.Minute)
+		return p.Len() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestTestClock_SubscribeUntil(t *testing.T) {
+	ctx := context.Background()
+	clock := NewTestClock()
+	scope := pubsub.NewEventScope(pubsub.WithClock(clock))
+
+	ch, unsub := pubsub.SubscribeUntil[int](ctx, scope, clock.Now().Add(time.Hour))
+	defer unsub()
+	clock.Advance(59 * time.Minute)
+	require.NoError(t, pubsub.PublishToScope(ctx, scope, 1))
+	assert.Equal(t, 1, <-ch)
+	clock.Advance(time.Minute)
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Zero(t, clock.Pending())
+
+	// Ending the subscription early stops the timer.
+	_, unsub = pubsub.SubscribeUntil[int](ctx, scope, clock.Now().Add(time.Hour))
+	assert.Equal(t, 1, clock.Pending())
+	unsub()
+	assert.Zero(t, clock.Pending())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	ch, unsub = pubsub.SubscribeUntil[int](cancelCtx, scope, clock.Now().Add(time.Hour))
+	defer unsub()
+	cancel()
+	for range ch {
+	}
+	assert.Eventually(t, func() bool { return clock.Pending() == 0 }, time.Second, time.Millisecond)
+}
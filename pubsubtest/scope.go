@@ -0,0 +1,27 @@
+package pubsubtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WillYingling/pubsub"
+)
+
+// WithScopeContext returns a context that is canceled when the test ends, along with a fresh
+// event scope created with opts that is closed once it has been. Every subscription made with the
+// context or on the scope ends with the test, closing its channel, so tests do not need to
+// unsubscribe and cannot leak subscribers into the tests that run after them.
+func WithScopeContext(t testing.TB, opts ...pubsub.EventScopeOption) (context.Context, *pubsub.EventScope) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scope := pubsub.NewEventScope(opts...)
+	// Cleanups run last to first: the scope is closed after the context is canceled.
+	t.Cleanup(func() {
+		if err := scope.Close(); err != nil {
+			t.Errorf("closing event scope: %v", err)
+		}
+	})
+	t.Cleanup(cancel)
+	return ctx, scope
+}
@@ -0,0 +1,34 @@
+package pubsubtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WillYingling/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithScopeContext(t *testing.T) {
+	var ctx context.Context
+	var scope *pubsub.EventScope
+	var ints chan int
+	var global chan string
+
+	t.Run("subscribe", func(t *testing.T) {
+		ctx, scope = WithScopeContext(t)
+		ints, _ = pubsub.SubscribeToScope[int](ctx, scope)
+		global, _ = pubsub.SubscribeTo[string](ctx)
+
+		require.NoError(t, pubsub.PublishToScope(ctx, scope, 1))
+		assert.Equal(t, 1, <-ints)
+	})
+
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	_, ok := <-ints
+	assert.False(t, ok)
+	_, ok = <-global
+	assert.False(t, ok)
+	assert.ErrorIs(t, pubsub.PublishToScope(context.Background(), scope, 2), pubsub.ErrScopeClosed)
+	assert.NoError(t, scope.WaitForQuiescence(context.Background()))
+}
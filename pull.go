@@ -0,0 +1,121 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// PullScope is an event scope whose values are pulled by consumers when they are ready for them,
+// rather than pushed to subscribers as they are published. Every value published on its event
+// scope is buffered, per type, until it is pulled with Pull or PullN, so consumers control the
+// rate at which they take values. Each value is pulled by a single consumer.
+//
+// The buffers are not bounded: values of types nobody pulls are kept until the scope is closed.
+type PullScope struct {
+	scope *EventScope
+
+	mu     sync.Mutex
+	queues map[any][]any // type key -> values in publish order
+	closed bool
+
+	// wake is closed, and replaced, whenever a value is buffered or the scope is closed.
+	wake chan struct{}
+}
+
+// NewPullScope creates a PullScope on a new event scope created with opts. It buffers values until
+// it is closed with Close.
+func NewPullScope(opts ...EventScopeOption) *PullScope {
+	s := &PullScope{
+		scope:  NewEventScope(opts...),
+		queues: make(map[any][]any),
+		wake:   make(chan struct{}),
+	}
+	ch, _ := subscribeAll(context.Background(), s.scope, WithOrdered())
+	go func() {
+		for msg := range ch {
+			s.mu.Lock()
+			// Values are buffered by type key, as topics are, since distinct types can share a
+			// name.
+			key := msg.eventType.key
+			s.queues[key] = append(s.queues[key], msg.val)
+			s.wakeLocked()
+			s.mu.Unlock()
+		}
+		s.mu.Lock()
+		s.closed = true
+		s.wakeLocked()
+		s.mu.Unlock()
+	}()
+	return s
+}
+
+// Scope returns the event scope values are published on, with PublishToScope. It can also be
+// subscribed to as usual, in which case subscribers receive the values as they are published in
+// addition to them being buffered.
+func (s *PullScope) Scope() *EventScope {
+	return s.scope
+}
+
+// Close closes the underlying event scope, see EventScope.Close. Values that are still buffered
+// can be pulled after Close, after which Pull and PullN return ErrScopeClosed.
+func (s *PullScope) Close() error {
+	return s.scope.Close()
+}
+
+func (s *PullScope) wakeLocked() {
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
+
+// Pull returns the oldest buffered value of type T, waiting for one to be published if there is
+// none. It returns ctx.Err() if ctx is done first, and ErrScopeClosed once the scope has been
+// closed and no value of T is left.
+func Pull[T any](ctx context.Context, s *PullScope) (T, error) {
+	vals, err := PullN[T](ctx, s, 1)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return vals[0], nil
+}
+
+// PullN returns up to n of the oldest buffered values of type T, in publish order. It waits for
+// at least one value to be published if there is none, but not for more than those buffered. It
+// returns ctx.Err() if ctx is done first, and ErrScopeClosed once the scope has been closed and
+// no value of T is left.
+func PullN[T any](ctx context.Context, s *PullScope, n int) ([]T, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	key := eventTypeOf[T]().key
+	for {
+		s.mu.Lock()
+		if queue := s.queues[key]; len(queue) > 0 {
+			n = min(n, len(queue))
+			vals := make([]T, n)
+			for i, val := range queue[:n] {
+				// val is nil for the zero value of an interface type.
+				vals[i], _ = val.(T)
+				queue[i] = nil
+			}
+			if n == len(queue) {
+				delete(s.queues, key)
+			} else {
+				s.queues[key] = queue[n:]
+			}
+			s.mu.Unlock()
+			return vals, nil
+		}
+		closed, wake := s.closed, s.wake
+		s.mu.Unlock()
+
+		if closed {
+			return nil, ErrScopeClosed
+		}
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPull(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewPullScope()
+	defer testScope.Close()
+
+	PublishToScope(ctx, testScope.Scope(), 1)
+	PublishToScope(ctx, testScope.Scope(), "other type")
+	PublishToScope(ctx, testScope.Scope(), 2)
+
+	val, err := Pull[int](ctx, testScope)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	val, err = Pull[int](ctx, testScope)
+	require.NoError(t, err)
+	assert.Equal(t, 2, val)
+
+	str, err := Pull[string](ctx, testScope)
+	require.NoError(t, err)
+	assert.Equal(t, "other type", str)
+}
+
+func TestPull_Waits(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewPullScope()
+	defer testScope.Close()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		PublishToScope(ctx, testScope.Scope(), 1)
+	}()
+	val, err := Pull[int](ctx, testScope)
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = Pull[int](timeoutCtx, testScope)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPullN(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewPullScope()
+
+	for i := 1; i <= 3; i++ {
+		PublishToScope(ctx, testScope.Scope(), i)
+	}
+	assert.Eventually(t, func() bool {
+		testScope.mu.Lock()
+		defer testScope.mu.Unlock()
+		return len(testScope.queues[eventTypeOf[int]().key]) == 3
+	}, time.Second, time.Millisecond)
+
+	vals, err := PullN[int](ctx, testScope, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, vals)
+
+	require.NoError(t, testScope.Close())
+	vals, err = PullN[int](ctx, testScope, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, vals)
+
+	_, err = PullN[int](ctx, testScope, 2)
+	assert.ErrorIs(t, err, ErrScopeClosed)
+}
+
+// pulledDup is printed as pubsub.pulledDup, like the type of the same name declared in
+// TestPullN_SameTypeName, which refers to it as pulledDupInt.
+type pulledDup int
+
+type pulledDupInt = pulledDup
+
+func TestPullN_SameTypeName(t *testing.T) {
+	type pulledDup string
+	// With the values of both types in a single buffer, the second pull would wait forever.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s := NewPullScope()
+	defer s.Close()
+	require.Equal(t, typeName[pulledDup](), typeOf[pulledDupInt]().String())
+
+	require.NoError(t, PublishToScope(ctx, s.Scope(), pulledDupInt(1)))
+	require.NoError(t, PublishToScope(ctx, s.Scope(), pulledDup("one")))
+
+	strs, err := PullN[pulledDup](ctx, s, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []pulledDup{"one"}, strs)
+	ints, err := PullN[pulledDupInt](ctx, s, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []pulledDupInt{1}, ints)
+}
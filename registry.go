@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrUnknownType is returned by TypeRegistry for a name no type is registered under.
+	ErrUnknownType = errors.New("pubsub: unknown type name")
+
+	// ErrTypeMismatch is returned by TypeRegistry.Publish for a value that does not have the
+	// type registered under the name it is published with.
+	ErrTypeMismatch = errors.New("pubsub: value does not match registered type")
+)
+
+// TypeRegistry maps names to types so that values can be published and subscribed to by the
+// name of their type, for code that only learns the types it handles at run time, such as from
+// configuration. The values are published and received as if PublishToScope and
+// SubscribeToScope had been instantiated with the registered types, so they reach and come from
+// typed publishers and subscribers as well. A TypeRegistry is safe for concurrent use.
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// NewTypeRegistry creates an empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{types: make(map[string]reflect.Type)}
+}
+
+// RegisterType registers T under name. Go methods cannot have type parameters, so it is a
+// function rather than a method of TypeRegistry. Registering T under a name again has no effect,
+// and registering another type under a name that is taken panics, as does registering a type
+// that cannot be used as a topic key, see Hashable.
+func RegisterType[T any](r *TypeRegistry, name string) {
+	t := eventTypeOf[T]()
+	t.checkHashable()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.types[name]; ok && existing != t.typ {
+		panic(fmt.Sprintf("pubsub: type name %q registered for both %s and %s", name, existing, t.typ))
+	}
+	r.types[name] = t.typ
+}
+
+// lookup returns the type registered under name.
+func (r *TypeRegistry) lookup(name string) (eventType, error) {
+	r.mu.RLock()
+	typ, ok := r.types[name]
+	r.mu.RUnlock()
+	if !ok {
+		return eventType{}, fmt.Errorf("%w: %q", ErrUnknownType, name)
+	}
+	return eventTypeFor(typ), nil
+}
+
+// Publish publishes val on the event scope as the type registered under typeName. It returns
+// ErrUnknownType if no type is registered under typeName, ErrTypeMismatch if val cannot be
+// published as that type, such as nil for a type that is not an interface type, and otherwise the
+// errors of PublishToScope.
+func (r *TypeRegistry) Publish(ctx context.Context, scope *EventScope, typeName string, val any) error {
+	t, err := r.lookup(typeName)
+	if err != nil {
+		return err
+	}
+	if !holds(t, val) {
+		return fmt.Errorf("%w: %T published as %s", ErrTypeMismatch, val, t.name())
+	}
+	if scope == nil {
+		return ErrNilScope
+	}
+	return scope.publish(ctx, t, scope.newMessage(ctx, val))
+}
+
+// Subscribe subscribes to the type registered under typeName on the event scope, like
+// SubscribeToScope. The values are received as any, holding values of the registered type. It
+// returns ErrUnknownType if no type is registered under typeName.
+func (r *TypeRegistry) Subscribe(ctx context.Context, scope *EventScope, typeName string, opts ...SubscribeOption) (chan any, UnsubFn, error) {
+	t, err := r.lookup(typeName)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, unsub := subscribeKey(ctx, scope, t, func(val any, _ message) any { return val }, opts...)
+	return ch, unsub, nil
+}
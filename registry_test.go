@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredOrder struct{ ID int }
+
+func TestTypeRegistry(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	registry := NewTypeRegistry()
+	RegisterType[registeredOrder](registry, "order.placed")
+	RegisterType[error](registry, "error")
+
+	dynamicCh, unsub, err := registry.Subscribe(ctx, testScope, "order.placed")
+	require.NoError(t, err)
+	defer unsub()
+	typedCh, unsubTyped := SubscribeToScope[registeredOrder](ctx, testScope)
+	defer unsubTyped()
+
+	require.NoError(t, registry.Publish(ctx, testScope, "order.placed", registeredOrder{ID: 1}))
+	assert.Equal(t, registeredOrder{ID: 1}, <-dynamicCh)
+	assert.Equal(t, registeredOrder{ID: 1}, <-typedCh)
+
+	require.NoError(t, PublishToScope(ctx, testScope, registeredOrder{ID: 2}))
+	assert.Equal(t, registeredOrder{ID: 2}, <-dynamicCh)
+	<-typedCh
+
+	// Interface types accept any value implementing them.
+	errCh, unsubErrs := SubscribeToErrors(ctx, testScope)
+	defer unsubErrs()
+	require.NoError(t, registry.Publish(ctx, testScope, "error", lookupError{Key: "order"}))
+	assert.Equal(t, lookupError{Key: "order"}, <-errCh)
+	require.NoError(t, registry.Publish(ctx, testScope, "error", nil))
+	assert.Nil(t, <-errCh)
+}
+
+func TestTypeRegistry_Errors(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	registry := NewTypeRegistry()
+	RegisterType[registeredOrder](registry, "order.placed")
+	RegisterType[registeredOrder](registry, "order.placed")
+	RegisterType[int](registry, "int")
+
+	ints, unsub := SubscribeToScope[int](ctx, testScope)
+	defer unsub()
+	sub := NewSubscription[int](ctx, testScope)
+	defer sub.Unsubscribe()
+
+	assert.ErrorIs(t, registry.Publish(ctx, testScope, "order.shipped", registeredOrder{}), ErrUnknownType)
+	assert.ErrorIs(t, registry.Publish(ctx, testScope, "order.placed", 1), ErrTypeMismatch)
+	assert.ErrorIs(t, registry.Publish(ctx, testScope, "order.placed", nil), ErrTypeMismatch)
+	assert.ErrorIs(t, registry.Publish(ctx, testScope, "int", nil), ErrTypeMismatch)
+	assert.Zero(t, testScope.Stats().Published)
+	require.NoError(t, registry.Publish(ctx, testScope, "int", 1))
+	assert.Equal(t, 1, <-ints)
+	assert.Equal(t, 1, <-sub.C)
+	assert.ErrorIs(t, registry.Publish(ctx, nil, "order.placed", registeredOrder{}), ErrNilScope)
+
+	_, _, err := registry.Subscribe(ctx, testScope, "order.shipped")
+	assert.ErrorIs(t, err, ErrUnknownType)
+
+	assert.Panics(t, func() { RegisterType[string](registry, "order.placed") })
+	assert.Panics(t, func() { RegisterType[[]int](registry, "ints") })
+}
@@ -0,0 +1,183 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// WithReplay makes the event scope retain published messages so that subscribers created with
+// SubscribeFromWatermark can replay the events they missed. At most limit messages are retained
+// per type, oldest first; a limit of 0 retains every message.
+func WithReplay(limit int) EventScopeOption {
+	return func(e *EventScope) {
+		e.replay = &replayLog{
+			limit: limit,
+			byKey: make(map[any]*replayedType),
+		}
+	}
+}
+
+type replayLog struct {
+	limit int
+
+	mu    sync.RWMutex
+	byKey map[any]*replayedType
+}
+
+// replayedType holds the retained messages of a single type, oldest first.
+type replayedType struct {
+	t    eventType
+	msgs []message
+}
+
+func (r *replayLog) record(t eventType, msg message) {
+	// Replayed messages are not delivered through their topics, which retaining them would
+	// keep alive after they have been deleted, and replays are not acknowledged.
+	msg.top, msg.allTop, msg.acks = nil, nil, nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt, ok := r.byKey[t.key]
+	if !ok {
+		rt = &replayedType{t: t}
+		r.byKey[t.key] = rt
+	}
+	rt.msgs = append(rt.msgs, msg)
+	if r.limit > 0 && len(rt.msgs) > r.limit {
+		rt.msgs = rt.msgs[len(rt.msgs)-r.limit:]
+	}
+}
+
+// since returns the retained messages for key with a sequence number greater than seq.
+func (r *replayLog) since(key any, seq int64) []message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rt, ok := r.byKey[key]
+	if !ok {
+		return nil
+	}
+	return after(rt.msgs, seq)
+}
+
+// sinceAll returns the retained messages of every type allowed by allow with a sequence number
+// greater than seq, in sequence order.
+func (r *replayLog) sinceAll(seq int64, allow func(eventType) bool) []message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []message
+	for _, rt := range r.byKey {
+		if allow(rt.t) {
+			out = append(out, after(rt.msgs, seq)...)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].seq < out[j].seq })
+	return out
+}
+
+// after returns the messages of msgs with a sequence number greater than seq.
+func after(msgs []message, seq int64) []message {
+	var out []message
+	for _, msg := range msgs {
+		if msg.seq > seq {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// SubscribeFromWatermark subscribes to T on the event scope and first replays every retained
+// event of type T with a sequence number greater than watermark, followed by live events. The
+// scope must have been created with WithStore or WithReplay for events published before the
+// subscription to be replayed; otherwise only live events newer than the watermark are delivered.
+// The replay and the live events meet without a gap or an overlap, even while other goroutines
+// publish: the live subscriber is registered and the point the replay ends at is taken in a single
+// step that no publish is numbered during.
+//
+// The watermark is the value returned by Subscription.Watermark on an earlier subscription, which
+// lets a subscriber pick up where it left off. Use NewSubscriptionFromWatermark to keep tracking
+// the watermark of the new subscription.
+func SubscribeFromWatermark[T any](ctx context.Context, e *EventScope, watermark int64) (chan T, UnsubFn) {
+	sub := NewSubscriptionFromWatermark[T](ctx, e, watermark)
+	return sub.C, sub.Unsubscribe
+}
+
+// NewSubscriptionFromWatermark is like SubscribeFromWatermark but returns a Subscription. If
+// the identity attached to ctx may not subscribe to T, the subscription is already closed and
+// nothing is replayed. If the scope's message store cannot be read, the error is reported to the
+// scope's error handler and the subscription is closed, rather than skipping events.
+func NewSubscriptionFromWatermark[T any](ctx context.Context, e *EventScope, watermark int64) *Subscription[T] {
+	return subscribeFromWatermark[T](ctx, e, watermark, uuid.New())
+}
+
+// subscribeFromWatermark creates the subscription of NewSubscriptionFromWatermark with the given
+// ID, and tracks it for SerializeSubscriberState.
+func subscribeFromWatermark[T any](ctx context.Context, e *EventScope, watermark int64, id uuid.UUID) *Subscription[T] {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The retained messages are collected while the live subscriber is registered, so that
+	// every message ends up either in the replay or in the live stream, never in both or
+	// neither. Stored messages are only read once the subscriber is registered, so the cutoff
+	// between the two is taken instead.
+	var replayed []message
+	var store MessageStore
+	var cutoff int64
+	snapshot := func(c *subscribeConfig) {
+		c.registered = func(*topic, *subscriber) {
+			switch {
+			case e.store != nil:
+				store, cutoff = e.store, e.seq.Load()
+			case e.replay != nil:
+				replayed = e.replay.since(eventTypeOf[T]().key, watermark)
+			}
+		}
+	}
+	live, unsub := subscribe(ctx, e, func(_ T, msg message) message { return msg }, WithOrdered(), withSubscriberID(id), snapshot)
+	sub := newSubscription[T](watermark, nil)
+	sub.id = id
+	sub.attach(ctx, cancel, e, unsub)
+	trackSubscription(e, sub)
+
+	if store != nil {
+		var err error
+		replayed, err = storedMessages[T](e, store, watermark+1, cutoff)
+		if err != nil {
+			e.reportError(err)
+			sub.Unsubscribe()
+		}
+	} else {
+		cutoff = watermark
+		if len(replayed) > 0 {
+			cutoff = replayed[len(replayed)-1].seq
+		}
+	}
+	go sub.forward(ctx, replayed, live, cutoff)
+	return sub
+}
+
+// storedMessages reads the values of type T in store numbered from from to to, and decodes them.
+func storedMessages[T any](e *EventScope, store MessageStore, from, to int64) ([]message, error) {
+	t := eventTypeOf[T]()
+	stored, err := store.ReadFrom(t.name(), from)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []message
+	for _, s := range stored {
+		if s.Seq > to {
+			break
+		}
+		val, err := e.unmarshal(t.name(), s.Data, t.decode)
+		if err != nil {
+			return nil, fmt.Errorf("pubsub: decoding stored message %d: %w", s.Seq, err)
+		}
+		msgs = append(msgs, message{val: val, seq: s.Seq})
+	}
+	return msgs, nil
+}
@@ -0,0 +1,172 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeFromWatermark(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithReplay(0))
+
+	first := NewSubscription[int](ctx, testScope)
+	for i := 1; i <= 3; i++ {
+		PublishToScope(ctx, testScope, i)
+		assert.NotZero(t, <-first.C)
+	}
+	watermark := first.Watermark()
+	first.Unsubscribe()
+
+	// Published while nobody is subscribed.
+	PublishToScope(ctx, testScope, 4)
+	PublishToScope(ctx, testScope, 5)
+
+	resumed, unsub := SubscribeFromWatermark[int](ctx, testScope, watermark)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 6)
+
+	assert.Equal(t, 4, <-resumed)
+	assert.Equal(t, 5, <-resumed)
+	assert.Equal(t, 6, <-resumed)
+}
+
+func TestSubscribeFromWatermark_Limit(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithReplay(2))
+
+	for i := 1; i <= 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	PublishToScope(ctx, testScope, "other type")
+
+	sub := NewSubscriptionFromWatermark[int](ctx, testScope, 0)
+	defer sub.Unsubscribe()
+
+	assert.Equal(t, 4, <-sub.C)
+	assert.Equal(t, 5, <-sub.C)
+	assert.Equal(t, int64(5), sub.Watermark())
+}
+
+func TestSubscribeFromWatermark_NoReplay(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	PublishToScope(ctx, testScope, 1)
+
+	testingCh, unsub := SubscribeFromWatermark[int](ctx, testScope, 0)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 2, <-testingCh)
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	sub := NewSubscription[int](ctx, testScope)
+	PublishToScope(ctx, testScope, 1)
+	sub.Unsubscribe()
+
+	// The pending value may or may not be delivered, but the channel must close.
+	for range sub.C {
+	}
+}
+
+func TestSubscribeFromWatermark_Unauthorized(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithReplay(0))
+	testScope.AllowSubscribe("int", "reader")
+
+	PublishToScope(ctx, testScope, 1)
+
+	testingCh, unsub := SubscribeFromWatermark[int](WithIdentity(ctx, "intruder"), testScope, 0)
+	defer unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+}
+
+func TestSubscribeFromWatermark_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithReplay(0))
+
+	const count = 200
+	for i := 1; i <= count/2; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+
+	// Publishing continues while the subscription is created. Every value must be seen once,
+	// in order, and the watermark must never move backwards.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := count/2 + 1; i <= count; i++ {
+			PublishToScope(ctx, testScope, i)
+		}
+	}()
+
+	sub := NewSubscriptionFromWatermark[int](ctx, testScope, 0)
+	defer sub.Unsubscribe()
+
+	var last int64
+	for i := 1; i <= count; i++ {
+		assert.Equal(t, i, <-sub.C)
+		watermark := sub.Watermark()
+		assert.GreaterOrEqual(t, watermark, last)
+		last = watermark
+	}
+	<-done
+}
+
+func TestSubscribeFromWatermark_ConcurrentPublishers(t *testing.T) {
+	for name, opt := range map[string]func() EventScopeOption{
+		"Replay": func() EventScopeOption { return WithReplay(0) },
+		"Store":  func() EventScopeOption { return WithStore(NewInMemoryStore()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			testScope := NewEventScope(opt())
+
+			const publishers, perPublisher = 4, 50
+			var wg sync.WaitGroup
+			for p := 0; p < publishers; p++ {
+				wg.Add(1)
+				go func(p int) {
+					defer wg.Done()
+					for i := 0; i < perPublisher; i++ {
+						PublishToScope(ctx, testScope, p*perPublisher+i)
+					}
+				}(p)
+			}
+
+			// Subscriptions made at different points of the publishing must each see every
+			// value exactly once, in sequence order.
+			var subs []*Subscription[int]
+			for i := 0; i < 3; i++ {
+				sub := NewSubscriptionFromWatermark[int](ctx, testScope, 0)
+				defer sub.Unsubscribe()
+				subs = append(subs, sub)
+				runtime.Gosched()
+			}
+			wg.Wait()
+
+			for _, sub := range subs {
+				seen := make(map[int]bool)
+				var last int64
+				for len(seen) < publishers*perPublisher {
+					val := <-sub.C
+					assert.False(t, seen[val], "value %d received twice", val)
+					seen[val] = true
+					assert.GreaterOrEqual(t, sub.Watermark(), last)
+					last = sub.Watermark()
+				}
+				assert.Equal(t, testScope.seq.Load(), last)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package pubsub
+
+import "context"
+
+// Result holds either a value or the error that prevented it from being produced.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// PublishResult publishes Result[T]{Value: val, Err: err} on the event scope, so that the
+// subscribers of a stage of a pipeline learn about the values an earlier stage failed to produce
+// as well as the ones it produced. It returns the same errors as PublishToScope.
+func PublishResult[T any](ctx context.Context, scope *EventScope, val T, err error) error {
+	return PublishToScope(ctx, scope, Result[T]{Value: val, Err: err})
+}
+
+// SubscribeResult subscribes to the values of type Result[T] published on the event scope, such
+// as with PublishResult or by MapResult. It behaves like SubscribeToScope[Result[T]].
+func SubscribeResult[T any](ctx context.Context, scope *EventScope, opts ...SubscribeOption) (chan Result[T], UnsubFn) {
+	return SubscribeToScope[Result[T]](ctx, scope, opts...)
+}
+
+// MapResult publishes a Result[U] on dst for every Result[T] published on src, in the order they
+// were published. fn is applied to the value of every successful result, and its return values
+// become the published result; a failed result is passed on with its error, without calling fn.
+// Chaining calls to MapResult thus builds a pipeline in which an error stops the value it is
+// about from being processed further and reaches the end of the pipeline instead. The
+// subscription to src lasts until the UnsubFn is called, ctx is canceled or src is closed.
+func MapResult[T, U any](ctx context.Context, src *EventScope, fn func(T) (U, error), dst *EventScope) UnsubFn {
+	return SubscribeCallback(ctx, src, func(res Result[T]) {
+		if res.Err != nil {
+			PublishResult(ctx, dst, *new(U), res.Err)
+			return
+		}
+		val, err := fn(res.Value)
+		PublishResult(ctx, dst, val, err)
+	}, WithOrdered())
+}
@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishResult(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	results, unsub := SubscribeResult[int](ctx, testScope)
+	defer unsub()
+
+	require.NoError(t, PublishResult(ctx, testScope, 1, nil))
+	assert.Equal(t, Result[int]{Value: 1}, <-results)
+	require.NoError(t, PublishResult(ctx, testScope, 0, errNotFound))
+	assert.Equal(t, Result[int]{Err: errNotFound}, <-results)
+}
+
+func TestMapResult(t *testing.T) {
+	ctx := context.Background()
+	src := NewEventScope()
+	mid := NewEventScope()
+	dst := NewEventScope()
+
+	unsubParse := MapResult(ctx, src, strconv.Atoi, mid)
+	defer unsubParse()
+	errNegative := errors.New("negative")
+	unsubDouble := MapResult(ctx, mid, func(val int) (int, error) {
+		if val < 0 {
+			return 0, errNegative
+		}
+		return 2 * val, nil
+	}, dst)
+	defer unsubDouble()
+
+	results, unsub := SubscribeResult[int](ctx, dst, WithOrdered())
+	defer unsub()
+
+	for _, s := range []string{"1", "x", "-1", "2"} {
+		require.NoError(t, PublishResult(ctx, src, s, nil))
+	}
+	require.NoError(t, PublishResult(ctx, src, "", errNotFound))
+
+	assert.Equal(t, Result[int]{Value: 2}, <-results)
+	var numErr *strconv.NumError
+	assert.ErrorAs(t, (<-results).Err, &numErr)
+	assert.Equal(t, Result[int]{Err: errNegative}, <-results)
+	assert.Equal(t, Result[int]{Value: 4}, <-results)
+	assert.Equal(t, Result[int]{Err: errNotFound}, <-results)
+}
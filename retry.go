@@ -0,0 +1,68 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HandlerError is reported to the scope's error handler when a SubscribeWithRetry handler still
+// fails after its last retry, and when a SupervisedSubscribe handler fails.
+type HandlerError struct {
+	// TypeName is the name of the type of Value, as used by bridges.
+	TypeName string
+	Value    any
+
+	// Attempts is the number of times the handler was called with Value.
+	Attempts int
+
+	// Err is the error the handler returned on its last attempt.
+	Err error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("pubsub: handling %s failed after %d attempts: %v", e.TypeName, e.Attempts, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// SubscribeWithRetry subscribes to T on the event scope and calls handler with every value, in
+// publish order, as with WithOrdered. If handler returns an error, it is called again with the same
+// value up to maxRetries times, waiting backoff(attempt) before retry number attempt, starting
+// from 1. backoff may be nil to retry right away, and the waits are measured with the scope's
+// clock. A value the handler still fails on is reported to the scope's error handler as a
+// *HandlerError, and the subscription moves on to the next value. Retries are abandoned once ctx
+// is canceled or the UnsubFn is called.
+func SubscribeWithRetry[T any](ctx context.Context, scope *EventScope, handler func(T) error, maxRetries int, backoff func(attempt int) time.Duration) UnsubFn {
+	ctx, cancel := context.WithCancel(ctx)
+	unsub := SubscribeCallback(ctx, scope, func(val T) {
+		err := handler(val)
+		attempt := 0
+		for ; err != nil && attempt < maxRetries; attempt++ {
+			if backoff != nil {
+				select {
+				case <-scope.clock.After(backoff(attempt + 1)):
+				case <-ctx.Done():
+					return
+				}
+			} else if ctx.Err() != nil {
+				return
+			}
+			err = handler(val)
+		}
+		if err != nil {
+			scope.reportError(&HandlerError{
+				TypeName: typeName[T](),
+				Value:    val,
+				Attempts: attempt + 1,
+				Err:      err,
+			})
+		}
+	}, WithOrdered())
+	return func() {
+		cancel()
+		unsub()
+	}
+}
@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+func TestSubscribeWithRetry(t *testing.T) {
+	ctx := context.Background()
+	reported := make(chan error, 1)
+	testScope := NewEventScope(WithErrorHandler(func(err error) { reported <- err }))
+
+	var mu sync.Mutex
+	calls := make(map[int]int)
+	var backoffs []int
+	handled := make(chan int, 2)
+	unsub := SubscribeWithRetry(ctx, testScope, func(v int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[v]++
+		// 1 succeeds on its third attempt, 2 never does.
+		if v == 2 || calls[v] < 3 {
+			return errTransient
+		}
+		handled <- v
+		return nil
+	}, 3, func(attempt int) time.Duration {
+		mu.Lock()
+		backoffs = append(backoffs, attempt)
+		mu.Unlock()
+		return time.Millisecond
+	})
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, 2)
+	PublishToScope(ctx, testScope, 3)
+
+	var err error
+	select {
+	case err = <-reported:
+	case <-time.After(time.Second):
+		t.Fatal("handler failure was not reported")
+	}
+	var handlerErr *HandlerError
+	require.ErrorAs(t, err, &handlerErr)
+	assert.Equal(t, "int", handlerErr.TypeName)
+	assert.Equal(t, 2, handlerErr.Value)
+	assert.Equal(t, 4, handlerErr.Attempts)
+	assert.ErrorIs(t, err, errTransient)
+
+	// Values after the failed one are still handled.
+	assert.Equal(t, 1, <-handled)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls[3] == 3
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{1, 2, 1, 2, 3, 1, 2}, backoffs)
+}
@@ -0,0 +1,28 @@
+package pubsub
+
+import "reflect"
+
+// WithPublishRewriter intercepts every value of type T published on the scope and replaces it
+// with rewriter(val) before it is delivered, recorded or sent across a bridge. Rewriters
+// registered for the same type are applied in the order they were registered.
+func WithPublishRewriter[T any](rewriter func(T) T) EventScopeOption {
+	return func(e *EventScope) {
+		if e.rewriters == nil {
+			e.rewriters = make(map[reflect.Type][]func(any) any)
+		}
+		t := typeOf[T]()
+		e.rewriters[t] = append(e.rewriters[t], func(val any) any {
+			// val is nil for the zero value of an interface type.
+			typed, _ := val.(T)
+			return rewriter(typed)
+		})
+	}
+}
+
+// rewrite applies the rewriters registered for t to val.
+func (e *EventScope) rewrite(t eventType, val any) any {
+	for _, rewriter := range e.rewriters[t.typ] {
+		val = rewriter(val)
+	}
+	return val
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userProfile struct {
+	Name  string
+	Email string
+}
+
+func TestPublishRewriter(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(
+		WithPublishRewriter(func(u userProfile) userProfile {
+			u.Email = "redacted"
+			return u
+		}),
+		WithPublishRewriter(func(u userProfile) userProfile {
+			u.Name = strings.ToUpper(u.Name)
+			return u
+		}),
+		WithPublishRewriter(func(s string) string { return s + "!" }),
+		WithPublishRewriter(func(s string) string { return s + "?" }),
+	)
+
+	users, unsubUsers := SubscribeToScope[userProfile](ctx, testScope)
+	defer unsubUsers()
+	strs, unsubStrs := SubscribeToScope[string](ctx, testScope)
+	defer unsubStrs()
+	ints, unsubInts := SubscribeToScope[int](ctx, testScope)
+	defer unsubInts()
+
+	PublishToScope(ctx, testScope, userProfile{Name: "alice", Email: "alice@example.com"})
+	assert.Equal(t, userProfile{Name: "ALICE", Email: "redacted"}, <-users)
+
+	PublishToScope(ctx, testScope, "hello")
+	assert.Equal(t, "hello!?", <-strs)
+
+	PublishToScope(ctx, testScope, 1)
+	assert.Equal(t, 1, <-ints)
+}
@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// RingSubscription is a subscription that keeps only the most recent values of type T. Instead
+// of a channel, the buffered values are read with Latest and Drain.
+type RingSubscription[T any] struct {
+	mu    sync.Mutex
+	buf   []T
+	start int
+	n     int
+
+	unsub UnsubFn
+	done  chan struct{}
+}
+
+// SubscribeRingBuffer subscribes to T on the event scope and keeps the last capacity values in a
+// ring buffer. When the buffer is full, each new value overwrites the oldest one, so a slow
+// consumer never holds back publishers and always sees the most recent values. Values are kept in
+// publish order, as with WithOrdered. capacity must be positive.
+func SubscribeRingBuffer[T any](ctx context.Context, e *EventScope, capacity int) *RingSubscription[T] {
+	if capacity <= 0 {
+		panic("pubsub: ring buffer capacity must be positive")
+	}
+
+	ch, unsub := SubscribeToScope[T](ctx, e, WithOrdered())
+	s := &RingSubscription[T]{
+		buf:   make([]T, capacity),
+		unsub: unsub,
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		for val := range ch {
+			s.push(val)
+		}
+	}()
+	return s
+}
+
+func (s *RingSubscription[T]) push(val T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.n < len(s.buf) {
+		s.buf[(s.start+s.n)%len(s.buf)] = val
+		s.n++
+		return
+	}
+	s.buf[s.start] = val
+	s.start = (s.start + 1) % len(s.buf)
+}
+
+// Latest returns the most recent value in the buffer without removing it. The boolean is false
+// if the buffer is empty.
+func (s *RingSubscription[T]) Latest() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.buf[(s.start+s.n-1)%len(s.buf)], true
+}
+
+// Drain removes and returns every value in the buffer, oldest first.
+func (s *RingSubscription[T]) Drain() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vals := make([]T, s.n)
+	for i := range vals {
+		vals[i] = s.buf[(s.start+i)%len(s.buf)]
+	}
+
+	var zero T
+	for i := range s.buf {
+		s.buf[i] = zero
+	}
+	s.start, s.n = 0, 0
+	return vals
+}
+
+// Unsubscribe ends the subscription. Values already in the buffer can still be read.
+func (s *RingSubscription[T]) Unsubscribe() {
+	s.unsub()
+	<-s.done
+}
@@ -0,0 +1,54 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeRingBuffer(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	sub := SubscribeRingBuffer[int](ctx, testScope, 3)
+	defer sub.Unsubscribe()
+
+	_, ok := sub.Latest()
+	assert.False(t, ok)
+
+	for i := 1; i <= 5; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	assert.Eventually(t, func() bool {
+		latest, ok := sub.Latest()
+		return ok && latest == 5
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, []int{3, 4, 5}, sub.Drain())
+	assert.Empty(t, sub.Drain())
+
+	PublishToScope(ctx, testScope, 6)
+	assert.Eventually(t, func() bool {
+		latest, _ := sub.Latest()
+		return latest == 6
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []int{6}, sub.Drain())
+}
+
+func TestSubscribeRingBuffer_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	sub := SubscribeRingBuffer[int](ctx, testScope, 2)
+	PublishToScope(ctx, testScope, 1)
+	assert.Eventually(t, func() bool {
+		_, ok := sub.Latest()
+		return ok
+	}, time.Second, time.Millisecond)
+
+	sub.Unsubscribe()
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, []int{1}, sub.Drain())
+}
@@ -0,0 +1,148 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SagaStep is a single step of a Saga. Execute performs the step's work given the output of the
+// previous step. Compensate undoes the work of a step that completed, given the output it
+// produced, and may be nil if the step has nothing to undo.
+type SagaStep struct {
+	Name       string
+	Execute    func(ctx context.Context, input any) (any, error)
+	Compensate func(ctx context.Context, output any) error
+}
+
+// SagaState describes a transition of a running saga.
+type SagaState int
+
+const (
+	SagaStarted SagaState = iota
+	SagaStepCompleted
+	SagaStepFailed
+	SagaStepCompensated
+	SagaCompleted
+	SagaAborted
+)
+
+func (s SagaState) String() string {
+	switch s {
+	case SagaStarted:
+		return "started"
+	case SagaStepCompleted:
+		return "step completed"
+	case SagaStepFailed:
+		return "step failed"
+	case SagaStepCompensated:
+		return "step compensated"
+	case SagaCompleted:
+		return "completed"
+	case SagaAborted:
+		return "aborted"
+	default:
+		return fmt.Sprintf("SagaState(%d)", int(s))
+	}
+}
+
+// SagaEvent is published on the saga's scope for every state transition. Step is the index of
+// the step the event refers to, or -1 for events about the saga as a whole.
+type SagaEvent struct {
+	SagaID uuid.UUID
+	State  SagaState
+	Step   int
+	Name   string
+	Err    error
+}
+
+// Saga coordinates a transaction made of several steps. The steps run in order, and the output
+// of each step is published on the scope so that other subscribers can follow the transaction.
+// The output is also passed to the next step directly, in memory, rather than through the scope.
+// If a step fails, the steps that already completed are compensated in reverse order.
+type Saga struct {
+	scope *EventScope
+	steps []SagaStep
+}
+
+// NewSaga creates a saga that runs steps in order and publishes its progress on scope.
+func NewSaga(scope *EventScope, steps ...SagaStep) *Saga {
+	return &Saga{
+		scope: scope,
+		steps: steps,
+	}
+}
+
+// Run executes the saga with the given input and returns the output of the last step. If a step
+// fails, or its output cannot be published on the scope, Run compensates the completed steps and
+// returns the step's error joined with any compensation errors. Outputs of types that cannot be
+// published, such as slices and maps, fail their step with ErrUnsupportedType.
+//
+// If the saga's progress events cannot be published, Run returns without running any step. Any
+// later failure to publish a progress event does not stop the saga, but is joined into the
+// returned error.
+func (s *Saga) Run(ctx context.Context, input any) (any, error) {
+	id := uuid.New()
+	if err := s.emit(ctx, SagaEvent{SagaID: id, State: SagaStarted, Step: -1}); err != nil {
+		return nil, err
+	}
+
+	var emitErrs []error
+	emit := func(ev SagaEvent) {
+		if err := s.emit(ctx, ev); err != nil {
+			emitErrs = append(emitErrs, err)
+		}
+	}
+
+	outputs := make([]any, 0, len(s.steps))
+	val := input
+	for i, step := range s.steps {
+		out, err := step.Execute(ctx, val)
+		if err == nil {
+			// The step completed, so it must be compensated even if its output is rejected.
+			outputs = append(outputs, out)
+			err = s.scope.publishValue(ctx, out)
+		}
+		if err != nil {
+			err = fmt.Errorf("saga step %d (%s): %w", i, step.Name, err)
+			emit(SagaEvent{SagaID: id, State: SagaStepFailed, Step: i, Name: step.Name, Err: err})
+			err = errors.Join(err, s.compensate(ctx, id, outputs, emit))
+			return nil, errors.Join(append([]error{err}, emitErrs...)...)
+		}
+
+		emit(SagaEvent{SagaID: id, State: SagaStepCompleted, Step: i, Name: step.Name})
+		val = out
+	}
+
+	emit(SagaEvent{SagaID: id, State: SagaCompleted, Step: -1})
+	return val, errors.Join(emitErrs...)
+}
+
+// compensate undoes the completed steps in reverse order. Every step is compensated even if an
+// earlier compensation fails.
+func (s *Saga) compensate(ctx context.Context, id uuid.UUID, outputs []any, emit func(SagaEvent)) error {
+	var errs []error
+	for i := len(outputs) - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+
+		err := step.Compensate(ctx, outputs[i])
+		if err != nil {
+			err = fmt.Errorf("compensating saga step %d (%s): %w", i, step.Name, err)
+			errs = append(errs, err)
+		}
+		emit(SagaEvent{SagaID: id, State: SagaStepCompensated, Step: i, Name: step.Name, Err: err})
+	}
+
+	err := errors.Join(errs...)
+	emit(SagaEvent{SagaID: id, State: SagaAborted, Step: -1, Err: err})
+	return err
+}
+
+func (s *Saga) emit(ctx context.Context, ev SagaEvent) error {
+	return PublishToScope(ctx, s.scope, ev)
+}
@@ -0,0 +1,154 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderPlaced struct {
+	ID int
+}
+
+type paymentTaken struct {
+	OrderID int
+}
+
+func TestSaga_Completes(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	paymentCh, unsub := SubscribeToScope[paymentTaken](ctx, testScope)
+	defer unsub()
+
+	saga := NewSaga(testScope,
+		SagaStep{
+			Name: "place order",
+			Execute: func(_ context.Context, input any) (any, error) {
+				return orderPlaced{ID: input.(int)}, nil
+			},
+		},
+		SagaStep{
+			Name: "take payment",
+			Execute: func(_ context.Context, input any) (any, error) {
+				return paymentTaken{OrderID: input.(orderPlaced).ID}, nil
+			},
+		},
+	)
+
+	out, err := saga.Run(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, paymentTaken{OrderID: 7}, out)
+	assert.Equal(t, paymentTaken{OrderID: 7}, <-paymentCh)
+}
+
+func TestSaga_Compensates(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	eventCh, unsub := SubscribeToScope[SagaEvent](ctx, testScope)
+	defer unsub()
+	events := make(chan SagaEvent, 16)
+	go func() {
+		for ev := range eventCh {
+			events <- ev
+		}
+	}()
+
+	var compensated []string
+	errPayment := errors.New("card declined")
+	saga := NewSaga(testScope,
+		SagaStep{
+			Name:    "reserve stock",
+			Execute: func(context.Context, any) (any, error) { return "stock", nil },
+			Compensate: func(_ context.Context, output any) error {
+				compensated = append(compensated, output.(string))
+				return nil
+			},
+		},
+		SagaStep{
+			Name:    "place order",
+			Execute: func(context.Context, any) (any, error) { return "order", nil },
+			Compensate: func(_ context.Context, output any) error {
+				compensated = append(compensated, output.(string))
+				return nil
+			},
+		},
+		SagaStep{
+			Name:    "take payment",
+			Execute: func(context.Context, any) (any, error) { return nil, errPayment },
+		},
+	)
+
+	_, err := saga.Run(ctx, nil)
+	assert.ErrorIs(t, err, errPayment)
+	assert.Equal(t, []string{"order", "stock"}, compensated)
+
+	states := map[SagaState]int{}
+	assert.Eventually(t, func() bool {
+		for {
+			select {
+			case ev := <-events:
+				states[ev.State]++
+			default:
+				// Events are not delivered in order, so wait for all of them.
+				return states[SagaAborted] == 1 && states[SagaStepCompensated] == 2 &&
+					states[SagaStepCompleted] == 2 && states[SagaStepFailed] == 1 && states[SagaStarted] == 1
+			}
+		}
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, states[SagaStarted])
+	assert.Equal(t, 2, states[SagaStepCompleted])
+	assert.Equal(t, 1, states[SagaStepFailed])
+	assert.Equal(t, 2, states[SagaStepCompensated])
+	assert.Zero(t, states[SagaCompleted])
+}
+
+func TestSaga_UnsupportedOutput(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	compensated := false
+	saga := NewSaga(testScope,
+		SagaStep{
+			Name:    "list items",
+			Execute: func(context.Context, any) (any, error) { return []int{1, 2}, nil },
+			Compensate: func(context.Context, any) error {
+				compensated = true
+				return nil
+			},
+		},
+		SagaStep{
+			Name: "unreachable",
+			Execute: func(context.Context, any) (any, error) {
+				t.Fatal("step after a rejected output was run")
+				return nil, nil
+			},
+		},
+	)
+
+	_, err := saga.Run(ctx, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.True(t, compensated)
+}
+
+func TestSaga_EventsUnauthorized(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.AllowPublish("pubsub.SagaEvent", "coordinator")
+
+	saga := NewSaga(testScope, SagaStep{
+		Name: "never run",
+		Execute: func(context.Context, any) (any, error) {
+			t.Fatal("step was run without publishing progress")
+			return nil, nil
+		},
+	})
+
+	_, err := saga.Run(ctx, nil)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
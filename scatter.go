@@ -0,0 +1,74 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying a correlation ID, which event scopes attach to
+// every message published with it so subscribers can read it from the Envelope. A responder
+// replies to a request made with ScatterGather by publishing its response with the correlation
+// ID of the request:
+//
+//	requests, unsub := pubsub.SubscribeEnvelopes[PriceQuery](ctx, scope)
+//	defer unsub()
+//	for req := range requests {
+//		replyCtx := pubsub.WithCorrelationID(ctx, req.CorrelationID)
+//		pubsub.PublishToScope(replyCtx, scope, quote(req.Value))
+//	}
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID attached to ctx with WithCorrelationID.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok
+}
+
+// ScatterGather publishes req on the event scope with a new correlation ID, and gathers the
+// responses of type Resp published with the same correlation ID, see WithCorrelationID, until
+// expectedResponses have arrived or timeout elapses. Responses to other requests are ignored.
+// The responses are returned in the order they were received; if timeout elapses first, the
+// ones received so far are returned without an error. If ctx is done first, they are returned
+// along with ctx.Err(), and if req cannot be published, the error of PublishToScope is
+// returned.
+func ScatterGather[Req, Resp any](ctx context.Context, scope *EventScope, req Req, expectedResponses int, timeout time.Duration) ([]Resp, error) {
+	if scope == nil {
+		return nil, ErrNilScope
+	}
+	id := uuid.NewString()
+
+	// Responses are subscribed to before the request is published so none is missed.
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	responses, unsub := subscribe(subCtx, scope, newEnvelope[Resp])
+	defer unsub()
+
+	if err := PublishToScope(WithCorrelationID(ctx, id), scope, req); err != nil {
+		return nil, err
+	}
+
+	expired := scope.clock.After(timeout)
+	var gathered []Resp
+	for len(gathered) < expectedResponses {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				return gathered, subscriptionErr(ctx, scope)
+			}
+			if resp.CorrelationID == id {
+				gathered = append(gathered, resp.Value)
+			}
+		case <-expired:
+			return gathered, nil
+		case <-ctx.Done():
+			return gathered, ctx.Err()
+		}
+	}
+	return gathered, nil
+}
@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type quoteRequest struct{ Item string }
+
+type quote struct {
+	Vendor string
+	Item   string
+}
+
+// startResponders subscribes n vendors replying to every quoteRequest, and returns a function
+// that stops them.
+func startResponders(ctx context.Context, scope *EventScope, n int) func() {
+	var wg sync.WaitGroup
+	var unsubs []UnsubFn
+	for i := 0; i < n; i++ {
+		requests, unsub := SubscribeEnvelopes[quoteRequest](ctx, scope)
+		unsubs = append(unsubs, unsub)
+		vendor := fmt.Sprintf("vendor-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range requests {
+				replyCtx := WithCorrelationID(ctx, req.CorrelationID)
+				PublishToScope(replyCtx, scope, quote{Vendor: vendor, Item: req.Value.Item})
+			}
+		}()
+	}
+	return func() {
+		for _, unsub := range unsubs {
+			unsub()
+		}
+		wg.Wait()
+	}
+}
+
+func TestScatterGather(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	defer startResponders(ctx, testScope, 3)()
+
+	// A response to another request is not gathered.
+	PublishToScope(WithCorrelationID(ctx, "other"), testScope, quote{Vendor: "stale"})
+
+	quotes, err := ScatterGather[quoteRequest, quote](ctx, testScope, quoteRequest{Item: "widget"}, 3, time.Second)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []quote{
+		{Vendor: "vendor-0", Item: "widget"},
+		{Vendor: "vendor-1", Item: "widget"},
+		{Vendor: "vendor-2", Item: "widget"},
+	}, quotes)
+}
+
+func TestScatterGather_Timeout(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	defer startResponders(ctx, testScope, 2)()
+
+	quotes, err := ScatterGather[quoteRequest, quote](ctx, testScope, quoteRequest{Item: "widget"}, 3, 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.Len(t, quotes, 2)
+}
+
+func TestScatterGather_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ScatterGather[quoteRequest, quote](ctx, NewEventScope(), quoteRequest{}, 1, time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+}
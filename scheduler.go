@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Scheduler decides which goroutine runs the callbacks of a subscription made with
+// SubscribeOnScheduler.
+type Scheduler interface {
+	// Schedule arranges for fn to be called. It must not block until fn has run, unless it runs
+	// fn itself.
+	Schedule(fn func())
+}
+
+// SubscribeOnScheduler calls fn with every value of type T published on the event scope, like
+// SubscribeCallback, but on the goroutine chosen by scheduler: each value is passed to
+// scheduler.Schedule, in publish order, as with WithOrdered, wrapped in a function that calls fn
+// with it. The values are scheduled one at a time, so a scheduler that runs fn itself delays the
+// values after it until fn returns, while fn may run concurrently with itself on a scheduler with
+// several goroutines.
+func SubscribeOnScheduler[T any](ctx context.Context, scope *EventScope, scheduler Scheduler, fn func(T)) UnsubFn {
+	return SubscribeCallback(ctx, scope, func(val T) {
+		scheduler.Schedule(func() { fn(val) })
+	}, WithOrdered())
+}
+
+// ImmediateScheduler runs every function right away, on the goroutine that schedules it. On a
+// subscription made with SubscribeOnScheduler, that is the goroutine that receives its values.
+type ImmediateScheduler struct{}
+
+func (ImmediateScheduler) Schedule(fn func()) {
+	fn()
+}
+
+// PoolScheduler returns a scheduler that runs functions on a pool of n goroutines, starting them
+// in the order they were scheduled. The pool can also deliver the messages of event scopes, see
+// SharedPool. Close stops it.
+func PoolScheduler(n int) *SharedPool {
+	return NewSharedPool(n)
+}
+
+// Schedule queues fn to be run by one of the pool's goroutines. Once the pool has been closed, fn
+// is run on a goroutine of its own.
+func (p *SharedPool) Schedule(fn func()) {
+	if !p.submit(fn) {
+		go fn()
+	}
+}
+
+// MainThreadScheduler queues functions until a goroutine of the application's choosing runs them
+// with Run or RunPending, such as the goroutine that runs the event loop of a GUI framework.
+type MainThreadScheduler struct {
+	mu    sync.Mutex
+	tasks []func()
+	wake  chan struct{}
+}
+
+// NewMainThreadScheduler returns a scheduler with nothing queued.
+func NewMainThreadScheduler() *MainThreadScheduler {
+	return &MainThreadScheduler{wake: make(chan struct{}, 1)}
+}
+
+// Schedule queues fn. It never blocks.
+func (s *MainThreadScheduler) Schedule(fn func()) {
+	s.mu.Lock()
+	s.tasks = append(s.tasks, fn)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunPending runs the functions queued so far, in the order they were scheduled, on the calling
+// goroutine, and returns how many it ran. Functions scheduled while it runs are left for the next
+// call.
+func (s *MainThreadScheduler) RunPending() int {
+	s.mu.Lock()
+	tasks := s.tasks
+	s.tasks = nil
+	s.mu.Unlock()
+
+	for _, task := range tasks {
+		task()
+	}
+	return len(tasks)
+}
+
+// Run runs queued functions on the calling goroutine as they are scheduled, until ctx is canceled,
+// and then returns ctx.Err().
+func (s *MainThreadScheduler) Run(ctx context.Context) error {
+	for {
+		s.RunPending()
+		select {
+		case <-s.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeOnScheduler_MainThread(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	scheduler := NewMainThreadScheduler()
+
+	var received []int
+	unsub := SubscribeOnScheduler(ctx, testScope, scheduler, func(val int) {
+		received = append(received, val)
+	})
+	defer unsub()
+
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	// Nothing runs until the scheduler is run.
+	require.Eventually(t, func() bool {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return len(scheduler.tasks) == 3
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, received)
+	assert.Equal(t, 3, scheduler.RunPending())
+	assert.Equal(t, []int{0, 1, 2}, received)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- scheduler.Run(runCtx) }()
+	ran := make(chan struct{})
+	scheduler.Schedule(func() { close(ran) })
+	<-ran
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestSubscribeOnScheduler_Pool(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	scheduler := PoolScheduler(2)
+	defer scheduler.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(10)
+	var mu sync.Mutex
+	sum := 0
+	unsub := SubscribeOnScheduler(ctx, testScope, scheduler, func(val int) {
+		mu.Lock()
+		sum += val
+		mu.Unlock()
+		wg.Done()
+	})
+	defer unsub()
+
+	for i := 0; i < 10; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	wg.Wait()
+	assert.Equal(t, 45, sum)
+}
+
+func TestSubscribeOnScheduler_Immediate(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	received := make(chan int)
+	unsub := SubscribeOnScheduler(ctx, testScope, ImmediateScheduler{}, func(val int) {
+		received <- val
+	})
+	defer unsub()
+
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, 2)
+	assert.Equal(t, 1, <-received)
+	assert.Equal(t, 2, <-received)
+}
@@ -0,0 +1,197 @@
+package pubsub
+
+import (
+	"context"
+	"math"
+	"reflect"
+)
+
+// EnableGlobalSequencing exposes the scope's global sequence number to subscribers created with
+// SubscribeOrdered. Every publish on the scope, regardless of its type, is numbered from a single
+// counter, so values of different types can be compared to recover the total order in which they
+// were published, as MergeOrdered does.
+func (e *EventScope) EnableGlobalSequencing() {
+	e.sequencingEnabled.Store(true)
+}
+
+// SequencedMessage is a value delivered by SubscribeOrdered together with its global sequence
+// number. GlobalSeq is zero if global sequencing is not enabled on the scope.
+type SequencedMessage[T any] struct {
+	Value     T
+	GlobalSeq int64
+}
+
+// AnyEvent is an event of any type produced by MergeOrdered.
+type AnyEvent struct {
+	Value     any
+	GlobalSeq int64
+}
+
+// OrderedSub is a stream of events in global sequence order that can be merged with
+// MergeOrdered. It is implemented by the subscriptions returned from SubscribeOrdered.
+type OrderedSub interface {
+	orderedStream() *orderedStream
+}
+
+// orderedStream is the type independent view of an ordered subscription used by MergeOrdered.
+type orderedStream struct {
+	e   *EventScope
+	top *topic
+	sub *subscriber // nil if the subscription was refused
+
+	ch    reflect.Value
+	event func(reflect.Value) AnyEvent
+}
+
+// settled returns a global sequence number up to which the stream has delivered every event. An
+// event of the stream's type is numbered in the same critical section as its topic sequence, so
+// once the subscriber has forwarded everything up to the topic's sequence, nothing numbered
+// before the current global sequence can still arrive.
+func (s *orderedStream) settled() int64 {
+	if s.sub == nil {
+		return math.MaxInt64
+	}
+
+	s.e.seqMu.Lock()
+	global := s.e.seq.Load()
+	published := s.top.seq.Load()
+	s.e.seqMu.Unlock()
+
+	if s.sub.forwarded.Load() < published {
+		return 0
+	}
+	return global
+}
+
+// OrderedSubscription is a subscription created by SubscribeOrdered. Values are received on C.
+type OrderedSubscription[T any] struct {
+	C chan SequencedMessage[T]
+
+	unsub  UnsubFn
+	stream orderedStream
+}
+
+func (s *OrderedSubscription[T]) orderedStream() *orderedStream {
+	return &s.stream
+}
+
+// Unsubscribe ends the subscription and closes C.
+func (s *OrderedSubscription[T]) Unsubscribe() {
+	s.unsub()
+}
+
+// SubscribeOrdered subscribes to T on the event scope and delivers each value with its global
+// sequence number. Values are delivered in publish order, as with WithOrdered.
+func SubscribeOrdered[T any](ctx context.Context, e *EventScope) *OrderedSubscription[T] {
+	s := &OrderedSubscription[T]{stream: orderedStream{e: e}}
+	registered := func(c *subscribeConfig) {
+		c.registered = func(top *topic, sub *subscriber) {
+			s.stream.top = top
+			s.stream.sub = sub
+		}
+	}
+
+	s.C, s.unsub = subscribe(ctx, e, func(val T, msg message) SequencedMessage[T] {
+		sm := SequencedMessage[T]{Value: val}
+		if e.sequencingEnabled.Load() {
+			sm.GlobalSeq = msg.seq
+		}
+		return sm
+	}, WithOrdered(), registered)
+
+	s.stream.ch = reflect.ValueOf(s.C)
+	s.stream.event = func(v reflect.Value) AnyEvent {
+		msg := v.Interface().(SequencedMessage[T])
+		return AnyEvent{Value: msg.Value, GlobalSeq: msg.GlobalSeq}
+	}
+	return s
+}
+
+// MergeOrdered interleaves several ordered streams into a single stream in global sequence
+// order. An event is emitted once every other stream has either an event pending or delivered
+// everything numbered before it, so a stream without traffic does not hold back the others. The
+// subscriptions must not be read from elsewhere while they are merged. The returned channel is
+// closed once every input is closed.
+//
+// The order is that of the events' GlobalSeq, so EnableGlobalSequencing must have been called on
+// the scopes of the subscriptions before they were made. MergeOrdered panics if it has not.
+func MergeOrdered(subs ...OrderedSub) chan AnyEvent {
+	streams := make([]*orderedStream, len(subs))
+	for i, sub := range subs {
+		streams[i] = sub.orderedStream()
+		if e := streams[i].e; e != nil && !e.sequencingEnabled.Load() {
+			panic("pubsub: MergeOrdered without global sequencing enabled on the scope")
+		}
+	}
+	out := make(chan AnyEvent)
+
+	go func() {
+		defer close(out)
+
+		heads := make([]*AnyEvent, len(streams))
+		open := make([]bool, len(streams))
+		for i := range open {
+			open[i] = true
+		}
+
+		// ready reports whether no stream without a pending event can still produce one
+		// numbered before seq.
+		ready := func(seq int64) bool {
+			for i, s := range streams {
+				if open[i] && heads[i] == nil && s.settled() < seq {
+					return false
+				}
+			}
+			return true
+		}
+
+		for {
+			for {
+				min := -1
+				for i, head := range heads {
+					if head != nil && (min < 0 || head.GlobalSeq < heads[min].GlobalSeq) {
+						min = i
+					}
+				}
+				if min < 0 || !ready(heads[min].GlobalSeq) {
+					break
+				}
+				out <- *heads[min]
+				heads[min] = nil
+			}
+
+			// Wait for an event from a stream without one, or for one of those streams to
+			// make progress without producing an event.
+			var cases []reflect.SelectCase
+			var targets []int
+			for i, s := range streams {
+				if !open[i] || heads[i] != nil {
+					continue
+				}
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: s.ch})
+				targets = append(targets, i)
+				if s.sub != nil {
+					cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.sub.progress)})
+					targets = append(targets, -1)
+				}
+			}
+			if len(cases) == 0 {
+				return
+			}
+
+			chosen, v, ok := reflect.Select(cases)
+			i := targets[chosen]
+			if i < 0 {
+				continue
+			}
+			if !ok {
+				open[i] = false
+				continue
+			}
+			ev := streams[i].event(v)
+			heads[i] = &ev
+		}
+	}()
+
+	return out
+}
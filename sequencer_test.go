@@ -0,0 +1,85 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeOrdered(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.EnableGlobalSequencing()
+
+	ints := SubscribeOrdered[int](ctx, testScope)
+	strs := SubscribeOrdered[string](ctx, testScope)
+	merged := MergeOrdered(ints, strs)
+
+	PublishToScope(ctx, testScope, 1)
+	PublishToScope(ctx, testScope, "a")
+	PublishToScope(ctx, testScope, 2)
+	PublishToScope(ctx, testScope, "b")
+	PublishToScope(ctx, testScope, "c")
+
+	var values []any
+	var last int64
+	for i := 0; i < 5; i++ {
+		ev := <-merged
+		assert.Greater(t, ev.GlobalSeq, last)
+		last = ev.GlobalSeq
+		values = append(values, ev.Value)
+	}
+	assert.Equal(t, []any{1, "a", 2, "b", "c"}, values)
+
+	ints.Unsubscribe()
+	strs.Unsubscribe()
+	for range merged {
+	}
+}
+
+func TestMergeOrdered_IdleStream(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	testScope.EnableGlobalSequencing()
+
+	ints := SubscribeOrdered[int](ctx, testScope)
+	defer ints.Unsubscribe()
+	idle := SubscribeOrdered[float64](ctx, testScope)
+	defer idle.Unsubscribe()
+	merged := MergeOrdered(ints, idle)
+
+	// Nothing is ever published to the float64 stream, which must not hold back the ints.
+	for i := 0; i < 20; i++ {
+		PublishToScope(ctx, testScope, i)
+		PublishToScope(ctx, testScope, "ignored")
+	}
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, i, (<-merged).Value)
+	}
+}
+
+func TestSubscribeOrdered_Disabled(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	sub := SubscribeOrdered[int](ctx, testScope)
+	defer sub.Unsubscribe()
+
+	PublishToScope(ctx, testScope, 1)
+	msg := <-sub.C
+	assert.Equal(t, 1, msg.Value)
+	assert.Zero(t, msg.GlobalSeq)
+}
+
+func TestMergeOrdered_Disabled(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	ints := SubscribeOrdered[int](ctx, testScope)
+	defer ints.Unsubscribe()
+	strs := SubscribeOrdered[string](ctx, testScope)
+	defer strs.Unsubscribe()
+
+	assert.Panics(t, func() { MergeOrdered(ints, strs) })
+}
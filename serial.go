@@ -0,0 +1,98 @@
+package pubsub
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// WithSerialDelivery makes the event scope deliver every message from a single goroutine instead
+// of a goroutine per subscriber. Messages are delivered one at a time in the order they were
+// published, and each message is handed to its subscribers one after the other, by decreasing
+// priority: a subscriber is only offered a message once the previous one has received it or
+// stopped receiving. Every subscriber therefore receives the messages in publish order, without
+// WithOrdered, and no two subscribers are ever handed a message at the same time. Handing a
+// message over is all the scope can observe: a subscriber may still be handling it when the next
+// subscriber receives it.
+//
+// Publishing does not wait for the delivery, but a subscriber that does not receive holds back
+// every delivery after it, for every type, so serial delivery trades throughput for its ordering
+// guarantees. A delivery is abandoned once its publish context is canceled.
+func WithSerialDelivery() EventScopeOption {
+	return func(e *EventScope) {
+		e.serial = &serialQueue{scope: e}
+	}
+}
+
+// serialQueue holds the messages waiting for serial delivery, in sequence order. It is drained by
+// a goroutine that is only running while the queue is not empty.
+type serialQueue struct {
+	scope *EventScope
+
+	mu      sync.Mutex
+	jobs    []serialJob
+	running bool
+}
+
+type serialJob struct {
+	ctx context.Context
+	t   eventType
+	msg message
+}
+
+// push queues msg for delivery. It is called with seqMu held, so that the queue is in sequence
+// order. The message counts towards the scope's backlog until it has been delivered.
+func (q *serialQueue) push(ctx context.Context, t eventType, msg message) {
+	q.scope.backlog.add(1)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, serialJob{ctx: ctx, t: t, msg: msg})
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *serialQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.jobs) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		job := q.jobs[0]
+		q.jobs[0] = serialJob{}
+		q.jobs = q.jobs[1:]
+		q.mu.Unlock()
+
+		q.deliver(job)
+		q.scope.backlog.add(-1)
+	}
+}
+
+// deliver hands the message of job to its subscribers one at a time, waiting for each of them to
+// receive it before moving on to the next.
+func (q *serialQueue) deliver(job serialJob) {
+	e := q.scope
+	deliveries, _ := e.deliveriesOf(job.t, job.msg)
+	job.msg.acks.expect(len(deliveries))
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].sub.priority > deliveries[j].sub.priority
+	})
+
+	for _, d := range deliveries {
+		e.enqueue(d.sub)
+		received := make(chan struct{})
+		d.msg.received = func() { close(received) }
+		// A delivery that is not accepted has already settled.
+		if !e.send(job.ctx, d) {
+			continue
+		}
+		select {
+		case <-received:
+		case <-job.ctx.Done():
+		}
+	}
+}
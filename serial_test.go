@@ -0,0 +1,91 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialDelivery(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithSerialDelivery())
+
+	first, unsubFirst := SubscribeToScope[int](ctx, testScope)
+	defer unsubFirst()
+	second, unsubSecond := SubscribeToScope[int](ctx, testScope)
+	defer unsubSecond()
+
+	const count = 100
+	for i := 0; i < count; i++ {
+		require.NoError(t, PublishToScope(ctx, testScope, i))
+	}
+
+	// Without WithOrdered, subscribers still receive the values in publish order, and neither
+	// gets ahead of the other, since the next value is only delivered once both have received
+	// the one before.
+	for i := 0; i < count; i++ {
+		var val int
+		self, other := first, second
+		select {
+		case val = <-first:
+		case val = <-second:
+			self, other = second, first
+		}
+		assert.Equal(t, i, val)
+
+		select {
+		case val := <-other:
+			assert.Equal(t, i, val)
+		case val := <-self:
+			t.Fatalf("received %d while %d was being delivered to the other subscriber", val, i)
+		}
+	}
+	require.NoError(t, testScope.WaitUntilDrained(ctx))
+}
+
+func TestSerialDelivery_Priority(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithSerialDelivery())
+
+	low, unsubLow := SubscribeToScope[int](ctx, testScope)
+	defer unsubLow()
+	high, unsubHigh := SubscribeToScope[int](ctx, testScope, WithPriority(1))
+	defer unsubHigh()
+
+	PublishToScope(ctx, testScope, 1)
+	select {
+	case <-low:
+		t.Fatal("low priority subscriber received first")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.Equal(t, 1, <-high)
+	assert.Equal(t, 1, <-low)
+}
+
+func TestSerialDelivery_Canceled(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithSerialDelivery())
+
+	_, unsubIdle := SubscribeToScope[int](ctx, testScope)
+	defer unsubIdle()
+	testingCh, unsub := SubscribeToScope[int](ctx, testScope, WithPriority(-1))
+	defer unsub()
+
+	// The idle subscriber holds back the delivery until the publish context is canceled.
+	pubCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	PublishToScope(pubCtx, testScope, 1)
+	PublishToScope(ctx, testScope, 2)
+	<-pubCtx.Done()
+	unsubIdle()
+
+	// The value the context was canceled for may or may not reach the other subscriber.
+	val := <-testingCh
+	if val == 1 {
+		val = <-testingCh
+	}
+	assert.Equal(t, 2, val)
+}
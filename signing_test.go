@@ -0,0 +1,121 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigning_RoundTrip(t *testing.T) {
+	testScope := NewEventScope(WithMessageSigning([]byte("secret")))
+
+	data, err := testScope.marshal("string", "signed")
+	require.NoError(t, err)
+
+	val, err := testScope.unmarshal("string", data, decodeAs[string])
+	require.NoError(t, err)
+	assert.Equal(t, "signed", val)
+
+	data[len(data)-1] ^= 0xff
+	_, err = testScope.unmarshal("string", data, decodeAs[string])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = testScope.unmarshal("string", []byte("short"), decodeAs[string])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigning_KeyRotation(t *testing.T) {
+	oldScope := NewEventScope(WithMessageSigning([]byte("old")))
+	newScope := NewEventScope(WithSigningKeys([][]byte{[]byte("new"), []byte("old")}))
+	otherScope := NewEventScope(WithMessageSigning([]byte("other")))
+
+	data, err := oldScope.marshal("int", 42)
+	require.NoError(t, err)
+
+	val, err := newScope.unmarshal("int", data, decodeAs[int])
+	require.NoError(t, err)
+	assert.Equal(t, 42, val)
+
+	_, err = otherScope.unmarshal("int", data, decodeAs[int])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigning_BridgeDropsInvalid(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	left, right := newPipe()
+	scopeA := NewEventScope(WithMessageSigning([]byte("a")))
+	scopeB := NewEventScope(WithMessageSigning([]byte("b")))
+
+	bridgeA := NewBridge(scopeA, left)
+	defer BridgeType[bridgeEvent](ctx, bridgeA)()
+	bridgeB := NewBridge(scopeB, right)
+	defer BridgeType[bridgeEvent](ctx, bridgeB)()
+	go bridgeB.Run(ctx)
+
+	chB, unsubB := SubscribeToScope[bridgeEvent](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "forged"})
+
+	select {
+	case val := <-chB:
+		t.Fatalf("received event with invalid signature %v", val)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type relabeledEvent struct {
+	Msg string
+}
+
+func TestSigning_TypeName(t *testing.T) {
+	testScope := NewEventScope(WithMessageSigning([]byte("secret")))
+
+	data, err := testScope.marshal(typeName[bridgeEvent](), bridgeEvent{Msg: "hello"})
+	require.NoError(t, err)
+
+	_, err = testScope.unmarshal(typeName[bridgeEvent](), data, decodeAs[bridgeEvent])
+	assert.NoError(t, err)
+	_, err = testScope.unmarshal(typeName[relabeledEvent](), data, decodeAs[relabeledEvent])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigning_BridgeDropsRelabeled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := []byte("shared")
+	left, right := newPipe()
+	scopeA := NewEventScope(WithMessageSigning(key))
+	scopeB := NewEventScope(WithMessageSigning(key))
+
+	bridgeB := NewBridge(scopeB, right)
+	defer BridgeType[bridgeEvent](ctx, bridgeB)()
+	defer BridgeType[relabeledEvent](ctx, bridgeB)()
+	go bridgeB.Run(ctx)
+
+	relabeled, unsubRelabeled := SubscribeToScope[relabeledEvent](ctx, scopeB)
+	defer unsubRelabeled()
+	original, unsubOriginal := SubscribeToScope[bridgeEvent](ctx, scopeB)
+	defer unsubOriginal()
+
+	// A validly signed frame replayed under the name of another bridged type is dropped, while
+	// the same frame under its own name is accepted.
+	data, err := scopeA.marshal(typeName[bridgeEvent](), bridgeEvent{Msg: "hello"})
+	require.NoError(t, err)
+	data = append(appendVectorClock(nil, nil), data...)
+	require.NoError(t, left.Send(ctx, typeName[relabeledEvent](), data))
+	require.NoError(t, left.Send(ctx, typeName[bridgeEvent](), data))
+
+	assert.Equal(t, "hello", (<-original).Msg)
+	select {
+	case val := <-relabeled:
+		t.Fatalf("received relabeled event %v", val)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,182 @@
+package pubsub
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// WithBoundedBuffer limits the approximate amount of memory held by in-flight messages to
+// maxBytes. Once the limit is reached, newly published messages are serialized with the scope's
+// Codec, compressed if compression is enabled, and written to spillDir instead of being held in
+// memory. Spilled messages are read back and delivered in publish order as in-flight messages are
+// delivered. If spillDir is empty, the default directory for temporary files is used.
+//
+// The size of a message is estimated by the length of its serialized form. Values the codec
+// cannot serialize are always kept in memory.
+func WithBoundedBuffer(maxBytes int, spillDir string) EventScopeOption {
+	return func(e *EventScope) {
+		if spillDir == "" {
+			spillDir = os.TempDir()
+		}
+		e.spill = &spillBuffer{
+			scope:    e,
+			maxBytes: int64(maxBytes),
+			dir:      spillDir,
+		}
+	}
+}
+
+type spillBuffer struct {
+	scope    *EventScope
+	maxBytes int64
+	dir      string
+
+	mu       sync.Mutex
+	inFlight int64
+	queue    []spilledMessage
+	draining bool
+}
+
+// spilledMessage is a message whose value has been written to disk. The remaining metadata is
+// kept in memory until the message is re-injected.
+type spilledMessage struct {
+	ctx  context.Context
+	t    eventType
+	msg  message
+	path string
+	size int64
+}
+
+func (b *spillBuffer) publish(ctx context.Context, t eventType, msg message) {
+	data, err := b.scope.marshal(t.name(), msg.val)
+	if err != nil {
+		b.deliver(ctx, t, msg, 0)
+		return
+	}
+	size := int64(len(data))
+
+	b.mu.Lock()
+	// A message is only held in memory when it does not need to wait behind spilled messages.
+	// If nothing is in flight it is delivered regardless of its size so the buffer always makes
+	// progress.
+	if len(b.queue) == 0 && (b.inFlight == 0 || b.inFlight+size <= b.maxBytes) {
+		b.inFlight += size
+		b.mu.Unlock()
+		b.deliver(ctx, t, msg, size)
+		return
+	}
+
+	path, err := b.write(data)
+	if err != nil {
+		b.inFlight += size
+		b.mu.Unlock()
+		b.deliver(ctx, t, msg, size)
+		return
+	}
+	msg.val = nil
+	b.scope.backlog.add(1)
+	b.queue = append(b.queue, spilledMessage{
+		ctx:  ctx,
+		t:    t,
+		msg:  msg,
+		path: path,
+		size: size,
+	})
+	b.mu.Unlock()
+}
+
+func (b *spillBuffer) write(data []byte) (string, error) {
+	f, err := os.CreateTemp(b.dir, "pubsub-spill-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (b *spillBuffer) deliver(ctx context.Context, t eventType, msg message, size int64) {
+	b.scope.deliver(ctx, t, msg, func() {
+		b.release(size)
+	})
+}
+
+// release returns size bytes to the buffer and starts re-injecting spilled messages if any are
+// waiting.
+func (b *spillBuffer) release(size int64) {
+	b.mu.Lock()
+	b.inFlight -= size
+	start := !b.draining && len(b.queue) > 0
+	if start {
+		b.draining = true
+	}
+	b.mu.Unlock()
+
+	if start {
+		go b.drain()
+	}
+}
+
+// drain re-injects spilled messages in order until the queue is empty or the memory limit is
+// reached again. In the latter case the next release restarts it.
+func (b *spillBuffer) drain() {
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.draining = false
+			b.mu.Unlock()
+			return
+		}
+		next := b.queue[0]
+		if b.inFlight > 0 && b.inFlight+next.size > b.maxBytes {
+			b.draining = false
+			b.mu.Unlock()
+			return
+		}
+		b.queue[0] = spilledMessage{}
+		b.queue = b.queue[1:]
+		b.inFlight += next.size
+		b.mu.Unlock()
+
+		b.reinject(next)
+	}
+}
+
+func (b *spillBuffer) reinject(s spilledMessage) {
+	// The message stays in the backlog until it has been handed to its subscribers.
+	defer b.scope.backlog.add(-1)
+
+	data, err := os.ReadFile(s.path)
+	os.Remove(s.path)
+	if err != nil {
+		b.scope.drop(s.t.name(), s.msg, uuid.UUID{})
+		s.msg.acks.expect(0)
+		b.release(s.size)
+		return
+	}
+
+	val, err := b.scope.unmarshal(s.t.name(), data, s.t.decode)
+	if err != nil {
+		b.scope.drop(s.t.name(), s.msg, uuid.UUID{})
+		s.msg.acks.expect(0)
+		b.release(s.size)
+		return
+	}
+	s.msg.val = val
+	b.deliver(s.ctx, s.t, s.msg, s.size)
+}
+
+// spilled returns the number of messages currently written to disk.
+func (b *spillBuffer) spilled() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.queue)
+}
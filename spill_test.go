@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type spillEvent struct {
+	N       int
+	Payload string
+}
+
+func TestBoundedBuffer_Spills(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	testScope := NewEventScope(WithBoundedBuffer(64, dir))
+
+	testingCh, unsub := SubscribeToScope[spillEvent](ctx, testScope)
+	defer unsub()
+
+	const count = 10
+	for i := 0; i < count; i++ {
+		PublishToScope(ctx, testScope, spillEvent{N: i, Payload: "some payload data"})
+	}
+
+	assert.Greater(t, testScope.spill.spilled(), 0)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, testScope.spill.spilled(), len(entries))
+
+	// Only one message fits in memory at a time, so the rest are delivered from disk in the
+	// order they were published.
+	for i := 0; i < count; i++ {
+		val := <-testingCh
+		assert.Equal(t, i, val.N)
+		assert.Equal(t, "some payload data", val.Payload)
+	}
+
+	assert.Eventually(t, func() bool {
+		entries, _ := os.ReadDir(dir)
+		return len(entries) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBoundedBuffer_UnderLimit(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	testScope := NewEventScope(WithBoundedBuffer(1<<20, dir))
+
+	testingCh, unsub := SubscribeToScope[spillEvent](ctx, testScope)
+	defer unsub()
+
+	PublishToScope(ctx, testScope, spillEvent{N: 1})
+	assert.Zero(t, testScope.spill.spilled())
+
+	val := <-testingCh
+	assert.Equal(t, 1, val.N)
+}
+
+func TestBoundedBuffer_Unsubscribed(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope(WithBoundedBuffer(64, t.TempDir()))
+
+	// Nobody reads from the subscriber, so at most one message is received by it, another waits
+	// to be received and the rest are spilled behind it.
+	_, unsub := SubscribeToScope[spillEvent](ctx, testScope)
+	for i := 0; i < 3; i++ {
+		PublishToScope(ctx, testScope, spillEvent{N: i, Payload: "some payload data"})
+	}
+	assert.Greater(t, testScope.spill.spilled(), 0)
+
+	// Unsubscribing abandons the pending delivery, which frees the buffer for the spilled
+	// message and everything published afterwards.
+	unsub()
+	assert.Eventually(t, func() bool {
+		return testScope.spill.spilled() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	testingCh, unsub := SubscribeToScope[spillEvent](ctx, testScope)
+	defer unsub()
+	PublishToScope(ctx, testScope, spillEvent{N: 3, Payload: "some payload data"})
+
+	// A spilled message may still be re-injected after the new subscriber is registered.
+	for val := range testingCh {
+		if val.N == 3 {
+			break
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package pubsub
+
+import (
+	"context"
+	"reflect"
+)
+
+// stickyTopicKey is the key sticky subscribers to typ are registered under. They are kept apart
+// from the regular subscribers of the type, since each message is routed to only one of them.
+type stickyTopicKey struct {
+	typ reflect.Type
+}
+
+// SubscribeSticky subscribes to T on the event scope as one of a group of sticky subscribers.
+// Each value of T is delivered to a single subscriber of the group, chosen by consistent hashing
+// on its key, so that values with the same key always reach the same subscriber, in publish
+// order, for as long as it stays subscribed. Regular subscribers of T still receive every value.
+//
+// Values published with PublishSticky are routed by the key they were published with. Other
+// values are routed by the key stickyKey returns for them. Every sticky subscriber of a type is
+// expected to use the same stickyKey function.
+func SubscribeSticky[T any](ctx context.Context, e *EventScope, stickyKey func(T) string) (chan T, UnsubFn) {
+	if e == nil {
+		return SubscribeToScope[T](ctx, e)
+	}
+	typ := eventTypeOf[T]().typ
+	ring := e.ringsOf(typ).stickyRing()
+	return subscribeRing(ctx, e, stickyTopicKey{typ: typ}, ring, "", func(val T) uint64 {
+		return ringHash(stickyKey(val))
+	})
+}
+
+// PublishSticky publishes val on the event scope like PublishToScope, routing it to the sticky
+// subscribers of T by key instead of the key returned by their stickyKey function.
+func PublishSticky[T any](ctx context.Context, e *EventScope, val T, key string) error {
+	if e == nil {
+		return ErrNilScope
+	}
+	msg := e.newMessage(ctx, val)
+	msg.stickyKey, msg.keyed = key, true
+	return e.publish(ctx, eventTypeOf[T](), msg)
+}
@@ -0,0 +1,134 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stickyEvent struct {
+	Source string
+	N      int
+}
+
+func TestSubscribeSticky(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	const subscribers, sources, perSource = 3, 10, 20
+	bySource := func(ev stickyEvent) string { return ev.Source }
+
+	var mu sync.Mutex
+	received := make(map[string][]int)  // source -> values in order received
+	receivers := make(map[string][]int) // source -> subscribers that received it
+	var wg sync.WaitGroup
+	var unsubs []UnsubFn
+	for i := 0; i < subscribers; i++ {
+		ch, unsub := SubscribeSticky(ctx, testScope, bySource)
+		unsubs = append(unsubs, unsub)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for ev := range ch {
+				mu.Lock()
+				received[ev.Source] = append(received[ev.Source], ev.N)
+				if r := receivers[ev.Source]; len(r) == 0 || r[len(r)-1] != i {
+					receivers[ev.Source] = append(r, i)
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	all, unsubAll := SubscribeToScope[stickyEvent](ctx, testScope)
+	defer unsubAll()
+	var total int
+	allDone := make(chan struct{})
+	go func() {
+		defer close(allDone)
+		for range all {
+			total++
+			if total == sources*perSource {
+				return
+			}
+		}
+	}()
+
+	for n := 0; n < perSource; n++ {
+		for s := 0; s < sources; s++ {
+			PublishToScope(ctx, testScope, stickyEvent{Source: fmt.Sprint("source-", s), N: n})
+		}
+	}
+	<-allDone
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		n := 0
+		for _, vals := range received {
+			n += len(vals)
+		}
+		return n == sources*perSource
+	}, time.Second, time.Millisecond)
+
+	for _, unsub := range unsubs {
+		unsub()
+	}
+	wg.Wait()
+
+	used := make(map[int]bool)
+	for s := 0; s < sources; s++ {
+		source := fmt.Sprint("source-", s)
+		assert.Len(t, receivers[source], 1, source)
+		used[receivers[source][0]] = true
+
+		var want []int
+		for n := 0; n < perSource; n++ {
+			want = append(want, n)
+		}
+		assert.Equal(t, want, received[source], source)
+	}
+	assert.Greater(t, len(used), 1)
+}
+
+func TestPublishSticky(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+
+	first, unsubFirst := SubscribeSticky(ctx, testScope, func(int) string { return "ignored" })
+	second, unsubSecond := SubscribeSticky(ctx, testScope, func(int) string { return "ignored" })
+	defer unsubSecond()
+
+	// Find keys routed to each subscriber.
+	routed := func(key string) chan int {
+		PublishSticky(ctx, testScope, 0, key)
+		select {
+		case <-first:
+			return first
+		case <-second:
+			return second
+		}
+	}
+	keys := make(map[chan int]string)
+	for i := 0; len(keys) < 2; i++ {
+		key := fmt.Sprint("key-", i)
+		ch := routed(key)
+		if _, ok := keys[ch]; !ok {
+			keys[ch] = key
+		}
+	}
+
+	for i := 1; i <= 5; i++ {
+		PublishSticky(ctx, testScope, i, keys[first])
+		assert.Equal(t, i, <-first)
+	}
+
+	// Once the first subscriber leaves, its keys move to the remaining one.
+	unsubFirst()
+	PublishSticky(ctx, testScope, 6, keys[first])
+	assert.Equal(t, 6, <-second)
+}
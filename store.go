@@ -0,0 +1,217 @@
+package pubsub
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MessageStore persists the serialized values published on an event scope, by type, so that
+// subscribers can replay them. Unlike a Journal, which logs every message in a single sequence,
+// a store keeps each type apart and can be trimmed. Implementations must be safe for concurrent
+// use.
+type MessageStore interface {
+	// Append stores the serialized value published under typeName with sequence number
+	// seqNum. Appends are made in sequence order.
+	Append(typeName string, seqNum int64, data []byte) error
+
+	// ReadFrom returns the stored values of typeName with a sequence number greater than or
+	// equal to fromSeq, in sequence order.
+	ReadFrom(typeName string, fromSeq int64) ([]StoredMessage, error)
+
+	// Trim deletes the stored values of typeName with a sequence number lower than beforeSeq.
+	Trim(typeName string, beforeSeq int64) error
+}
+
+// StoredMessage is a serialized value held by a MessageStore.
+type StoredMessage struct {
+	Seq  int64
+	Data []byte
+}
+
+// WithStore stores every value published on the event scope in s, serialized with the scope's
+// Codec, compression and signing settings, and makes SubscribeFromWatermark replay values from s
+// instead of retaining them in memory as WithReplay does. A publish whose value cannot be
+// serialized or stored fails with that error without being delivered. Sequence numbers are those
+// of the scope, so a store should only be reused by another scope once it has been trimmed.
+func WithStore(s MessageStore) EventScopeOption {
+	return func(e *EventScope) {
+		e.store = s
+	}
+}
+
+// InMemoryStore is a MessageStore that keeps the values in memory. The zero value is an empty
+// store ready to use.
+type InMemoryStore struct {
+	mu     sync.RWMutex
+	byType map[string][]StoredMessage
+}
+
+// NewInMemoryStore returns an empty store.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{}
+}
+
+func (s *InMemoryStore) Append(typeName string, seqNum int64, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.byType == nil {
+		s.byType = make(map[string][]StoredMessage)
+	}
+	s.byType[typeName] = append(s.byType[typeName], StoredMessage{Seq: seqNum, Data: data})
+	return nil
+}
+
+func (s *InMemoryStore) ReadFrom(typeName string, fromSeq int64) ([]StoredMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	msgs := s.byType[typeName]
+	i := sort.Search(len(msgs), func(i int) bool { return msgs[i].Seq >= fromSeq })
+	return append([]StoredMessage(nil), msgs[i:]...), nil
+}
+
+func (s *InMemoryStore) Trim(typeName string, beforeSeq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := s.byType[typeName]
+	i := sort.Search(len(msgs), func(i int) bool { return msgs[i].Seq >= beforeSeq })
+	if i == len(msgs) {
+		delete(s.byType, typeName)
+		return nil
+	}
+	s.byType[typeName] = append([]StoredMessage(nil), msgs[i:]...)
+	return nil
+}
+
+// FileStore is a MessageStore that keeps each type in a FileJournal of its own, in a directory.
+type FileStore struct {
+	dir  string
+	opts []FileJournalOption
+
+	mu       sync.Mutex
+	journals map[string]*FileJournal
+}
+
+// NewFileStore opens the store in dir, creating the directory if it does not exist. The
+// journals of the store are opened with opts.
+func NewFileStore(dir string, opts ...FileJournalOption) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, opts: opts, journals: make(map[string]*FileJournal)}, nil
+}
+
+// path returns the file that holds the values of typeName.
+func (s *FileStore) path(typeName string) string {
+	return filepath.Join(s.dir, url.PathEscape(typeName)+".journal")
+}
+
+// journal returns the journal of typeName, opening it if needed. It must be called with mu held.
+func (s *FileStore) journal(typeName string) (*FileJournal, error) {
+	if j, ok := s.journals[typeName]; ok {
+		return j, nil
+	}
+	j, err := NewFileJournal(s.path(typeName), s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.journals[typeName] = j
+	return j, nil
+}
+
+func (s *FileStore) Append(typeName string, seqNum int64, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.journal(typeName)
+	if err != nil {
+		return err
+	}
+	return j.Append(seqNum, typeName, data)
+}
+
+func (s *FileStore) ReadFrom(typeName string, fromSeq int64) ([]StoredMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.journal(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return storedFrom(j, fromSeq)
+}
+
+// Trim rewrites the file of typeName without the values numbered before beforeSeq, and replaces
+// the old file with it once it has been flushed.
+func (s *FileStore) Trim(typeName string, beforeSeq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.journal(typeName)
+	if err != nil {
+		return err
+	}
+	kept, err := storedFrom(j, beforeSeq)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(typeName)
+	tmp, err := os.CreateTemp(s.dir, "trim-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	for _, msg := range kept {
+		if _, err := tmp.Write(encodeRecord(msg.Seq, typeName, msg.Data)); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := j.Close(); err != nil {
+		return err
+	}
+	delete(s.journals, typeName)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	_, err = s.journal(typeName)
+	return err
+}
+
+// Close flushes and closes the files of the store.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for name, j := range s.journals {
+		if closeErr := j.Close(); err == nil {
+			err = closeErr
+		}
+		delete(s.journals, name)
+	}
+	return err
+}
+
+func storedFrom(j *FileJournal, fromSeq int64) ([]StoredMessage, error) {
+	var msgs []StoredMessage
+	err := j.Range(fromSeq, func(seq int64, _ string, data []byte) bool {
+		msgs = append(msgs, StoredMessage{Seq: seq, Data: data})
+		return true
+	})
+	return msgs, err
+}
@@ -0,0 +1,104 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMessageStore checks the behavior every MessageStore shares.
+func testMessageStore(t *testing.T, store MessageStore) {
+	for seq := int64(1); seq <= 5; seq++ {
+		require.NoError(t, store.Append("int", seq, []byte{byte(seq)}))
+	}
+	require.NoError(t, store.Append("string", 6, []byte("six")))
+
+	msgs, err := store.ReadFrom("int", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []StoredMessage{{Seq: 4, Data: []byte{4}}, {Seq: 5, Data: []byte{5}}}, msgs)
+	msgs, err = store.ReadFrom("unknown", 0)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	require.NoError(t, store.Trim("int", 3))
+	msgs, err = store.ReadFrom("int", 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, int64(3), msgs[0].Seq)
+
+	// Appending continues after a trim, and other types are left alone.
+	require.NoError(t, store.Append("int", 7, []byte{7}))
+	msgs, err = store.ReadFrom("int", 6)
+	require.NoError(t, err)
+	assert.Equal(t, []StoredMessage{{Seq: 7, Data: []byte{7}}}, msgs)
+	msgs, err = store.ReadFrom("string", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []StoredMessage{{Seq: 6, Data: []byte("six")}}, msgs)
+}
+
+func TestInMemoryStore(t *testing.T) {
+	testMessageStore(t, NewInMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	testMessageStore(t, store)
+	require.NoError(t, store.Close())
+
+	// The values survive reopening the store.
+	store, err = NewFileStore(dir)
+	require.NoError(t, err)
+	defer store.Close()
+	msgs, err := store.ReadFrom("int", 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 4)
+}
+
+func TestWithStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	testScope := NewEventScope(WithStore(store))
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, PublishToScope(ctx, testScope, i))
+	}
+	PublishToScope(ctx, testScope, "other")
+	msgs, err := store.ReadFrom("int", 0)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 4)
+
+	sub := NewSubscriptionFromWatermark[int](ctx, testScope, 2)
+	defer sub.Unsubscribe()
+	PublishToScope(ctx, testScope, 5)
+	for _, want := range []int{3, 4, 5} {
+		assert.Equal(t, want, <-sub.C)
+	}
+	assert.Equal(t, int64(6), sub.Watermark())
+}
+
+type failingStore struct {
+	InMemoryStore
+}
+
+var errStoreDown = errors.New("store down")
+
+func (*failingStore) ReadFrom(string, int64) ([]StoredMessage, error) {
+	return nil, errStoreDown
+}
+
+func TestWithStore_ReadFails(t *testing.T) {
+	ctx := context.Background()
+	reported := make(chan error, 1)
+	testScope := NewEventScope(WithStore(&failingStore{}), WithErrorHandler(func(err error) { reported <- err }))
+
+	testingCh, unsub := SubscribeFromWatermark[int](ctx, testScope, 0)
+	defer unsub()
+	_, ok := <-testingCh
+	assert.False(t, ok)
+	assert.ErrorIs(t, <-reported, errStoreDown)
+}
@@ -0,0 +1,169 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Subscription is a subscription to values of type T that keeps track of how far the subscriber
+// has read. Values are received on C.
+type Subscription[T any] struct {
+	C chan T
+
+	id        uuid.UUID
+	watermark atomic.Int64
+
+	// mu guards unsub, and the scope and detach of a subscription made with NewSubscription or
+	// NewSubscriptionFromWatermark, which MigrateSubscription replaces.
+	mu    sync.Mutex
+	unsub UnsubFn
+
+	// ctx is the context the subscribers of the subscription are registered with, and scope the
+	// event scope its subscriber currently is on. detach removes that subscriber from the scope
+	// without ending the subscription.
+	ctx    context.Context
+	scope  *EventScope
+	detach UnsubFn
+
+	// feeds holds the live messages forward switches to once those it reads are closed, because
+	// the subscriber was moved to another scope.
+	feeds chan feed
+
+	// delivered is the sequence number of the last value the send on C has completed for, which
+	// unlike the watermark does not count a value that is still being sent.
+	delivered atomic.Int64
+}
+
+// NewSubscription subscribes to live values of type T on the event scope. Values are delivered
+// in publish order, as with WithOrdered, so the watermark only ever moves forward.
+func NewSubscription[T any](ctx context.Context, e *EventScope) *Subscription[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	id := uuid.New()
+	live, unsub := subscribe(ctx, e, func(_ T, msg message) message { return msg }, WithOrdered(), withSubscriberID(id))
+
+	sub := newSubscription[T](0, nil)
+	sub.id = id
+	sub.attach(ctx, cancel, e, unsub)
+	trackSubscription(e, sub)
+	go sub.forward(ctx, nil, live, 0)
+	return sub
+}
+
+func newSubscription[T any](watermark int64, unsub UnsubFn) *Subscription[T] {
+	sub := &Subscription[T]{
+		C:     make(chan T),
+		id:    uuid.New(),
+		unsub: unsub,
+	}
+	sub.watermark.Store(watermark)
+	sub.delivered.Store(watermark)
+	return sub
+}
+
+// feed is a stream of live messages for forward, along with the watermark it starts after.
+type feed struct {
+	live      <-chan message
+	watermark int64
+}
+
+// attach makes the subscription's subscriber on e, registered with ctx, one that can be moved to
+// another scope. Unsubscribing cancels ctx and removes whichever subscriber is current.
+func (s *Subscription[T]) attach(ctx context.Context, cancel context.CancelFunc, e *EventScope, detach UnsubFn) {
+	s.ctx = ctx
+	s.scope = e
+	s.detach = detach
+	s.feeds = make(chan feed, 1)
+	s.unsub = func() {
+		cancel()
+		s.mu.Lock()
+		detach := s.detach
+		s.mu.Unlock()
+		detach()
+	}
+}
+
+// forward sends the replayed messages on C, followed by the live messages with a sequence number
+// greater than cutoff. The watermark is advanced after every value the subscriber receives.
+func (s *Subscription[T]) forward(ctx context.Context, replayed []message, live <-chan message, cutoff int64) {
+	defer close(s.C)
+
+	send := func(msg message) bool {
+		// The watermark is advanced before the send so that it is already up to date when the
+		// subscriber receives the value, and rolled back if the value is never received.
+		prev := s.watermark.Swap(msg.seq)
+		// A nil value is the zero value of an interface type T.
+		typed, _ := msg.val.(T)
+		select {
+		case s.C <- typed:
+			s.delivered.Store(msg.seq)
+			return true
+		case <-ctx.Done():
+			s.watermark.Store(prev)
+			return false
+		}
+	}
+
+	for _, msg := range replayed {
+		if !send(msg) {
+			s.Unsubscribe()
+			return
+		}
+	}
+	for {
+		for msg := range live {
+			if msg.seq <= cutoff {
+				continue
+			}
+			if !send(msg) {
+				s.Unsubscribe()
+				return
+			}
+		}
+		next, ok := s.next()
+		if !ok {
+			return
+		}
+		live, cutoff = next.live, 0
+		s.watermark.Store(next.watermark)
+		s.delivered.Store(next.watermark)
+	}
+}
+
+// next returns the live messages to forward once the current ones are closed, if the subscriber
+// has been moved to another scope. Otherwise the subscription has ended and can no longer be
+// moved.
+func (s *Subscription[T]) next() (feed, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case next := <-s.feeds:
+		return next, true
+	default:
+		s.scope = nil
+		return feed{}, false
+	}
+}
+
+// Watermark returns the sequence number of the last value received from C. It can be passed to
+// SubscribeFromWatermark to resume after this value.
+func (s *Subscription[T]) Watermark() int64 {
+	return s.watermark.Load()
+}
+
+// ID returns the ID of the subscription, which identifies it in the state written by
+// SerializeSubscriberState.
+func (s *Subscription[T]) ID() uuid.UUID {
+	return s.id
+}
+
+// Unsubscribe ends the subscription and closes C.
+func (s *Subscription[T]) Unsubscribe() {
+	s.mu.Lock()
+	unsub := s.unsub
+	s.mu.Unlock()
+	unsub()
+}
@@ -0,0 +1,68 @@
+package pubsub
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_NilIntf(t *testing.T) {
+	ctx := context.Background()
+
+	for name, subscribe := range map[string]func(t *testing.T, scope *EventScope) (chan error, UnsubFn){
+		"NewSubscription": func(t *testing.T, scope *EventScope) (chan error, UnsubFn) {
+			sub := NewSubscription[error](ctx, scope)
+			return sub.C, sub.Unsubscribe
+		},
+		"SubscribeFromWatermark": func(t *testing.T, scope *EventScope) (chan error, UnsubFn) {
+			return SubscribeFromWatermark[error](ctx, scope, 0)
+		},
+		"SubscribeFromJournal": func(t *testing.T, scope *EventScope) (chan error, UnsubFn) {
+			ch, unsub, err := SubscribeFromJournal[error](ctx, scope, 0)
+			require.NoError(t, err)
+			return ch, unsub
+		},
+		"SubscribeRecent": func(t *testing.T, scope *EventScope) (chan error, UnsubFn) {
+			return SubscribeRecent[error](ctx, scope, 10)
+		},
+		"ResumeSubscription": func(t *testing.T, scope *EventScope) (chan error, UnsubFn) {
+			old := NewEventScope()
+			sub := NewSubscription[error](ctx, old)
+			state, err := old.SerializeSubscriberState()
+			require.NoError(t, err)
+			require.NoError(t, old.Close())
+
+			require.NoError(t, scope.RestoreSubscriberState(state))
+			resumed, err := ResumeSubscription[error](ctx, scope, sub.ID())
+			require.NoError(t, err)
+			return resumed.C, resumed.Unsubscribe
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			j, err := NewFileJournal(filepath.Join(t.TempDir(), "journal"))
+			require.NoError(t, err)
+			defer j.Close()
+			testScope := NewEventScope(WithReplay(10), WithHistory(10))
+			require.NoError(t, testScope.UseJournal(j))
+
+			// The first value is replayed by the subscriptions that replay, the second is live.
+			require.NoError(t, PublishToScope[error](ctx, testScope, nil))
+			ch, unsub := subscribe(t, testScope)
+			defer unsub()
+			require.NoError(t, PublishToScope[error](ctx, testScope, nil))
+
+			val, ok := <-ch
+			assert.True(t, ok)
+			assert.Nil(t, val)
+			if name == "NewSubscription" || name == "ResumeSubscription" {
+				return
+			}
+			val, ok = <-ch
+			assert.True(t, ok)
+			assert.Nil(t, val)
+		})
+	}
+}
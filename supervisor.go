@@ -0,0 +1,165 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// RestartPolicy decides whether SupervisedSubscribe restarts a handler that failed.
+type RestartPolicy int
+
+const (
+	// RestartAlways restarts the handler whether it returned an error or panicked.
+	RestartAlways RestartPolicy = iota
+	// RestartOnError restarts the handler when it returns an error, and ends the subscription
+	// when it panics.
+	RestartOnError
+	// RestartNever ends the subscription the first time the handler fails.
+	RestartNever
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartAlways:
+		return "always"
+	case RestartOnError:
+		return "on error"
+	case RestartNever:
+		return "never"
+	default:
+		return fmt.Sprintf("RestartPolicy(%d)", int(p))
+	}
+}
+
+// restarts reports whether the policy restarts a handler that failed, by panicking or not.
+func (p RestartPolicy) restarts(panicked bool) bool {
+	switch p {
+	case RestartAlways:
+		return true
+	case RestartOnError:
+		return !panicked
+	default:
+		return false
+	}
+}
+
+// The wait before a restart doubles with every failure in a row, from supervisorMinBackoff up to
+// supervisorMaxBackoff.
+const (
+	supervisorMinBackoff = 10 * time.Millisecond
+	supervisorMaxBackoff = 10 * time.Second
+)
+
+// supervisorBackoff returns the wait before restarting a handler that failed failures times in a
+// row: half of the exponential delay, plus a random jitter of up to the other half, so that
+// handlers failing together do not restart together.
+func supervisorBackoff(failures int) time.Duration {
+	d := supervisorMaxBackoff
+	if shift := failures - 1; shift < 32 {
+		d = min(supervisorMinBackoff<<shift, supervisorMaxBackoff)
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// SupervisedSub is a subscription made with SupervisedSubscribe.
+type SupervisedSub struct {
+	unsub    UnsubFn
+	restarts atomic.Int64
+}
+
+// RestartCount returns the number of times the handler has been restarted.
+func (s *SupervisedSub) RestartCount() int {
+	return int(s.restarts.Load())
+}
+
+// Unsubscribe ends the subscription, and stops the handler once it has returned.
+func (s *SupervisedSub) Unsubscribe() {
+	s.unsub()
+}
+
+// SupervisedSubscribe subscribes to T on the event scope and calls handler with every value, in
+// publish order, as with WithOrdered, from a goroutine the subscription supervises. When handler
+// returns an error or panics, the goroutine stops, the failure is reported to the scope's error
+// handler as a *HandlerError and, if policy allows it, a new goroutine takes over the
+// subscription, so that a failing handler does not lose it. The value the handler failed on is
+// not handed to it again.
+//
+// Restarts are delayed exponentially with the number of failures in a row, with jitter, from
+// 10ms up to 10s, measured with the scope's clock. A handler that handles a value without
+// failing starts over from the shortest delay. The subscription ends when the SupervisedSub is
+// unsubscribed, ctx is canceled, the scope is closed, or the handler fails and policy does not
+// restart it.
+func SupervisedSubscribe[T any](ctx context.Context, scope *EventScope, handler func(T) error, policy RestartPolicy) *SupervisedSub {
+	ctx, cancel := context.WithCancel(ctx)
+	ch, unsub := SubscribeToScope[T](ctx, scope, WithOrdered())
+	s := &SupervisedSub{unsub: func() {
+		cancel()
+		unsub()
+	}}
+	go supervise(ctx, scope, s, ch, handler, policy)
+	return s
+}
+
+// workerExit is how the goroutine running a supervised handler stopped. err is nil if the
+// subscription ended.
+type workerExit struct {
+	err      error
+	panicked bool
+	handled  int
+}
+
+func supervise[T any](ctx context.Context, scope *EventScope, s *SupervisedSub, ch <-chan T, handler func(T) error, policy RestartPolicy) {
+	failures := 0
+	for {
+		exited := make(chan workerExit, 1)
+		go func() {
+			exited <- runSupervised(ch, handler)
+		}()
+		exit := <-exited
+		if exit.err == nil {
+			return
+		}
+		scope.reportError(exit.err)
+		if !policy.restarts(exit.panicked) {
+			s.Unsubscribe()
+			return
+		}
+
+		if exit.handled > 0 {
+			failures = 0
+		}
+		failures++
+		select {
+		case <-scope.clock.After(supervisorBackoff(failures)):
+		case <-ctx.Done():
+			return
+		}
+		s.restarts.Add(1)
+	}
+}
+
+// runSupervised calls handler with the values of ch until it fails or ch is closed.
+func runSupervised[T any](ch <-chan T, handler func(T) error) (exit workerExit) {
+	var val T
+	defer func() {
+		if r := recover(); r != nil {
+			exit = workerExit{
+				err:      &HandlerError{TypeName: typeName[T](), Value: val, Attempts: 1, Err: fmt.Errorf("panic: %v", r)},
+				panicked: true,
+				handled:  exit.handled,
+			}
+		}
+	}()
+
+	for val = range ch {
+		if err := handler(val); err != nil {
+			exit.err = &HandlerError{TypeName: typeName[T](), Value: val, Attempts: 1, Err: err}
+			return exit
+		}
+		exit.handled++
+	}
+	return exit
+}
@@ -0,0 +1,101 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errHandler = errors.New("handler failed")
+
+// supervisedScope returns a scope whose reported errors are sent on the returned channel.
+func supervisedScope() (*EventScope, chan error) {
+	reported := make(chan error, 10)
+	return NewEventScope(WithErrorHandler(func(err error) { reported <- err })), reported
+}
+
+func TestSupervisedSubscribe(t *testing.T) {
+	ctx := context.Background()
+	testScope, reported := supervisedScope()
+
+	handled := make(chan int, 10)
+	sub := SupervisedSubscribe(ctx, testScope, func(val int) error {
+		switch val {
+		case 1:
+			return errHandler
+		case 2:
+			panic("boom")
+		}
+		handled <- val
+		return nil
+	}, RestartAlways)
+	defer sub.Unsubscribe()
+
+	for i := 1; i <= 3; i++ {
+		PublishToScope(ctx, testScope, i)
+	}
+	assert.Equal(t, 3, <-handled)
+	assert.Equal(t, 2, sub.RestartCount())
+
+	var handlerErr *HandlerError
+	require.ErrorAs(t, <-reported, &handlerErr)
+	assert.ErrorIs(t, handlerErr, errHandler)
+	assert.Equal(t, 1, handlerErr.Value)
+	require.ErrorAs(t, <-reported, &handlerErr)
+	assert.EqualError(t, handlerErr.Err, "panic: boom")
+}
+
+func TestSupervisedSubscribe_Policies(t *testing.T) {
+	for _, tc := range []struct {
+		policy  RestartPolicy
+		fail    func() error
+		restart bool
+	}{
+		{policy: RestartOnError, fail: func() error { return errHandler }, restart: true},
+		{policy: RestartOnError, fail: func() error { panic("boom") }},
+		{policy: RestartNever, fail: func() error { return errHandler }},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			ctx := context.Background()
+			testScope, reported := supervisedScope()
+
+			handled := make(chan int, 10)
+			sub := SupervisedSubscribe(ctx, testScope, func(val int) error {
+				if val == 1 {
+					return tc.fail()
+				}
+				handled <- val
+				return nil
+			}, tc.policy)
+			defer sub.Unsubscribe()
+
+			PublishToScope(ctx, testScope, 1)
+			<-reported
+			if !tc.restart {
+				// The subscription has ended.
+				assert.Eventually(t, func() bool { return testScope.Stats().Subscribers == 0 }, time.Second, time.Millisecond)
+				assert.Zero(t, sub.RestartCount())
+				return
+			}
+			PublishToScope(ctx, testScope, 2)
+			assert.Equal(t, 2, <-handled)
+			assert.Equal(t, 1, sub.RestartCount())
+		})
+	}
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	for failures, limit := range map[int]time.Duration{
+		1:   supervisorMinBackoff,
+		3:   4 * supervisorMinBackoff,
+		100: supervisorMaxBackoff,
+	} {
+		d := supervisorBackoff(failures)
+		assert.GreaterOrEqual(t, d, limit/2)
+		assert.LessOrEqual(t, d, limit)
+	}
+}
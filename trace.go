@@ -0,0 +1,50 @@
+package pubsub
+
+import "context"
+
+// WithTraceExtractor sets a function the scope calls with the context of every publish, once it
+// has been through the scope's middleware, to capture the trace headers the message carries to
+// its subscribers. With OpenTelemetry, fn would inject the span context of ctx into a map with a
+// propagator, for example:
+//
+//	pubsub.WithTraceExtractor(func(ctx context.Context) map[string]string {
+//		headers := map[string]string{}
+//		otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+//		return headers
+//	})
+//
+// The headers are kept in memory with the message; they are not written to journals, message
+// stores or transports.
+func WithTraceExtractor(fn func(ctx context.Context) map[string]string) EventScopeOption {
+	return func(e *EventScope) {
+		e.traceExtractor = fn
+	}
+}
+
+// WithTraceInjector sets a function the scope calls with the context of a subscriber and the
+// trace headers of each message delivered to it, captured by the scope's trace extractor, to
+// return the context the message is delivered with, as seen by the receive middleware of the
+// scope. With OpenTelemetry, fn would extract the headers with the propagator used by the
+// extractor. Messages that carry no headers are delivered with the subscriber's context.
+func WithTraceInjector(fn func(ctx context.Context, headers map[string]string) context.Context) EventScopeOption {
+	return func(e *EventScope) {
+		e.traceInjector = fn
+	}
+}
+
+// extractTrace returns the trace headers of a publish made with ctx, or nil if the scope has no
+// trace extractor.
+func (e *EventScope) extractTrace(ctx context.Context) map[string]string {
+	if e.traceExtractor == nil {
+		return nil
+	}
+	return e.traceExtractor(ctx)
+}
+
+// injectTrace returns the context msg is delivered with to a subscriber with the context ctx.
+func (e *EventScope) injectTrace(ctx context.Context, msg message) context.Context {
+	if e.traceInjector == nil || msg.trace == nil {
+		return ctx
+	}
+	return e.traceInjector(ctx, msg.trace)
+}
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+func TestTracePropagation(t *testing.T) {
+	extract := func(ctx context.Context) map[string]string {
+		traceID, _ := ctx.Value(traceKey{}).(string)
+		return map[string]string{"traceparent": traceID}
+	}
+	inject := func(ctx context.Context, headers map[string]string) context.Context {
+		return context.WithValue(ctx, traceKey{}, headers["traceparent"])
+	}
+	testScope := NewEventScope(WithTraceExtractor(extract), WithTraceInjector(inject))
+
+	traces := make(chan any, 1)
+	testScope.Use(ReceiveMiddlewareFunc(func(ctx context.Context, typeName string, val any, next ReceiveFn) {
+		traces <- ctx.Value(traceKey{})
+		next(ctx, typeName, val)
+	}))
+
+	testingCh, unsub := SubscribeToScope[int](context.Background(), testScope)
+	defer unsub()
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "00-trace-span-01")
+	require.NoError(t, PublishToScope(ctx, testScope, 1))
+	assert.Equal(t, 1, <-testingCh)
+	assert.Equal(t, "00-trace-span-01", <-traces)
+}
+
+func TestTracePropagation_NoExtractor(t *testing.T) {
+	injected := false
+	testScope := NewEventScope(WithTraceInjector(func(ctx context.Context, _ map[string]string) context.Context {
+		injected = true
+		return ctx
+	}))
+
+	testingCh, unsub := SubscribeToScope[int](context.Background(), testScope)
+	defer unsub()
+
+	require.NoError(t, PublishToScope(context.Background(), testScope, 1))
+	assert.Equal(t, 1, <-testingCh)
+	assert.False(t, injected)
+}
@@ -0,0 +1,142 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Transport carries serialized messages between federated event scopes. Messages are tagged with
+// the name of their type so the receiving side knows how to decode them. Implementations must be
+// safe for concurrent use.
+type Transport interface {
+	// Send transmits data published under typeName to the remote side.
+	Send(ctx context.Context, typeName string, data []byte) error
+
+	// Receive blocks until a message arrives from the remote side or ctx is canceled.
+	Receive(ctx context.Context) (typeName string, data []byte, err error)
+
+	// Close releases the resources held by the transport. Pending and future calls to Send
+	// and Receive return an error.
+	Close() error
+}
+
+// Bridge federates an EventScope with a remote scope over a Transport. Only types registered
+// with BridgeType are exchanged. Messages received from the remote side are published locally
+// but are never sent back across the bridge they arrived on. If vector clocks are enabled, the
+// clock of every message travels with it and is merged into the receiving scope's clock.
+type Bridge struct {
+	scope     *EventScope
+	transport Transport
+
+	types sync.Map // type name -> eventType
+
+	onError func(typeName string, err error)
+	filter  func(typeName string) bool
+}
+
+// BridgeOption configures a Bridge at creation time.
+type BridgeOption func(*Bridge)
+
+// WithBridgeErrorHandler sets a function that is called with the type name and the error whenever
+// a local value cannot be forwarded to the remote side, either because it cannot be serialized or
+// because the transport fails to send it. Without a handler such values are dropped silently.
+func WithBridgeErrorHandler(fn func(typeName string, err error)) BridgeOption {
+	return func(b *Bridge) {
+		b.onError = fn
+	}
+}
+
+// WithBridgeFilter sets a function that decides, for every local value, whether it is forwarded to
+// the remote side, given the name of its type. Values it returns false for are dropped before they
+// are serialized. Without a filter every value of a registered type is forwarded.
+func WithBridgeFilter(fn func(typeName string) bool) BridgeOption {
+	return func(b *Bridge) {
+		b.filter = fn
+	}
+}
+
+// NewBridge creates a bridge between scope and the remote side of transport. Call Run to start
+// receiving remote messages.
+func NewBridge(scope *EventScope, transport Transport, opts ...BridgeOption) *Bridge {
+	b := &Bridge{
+		scope:     scope,
+		transport: transport,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// BridgeType starts exchanging values of type T across the bridge. Local publishes of T are
+// serialized with the scope's Codec and sent to the remote side, and values of T received from
+// the remote side are published on the local scope. Both sides of the bridge must register the
+// same types and use the same codec and compression settings. Values that fail to be forwarded
+// are reported to the handler set with WithBridgeErrorHandler. The returned UnsubFn stops
+// forwarding local values. The bridge's subscription to T does not count towards the scope's
+// interest in T, as reported by WatchInterest.
+func BridgeType[T any](ctx context.Context, b *Bridge) UnsubFn {
+	t := eventTypeOf[T]()
+	name := t.name()
+	b.types.Store(name, t)
+
+	ch, unsub := subscribe(ctx, b.scope, func(_ T, msg message) message { return msg }, withPassive())
+	go func() {
+		for msg := range ch {
+			if msg.origin == b || (b.filter != nil && !b.filter(name)) {
+				continue
+			}
+			data, err := b.scope.marshal(name, msg.val)
+			if err == nil {
+				err = b.transport.Send(ctx, name, append(appendVectorClock(nil, msg.vclock), data...))
+			}
+			if err != nil && b.onError != nil {
+				b.onError(name, err)
+			}
+		}
+	}()
+
+	return func() {
+		b.types.Delete(name)
+		unsub()
+	}
+}
+
+// Run receives messages from the remote side and publishes them on the local scope until ctx is
+// canceled or the transport fails. Messages of types that have not been registered with
+// BridgeType are discarded. Received messages are published with the identity attached to ctx,
+// and messages that identity may not publish are discarded as well.
+func (b *Bridge) Run(ctx context.Context) error {
+	for {
+		name, data, err := b.transport.Receive(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+				return ctxErr
+			}
+			return err
+		}
+
+		v, ok := b.types.Load(name)
+		if !ok {
+			continue
+		}
+		t := v.(eventType)
+		clock, data, err := readVectorClock(data)
+		if err != nil {
+			continue
+		}
+		val, err := b.scope.unmarshal(name, data, t.decode)
+		if err != nil {
+			continue
+		}
+
+		// Merging the sender's clock orders the local copy of the event, and everything
+		// published after it, after the remote event.
+		b.scope.ObserveVectorClock(clock)
+
+		msg := b.scope.newMessage(ctx, val)
+		msg.origin = b
+		b.scope.publish(ctx, t, msg)
+	}
+}
@@ -0,0 +1,179 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pipeFrame struct {
+	name string
+	data []byte
+}
+
+// pipeTransport is an in-memory Transport. Frames sent on one end are received on the other.
+type pipeTransport struct {
+	in  <-chan pipeFrame
+	out chan<- pipeFrame
+}
+
+func newPipe() (*pipeTransport, *pipeTransport) {
+	a := make(chan pipeFrame, 16)
+	b := make(chan pipeFrame, 16)
+	return &pipeTransport{in: a, out: b}, &pipeTransport{in: b, out: a}
+}
+
+func (p *pipeTransport) Send(ctx context.Context, name string, data []byte) error {
+	select {
+	case p.out <- pipeFrame{name: name, data: data}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pipeTransport) Receive(ctx context.Context) (string, []byte, error) {
+	select {
+	case f, ok := <-p.in:
+		if !ok {
+			return "", nil, errors.New("closed")
+		}
+		return f.name, f.data, nil
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
+
+func (p *pipeTransport) Close() error {
+	return nil
+}
+
+type bridgeEvent struct {
+	Msg string
+}
+
+func TestBridge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	left, right := newPipe()
+	scopeA := NewEventScope()
+	scopeB := NewEventScope()
+
+	bridgeA := NewBridge(scopeA, left)
+	defer BridgeType[bridgeEvent](ctx, bridgeA)()
+	bridgeB := NewBridge(scopeB, right)
+	defer BridgeType[bridgeEvent](ctx, bridgeB)()
+
+	go bridgeA.Run(ctx)
+	go bridgeB.Run(ctx)
+
+	chA, unsubA := SubscribeToScope[bridgeEvent](ctx, scopeA)
+	defer unsubA()
+	chB, unsubB := SubscribeToScope[bridgeEvent](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "hello"})
+
+	assert.Equal(t, "hello", (<-chA).Msg)
+	assert.Equal(t, "hello", (<-chB).Msg)
+
+	// The event must not be echoed back to scope A.
+	select {
+	case val := <-chA:
+		t.Fatalf("received echoed event %v", val)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBridge_UnregisteredType(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	left, right := newPipe()
+	scopeA := NewEventScope()
+	scopeB := NewEventScope()
+
+	bridgeA := NewBridge(scopeA, left)
+	defer BridgeType[bridgeEvent](ctx, bridgeA)()
+	bridgeB := NewBridge(scopeB, right)
+	go bridgeB.Run(ctx)
+
+	chB, unsubB := SubscribeToScope[bridgeEvent](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "hello"})
+
+	select {
+	case val := <-chB:
+		t.Fatalf("received unregistered event %v", val)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBridge_MergesVectorClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	left, right := newPipe()
+	scopeA := NewEventScope()
+	scopeA.EnableVectorClock("a")
+	scopeB := NewEventScope()
+	scopeB.EnableVectorClock("b")
+
+	bridgeA := NewBridge(scopeA, left)
+	defer BridgeType[bridgeEvent](ctx, bridgeA)()
+	bridgeB := NewBridge(scopeB, right)
+	defer BridgeType[bridgeEvent](ctx, bridgeB)()
+	go bridgeB.Run(ctx)
+
+	chB, unsubB := SubscribeEnvelopes[bridgeEvent](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, bridgeEvent{Msg: "hello"})
+	sent := scopeA.VectorClock()
+	received := <-chB
+	assert.Equal(t, "hello", received.Value.Msg)
+	assert.True(t, sent.HappensBefore(received.Clock))
+	assert.Equal(t, int64(1), scopeB.VectorClock()["a"])
+}
+
+// failingTransport fails every Send.
+type failingTransport struct {
+	pipeTransport
+}
+
+var errSendFailed = errors.New("send failed")
+
+func (f *failingTransport) Send(context.Context, string, []byte) error {
+	return errSendFailed
+}
+
+func TestBridge_SendError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type sendError struct {
+		name string
+		err  error
+	}
+	errs := make(chan sendError, 1)
+	testScope := NewEventScope()
+	bridge := NewBridge(testScope, &failingTransport{}, WithBridgeErrorHandler(func(name string, err error) {
+		errs <- sendError{name: name, err: err}
+	}))
+	defer BridgeType[bridgeEvent](ctx, bridge)()
+
+	PublishToScope(ctx, testScope, bridgeEvent{Msg: "hello"})
+
+	select {
+	case got := <-errs:
+		assert.Equal(t, typeName[bridgeEvent](), got.name)
+		assert.ErrorIs(t, got.err, errSendFailed)
+	case <-time.After(time.Second):
+		t.Fatal("send error was not reported")
+	}
+}
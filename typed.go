@@ -0,0 +1,92 @@
+package pubsub
+
+import "context"
+
+// Publisher publishes values of type T. Code that only needs to publish T can depend on a
+// Publisher instead of an *EventScope, so that tests can substitute their own implementation.
+type Publisher[T any] interface {
+	Publish(ctx context.Context, val T) error
+}
+
+// Subscriber subscribes to values of type T, like SubscribeToScope.
+type Subscriber[T any] interface {
+	Subscribe(ctx context.Context) (chan T, UnsubFn)
+}
+
+// PubSub both publishes and subscribes to values of type T.
+type PubSub[T any] interface {
+	Publisher[T]
+	Subscriber[T]
+}
+
+// scopeAdapter implements PubSub on an event scope. Go methods cannot have type parameters, so
+// the adapters are returned by functions rather than methods of EventScope.
+type scopeAdapter[T any] struct {
+	scope *EventScope
+}
+
+// AsPublisher returns a Publisher that publishes on the event scope with PublishToScope.
+func AsPublisher[T any](scope *EventScope) Publisher[T] {
+	return scopeAdapter[T]{scope: scope}
+}
+
+// AsSubscriber returns a Subscriber that subscribes to the event scope with SubscribeToScope.
+func AsSubscriber[T any](scope *EventScope) Subscriber[T] {
+	return scopeAdapter[T]{scope: scope}
+}
+
+// AsPubSub returns a PubSub that publishes and subscribes on the event scope.
+func AsPubSub[T any](scope *EventScope) PubSub[T] {
+	return scopeAdapter[T]{scope: scope}
+}
+
+func (a scopeAdapter[T]) Publish(ctx context.Context, val T) error {
+	return PublishToScope(ctx, a.scope, val)
+}
+
+func (a scopeAdapter[T]) Subscribe(ctx context.Context) (chan T, UnsubFn) {
+	return SubscribeToScope[T](ctx, a.scope)
+}
+
+// TypedScope is an event scope dedicated to values of type T, whose methods take and return T
+// without type parameters at the call site. It implements PubSub.
+type TypedScope[T any] struct {
+	scope *EventScope
+}
+
+// NewTypedScope creates a TypedScope on a new event scope created with opts.
+func NewTypedScope[T any](opts ...EventScopeOption) *TypedScope[T] {
+	return &TypedScope[T]{scope: NewEventScope(opts...)}
+}
+
+// TypedScopeOf returns a TypedScope on an existing event scope, which can be shared with the
+// typed scopes of other types and with untyped publishers and subscribers.
+func TypedScopeOf[T any](scope *EventScope) *TypedScope[T] {
+	return &TypedScope[T]{scope: scope}
+}
+
+// Scope returns the event scope the typed scope publishes and subscribes on.
+func (s *TypedScope[T]) Scope() *EventScope {
+	return s.scope
+}
+
+// Publish publishes val on the scope with PublishToScope.
+func (s *TypedScope[T]) Publish(ctx context.Context, val T) error {
+	return PublishToScope(ctx, s.scope, val)
+}
+
+// Subscribe subscribes to T on the scope with SubscribeToScope.
+func (s *TypedScope[T]) Subscribe(ctx context.Context) (chan T, UnsubFn) {
+	return SubscribeToScope[T](ctx, s.scope)
+}
+
+// SubscribeWith is like Subscribe, with subscribe options.
+func (s *TypedScope[T]) SubscribeWith(ctx context.Context, opts ...SubscribeOption) (chan T, UnsubFn) {
+	return SubscribeToScope[T](ctx, s.scope, opts...)
+}
+
+// Close closes the underlying event scope, see EventScope.Close. A scope shared with other typed
+// scopes should be closed by its owner instead.
+func (s *TypedScope[T]) Close() error {
+	return s.scope.Close()
+}
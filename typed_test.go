@@ -0,0 +1,74 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// relay republishes what it receives, depending only on the interfaces.
+func relay(ctx context.Context, from Subscriber[int], to Publisher[string]) UnsubFn {
+	ch, unsub := from.Subscribe(ctx)
+	go func() {
+		for range ch {
+			to.Publish(ctx, "relayed")
+		}
+	}()
+	return unsub
+}
+
+type recordingPublisher struct {
+	published chan string
+}
+
+func (p recordingPublisher) Publish(_ context.Context, val string) error {
+	p.published <- val
+	return nil
+}
+
+func TestAsPubSub(t *testing.T) {
+	ctx := context.Background()
+	testScope := NewEventScope()
+	ints := AsPubSub[int](testScope)
+
+	published := make(chan string, 1)
+	unsub := relay(ctx, ints, recordingPublisher{published: published})
+	defer unsub()
+
+	require.NoError(t, ints.Publish(ctx, 1))
+	assert.Equal(t, "relayed", <-published)
+
+	strs, unsubStrs := AsSubscriber[string](testScope).Subscribe(ctx)
+	defer unsubStrs()
+	require.NoError(t, AsPublisher[string](testScope).Publish(ctx, "direct"))
+	assert.Equal(t, "direct", <-strs)
+
+	assert.ErrorIs(t, AsPublisher[int](nil).Publish(ctx, 1), ErrNilScope)
+}
+
+func TestTypedScope(t *testing.T) {
+	ctx := context.Background()
+	ints := NewTypedScope[int]()
+	defer ints.Close()
+	var _ PubSub[int] = ints
+
+	ch, unsub := ints.SubscribeWith(ctx, WithOrdered())
+	defer unsub()
+	require.NoError(t, ints.Publish(ctx, 1))
+	require.NoError(t, ints.Publish(ctx, 2))
+	assert.Equal(t, 1, <-ch)
+	assert.Equal(t, 2, <-ch)
+
+	// Typed scopes of other types can share the event scope.
+	strs := TypedScopeOf[string](ints.Scope())
+	strCh, unsubStrs := strs.Subscribe(ctx)
+	defer unsubStrs()
+	require.NoError(t, PublishToScope(ctx, ints.Scope(), "shared"))
+	assert.Equal(t, "shared", <-strCh)
+	assert.Empty(t, ch)
+
+	require.NoError(t, ints.Close())
+	assert.ErrorIs(t, strs.Publish(ctx, "closed"), ErrScopeClosed)
+}
@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedType is returned when a value is published under a type that cannot be used to
+// route messages, such as a slice, map or func type.
+var ErrUnsupportedType = errors.New("pubsub: unsupported type")
+
+// Hashable checks at compile time that values of type T can be published and subscribed to.
+// Subscribing to a type that cannot be used as a topic key, such as a slice, map or func type,
+// panics at run time; declaring a variable of type Hashable[T] next to the event type makes the
+// same mistake a compile error instead:
+//
+//	var _ pubsub.Hashable[UserEvent]
+//
+// The functions of the package do not constrain their type parameters to comparable themselves,
+// since every generic function calling them with a type parameter of its own would have to carry
+// the constraint as well.
+type Hashable[T comparable] struct{}
+
+// typeOf returns the reflect.Type of T. Unlike reflect.TypeOf, it also works for interface types.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// typeName returns the name used to identify T outside of the process, for example when a
+// message is sent across a Transport.
+func typeName[T any]() string {
+	return typeOf[T]().String()
+}
+
+// typeKey returns the key that subscribers of t are registered under. It matches the key used by
+// PublishToScope and SubscribeToScope when instantiated with t.
+func typeKey(t reflect.Type) any {
+	return reflect.Zero(t).Interface()
+}
+
+// eventType describes the type a message is published as: the key its subscribers are registered
+// under, the name it is known by outside of the process and how to decode its serialized form.
+type eventType struct {
+	key    any
+	typ    reflect.Type
+	decode func(Codec, []byte) (any, error)
+}
+
+// eventTypeOf returns the eventType used when publishing values of type T.
+func eventTypeOf[T any]() eventType {
+	var zero T
+	return eventType{key: zero, typ: typeOf[T](), decode: decodeAs[T]}
+}
+
+// eventTypeFor returns the eventType used when publishing values of type t.
+func eventTypeFor(t reflect.Type) eventType {
+	return eventType{key: typeKey(t), typ: t, decode: decodeType(t)}
+}
+
+// name returns the name of the type, as returned by typeName.
+func (t eventType) name() string {
+	return t.typ.String()
+}
+
+// checkHashable panics if values of the type cannot be used as a topic key, before any of the
+// scope's locks are taken, so that a failed publish or subscribe does not leave them held. See the
+// README for the types that are affected.
+func (t eventType) checkHashable() {
+	if t.typ != nil && !t.typ.Comparable() {
+		panic("pubsub: hash of unhashable type " + t.name())
+	}
+}
+
+// decodeType returns a decode function that unmarshals data into a new value of type t.
+func decodeType(t reflect.Type) func(Codec, []byte) (any, error) {
+	return func(c Codec, data []byte) (any, error) {
+		ptr := reflect.New(t)
+		if err := c.Unmarshal(data, ptr.Interface()); err != nil {
+			return nil, err
+		}
+		return ptr.Elem().Interface(), nil
+	}
+}
+
+// publishValue publishes val under its dynamic type, as if PublishToScope had been instantiated
+// with that type. Publishing a nil value is a no-op, and values of types that are not comparable
+// are rejected with ErrUnsupportedType.
+func (e *EventScope) publishValue(ctx context.Context, val any) error {
+	t := reflect.TypeOf(val)
+	if t == nil {
+		return nil
+	}
+	if !t.Comparable() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+	return e.publish(ctx, eventTypeFor(t), e.newMessage(ctx, val))
+}
@@ -0,0 +1,153 @@
+package pubsub
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errMalformedClock is returned when an encoded vector clock cannot be decoded.
+var errMalformedClock = errors.New("pubsub: malformed vector clock")
+
+// VectorClock maps an event scope ID to the number of events that scope has published. Comparing
+// the vector clocks attached to two events establishes whether one causally precedes the other.
+type VectorClock map[string]int64
+
+// Copy returns an independent copy of the vector clock.
+func (v VectorClock) Copy() VectorClock {
+	c := make(VectorClock, len(v))
+	for id, t := range v {
+		c[id] = t
+	}
+	return c
+}
+
+// Merge updates v in place so that each entry is the maximum of its current value and the
+// matching entry in other.
+func (v VectorClock) Merge(other VectorClock) {
+	for id, t := range other {
+		if t > v[id] {
+			v[id] = t
+		}
+	}
+}
+
+// HappensBefore reports whether v causally precedes other. That is the case when no entry of v
+// is greater than the matching entry of other and at least one entry is strictly less.
+func (v VectorClock) HappensBefore(other VectorClock) bool {
+	strictlyLess := false
+	for id, t := range v {
+		o := other[id]
+		if t > o {
+			return false
+		}
+		if t < o {
+			strictlyLess = true
+		}
+	}
+	for id, o := range other {
+		if _, ok := v[id]; !ok && o > 0 {
+			strictlyLess = true
+		}
+	}
+	return strictlyLess
+}
+
+// EnableVectorClock assigns the event scope an ID and starts attaching a vector clock to every
+// message published on it. The clock is available to subscribers through SubscribeEnvelopes.
+func (e *EventScope) EnableVectorClock(id string) {
+	e.clockMu.Lock()
+	defer e.clockMu.Unlock()
+
+	e.id = id
+	if e.vclock == nil {
+		e.vclock = VectorClock{}
+	}
+}
+
+// ID returns the ID assigned to the event scope, or the empty string if none has been assigned.
+func (e *EventScope) ID() string {
+	e.clockMu.Lock()
+	defer e.clockMu.Unlock()
+
+	return e.id
+}
+
+// VectorClock returns a copy of the scope's current vector clock, or nil if the vector clock is
+// not enabled.
+func (e *EventScope) VectorClock() VectorClock {
+	e.clockMu.Lock()
+	defer e.clockMu.Unlock()
+
+	if e.vclock == nil {
+		return nil
+	}
+	return e.vclock.Copy()
+}
+
+// ObserveVectorClock merges a vector clock received from another scope into this scope's clock,
+// so that events published afterwards are ordered after the received event. It is a no-op if
+// the vector clock is not enabled.
+func (e *EventScope) ObserveVectorClock(received VectorClock) {
+	e.clockMu.Lock()
+	defer e.clockMu.Unlock()
+
+	if e.vclock == nil {
+		return
+	}
+	e.vclock.Merge(received)
+}
+
+// tickVectorClock increments the scope's own entry and returns a snapshot for the message being
+// published. It returns nil if the vector clock is not enabled.
+func (e *EventScope) tickVectorClock() VectorClock {
+	e.clockMu.Lock()
+	defer e.clockMu.Unlock()
+
+	if e.vclock == nil {
+		return nil
+	}
+	e.vclock[e.id]++
+	return e.vclock.Copy()
+}
+
+// appendVectorClock appends the binary encoding of v to b. A nil clock is encoded as an empty one.
+func appendVectorClock(b []byte, v VectorClock) []byte {
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	for id, t := range v {
+		b = binary.AppendUvarint(b, uint64(len(id)))
+		b = append(b, id...)
+		b = binary.AppendVarint(b, t)
+	}
+	return b
+}
+
+// readVectorClock decodes a vector clock written by appendVectorClock from the front of b and
+// returns the remaining bytes. An empty clock is decoded as nil.
+func readVectorClock(b []byte) (VectorClock, []byte, error) {
+	n, read := binary.Uvarint(b)
+	if read <= 0 || n > uint64(len(b)) {
+		return nil, nil, errMalformedClock
+	}
+	b = b[read:]
+
+	var v VectorClock
+	if n > 0 {
+		v = make(VectorClock, n)
+	}
+	for i := uint64(0); i < n; i++ {
+		l, read := binary.Uvarint(b)
+		if read <= 0 || l > uint64(len(b)-read) {
+			return nil, nil, errMalformedClock
+		}
+		id := string(b[read : read+int(l)])
+		b = b[read+int(l):]
+
+		t, read := binary.Varint(b)
+		if read <= 0 {
+			return nil, nil, errMalformedClock
+		}
+		b = b[read:]
+		v[id] = t
+	}
+	return v, b, nil
+}
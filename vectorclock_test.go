@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectorClock_HappensBefore(t *testing.T) {
+	a := VectorClock{"a": 1}
+	b := VectorClock{"a": 1, "b": 1}
+	c := VectorClock{"a": 2}
+
+	assert.True(t, a.HappensBefore(b))
+	assert.True(t, a.HappensBefore(c))
+	assert.False(t, b.HappensBefore(a))
+	assert.False(t, b.HappensBefore(c))
+	assert.False(t, c.HappensBefore(b))
+	assert.False(t, a.HappensBefore(a))
+}
+
+func TestVectorClock_AcrossScopes(t *testing.T) {
+	ctx := context.Background()
+	scopeA := NewEventScope()
+	scopeA.EnableVectorClock("a")
+	scopeB := NewEventScope()
+	scopeB.EnableVectorClock("b")
+
+	chA, unsubA := SubscribeEnvelopes[int](ctx, scopeA)
+	defer unsubA()
+	chB, unsubB := SubscribeEnvelopes[string](ctx, scopeB)
+	defer unsubB()
+
+	PublishToScope(ctx, scopeA, 1)
+	first := <-chA
+	assert.Equal(t, VectorClock{"a": 1}, first.Clock)
+
+	scopeB.ObserveVectorClock(first.Clock)
+	PublishToScope(ctx, scopeB, "reply")
+	second := <-chB
+	assert.Equal(t, VectorClock{"a": 1, "b": 1}, second.Clock)
+
+	assert.True(t, first.Clock.HappensBefore(second.Clock))
+	assert.False(t, second.Clock.HappensBefore(first.Clock))
+}
+
+func TestVectorClock_Encoding(t *testing.T) {
+	clock := VectorClock{"a": 3, "b": 1}
+	decoded, rest, err := readVectorClock(append(appendVectorClock(nil, clock), "data"...))
+	assert.NoError(t, err)
+	assert.Equal(t, clock, decoded)
+	assert.Equal(t, "data", string(rest))
+
+	decoded, _, err = readVectorClock(appendVectorClock(nil, nil))
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	_, _, err = readVectorClock([]byte{5, 1})
+	assert.Error(t, err)
+}